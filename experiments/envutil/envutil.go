@@ -0,0 +1,39 @@
+// Package envutil reads experiment settings from environment variables, falling back to a default
+// when a variable is unset or can't be parsed.
+package envutil
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+func Get(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func Int(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+// Duration accepts a Go duration string ("500ms", "2m") or, as the experiments always have, a bare
+// integer number of seconds
+func Duration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,66 @@
+package envutil
+
+import (
+	"testing"
+	"time"
+)
+
+const testKey = "ENVUTIL_TEST_VALUE"
+
+func TestDuration(t *testing.T) {
+	const defaultValue = 42 * time.Second
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{name: "unset", value: "", want: defaultValue},
+		{name: "bare seconds", value: "30", want: 30 * time.Second},
+		{name: "zero seconds", value: "0", want: 0},
+		{name: "milliseconds", value: "500ms", want: 500 * time.Millisecond},
+		{name: "minutes", value: "2m", want: 2 * time.Minute},
+		{name: "compound", value: "1m30s", want: 90 * time.Second},
+		{name: "fractional", value: "1.5s", want: 1500 * time.Millisecond},
+		{name: "missing unit on a fraction", value: "1.5", want: defaultValue},
+		{name: "garbage", value: "soon", want: defaultValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(testKey, tt.value)
+			if got := Duration(testKey, defaultValue); got != tt.want {
+				t.Errorf("Duration(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{value: "", want: 7},
+		{value: "12", want: 12},
+		{value: "-3", want: -3},
+		{value: "12s", want: 7},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(testKey, tt.value)
+		if got := Int(testKey, 7); got != tt.want {
+			t.Errorf("Int(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	t.Setenv(testKey, "")
+	if got := Get(testKey, "fallback"); got != "fallback" {
+		t.Errorf("Get of an empty variable = %q, want the default", got)
+	}
+	t.Setenv(testKey, "value")
+	if got := Get(testKey, "fallback"); got != "value" {
+		t.Errorf("Get = %q, want %q", got, "value")
+	}
+}
@@ -0,0 +1,3 @@
+module example.com/envutil
+
+go 1.24.6
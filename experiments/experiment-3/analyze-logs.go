@@ -2,9 +2,14 @@ package main
 
 import (
 	"bufio"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"html/template"
 	"math"
 	"os"
+	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
@@ -28,9 +33,17 @@ type LeaderChange struct {
 }
 
 type PartitionInfo struct {
-	Pod   int
-	Term  int
-	State string
+	Pod      string // leader pod name, "unknown" when its member index wasn't reported; empty when NoLeader
+	Term     int
+	State    string
+	NoLeader bool
+}
+
+// LeaderlessStats is how long a partition spent without a leader over the run
+type LeaderlessStats struct {
+	Windows int
+	Total   time.Duration
+	Longest time.Duration
 }
 
 type WriteOperation struct {
@@ -40,7 +53,9 @@ type WriteOperation struct {
 	Duration  time.Duration
 	Success   bool
 	Error     string
+	Category  string // failure category logged by the writer; empty for successes
 	SeqNum    int
+	Partition int
 }
 
 type ReadOperation struct {
@@ -50,11 +65,39 @@ type ReadOperation struct {
 	Duration  time.Duration
 	Success   bool
 	Error     string
+	Category  string // failure category of a READ_FAILED; empty otherwise
 	Expected  string
 	SeqNum    int
+	Partition int
+	// SeqDistance is how many writes behind Expected the returned Value was, for inconsistent
+	// reads; -1 when either value carries no sequence number
+	SeqDistance int
+}
+
+// InterleavingAnomaly is a read whose value contradicts the write log: it completed before the write
+// that produced the value had started, or no successful write produced the value at all
+type InterleavingAnomaly struct {
+	Timestamp time.Time
+	Key       string
+	Value     string
+	Kind      string // anomalyReadBeforeWrite or anomalyUnwrittenValue
+}
+
+const (
+	anomalyReadBeforeWrite = "read-before-write"
+	anomalyUnwrittenValue  = "unwritten-value"
+)
+
+type StaleRead struct {
+	Timestamp   time.Time
+	Key         string
+	Got         string
+	Expected    string
+	SeqDistance int
 }
 
 type AnalysisResult struct {
+	Name               string
 	TotalWrites        int
 	SuccessfulWrites   int
 	FailedWrites       int
@@ -71,8 +114,31 @@ type AnalysisResult struct {
 	WriteGaps          []int
 	FailoverEvents     []FailoverEvent
 	TestDuration       time.Duration
+	WriteThroughput    float64 // operations per second over TestDuration
+	ReadThroughput     float64
 	BaselinePerf       PerformanceMetrics
 	FailoverPerf       PerformanceMetrics
+	PartitionLatency   map[int]PartitionLatency
+	StaleReads         []StaleRead
+	MaxStaleness       int
+	// Leaderless time per partition; a window still open when the log ends is closed at the last timestamp
+	Leaderless                 map[int]LeaderlessStats
+	LongestLeaderless          time.Duration
+	LongestLeaderlessPartition int
+	// Failed operations per error category (Unavailable, DeadlineExceeded, NotFound, Conflict, Unknown);
+	// logs from before categories were logged count as Unknown. Inconsistent reads aren't included.
+	WriteFailureCategories map[string]int
+	ReadFailureCategories  map[string]int
+
+	// Reads that don't fit the write log, counted by kind; see detectInterleavingAnomalies
+	InterleavingAnomalies []InterleavingAnomaly
+	ReadsBeforeWrite      int
+	UnwrittenValueReads   int
+
+	// Parsed operations and leader changes, kept for the CSV exports
+	Writes          []WriteOperation `json:"-"`
+	Reads           []ReadOperation  `json:"-"`
+	LeaderChangeLog []LeaderChange   `json:"-"`
 }
 
 type LatencyStats struct {
@@ -85,6 +151,14 @@ type LatencyStats struct {
 	StdDev     time.Duration
 }
 
+// PartitionLatency is the latency of operations on keys that hash to one partition
+type PartitionLatency struct {
+	Writes       int
+	Reads        int
+	WriteLatency LatencyStats
+	ReadLatency  LatencyStats
+}
+
 type PerformanceMetrics struct {
 	WriteLatency LatencyStats
 	ReadLatency  LatencyStats
@@ -103,17 +177,62 @@ type FailoverEvent struct {
 
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run analyze-logs.go <log-file-path>")
-		fmt.Println("Example: go run analyze-logs.go failover-test-results.log")
+		fmt.Println("Usage: go run analyze-logs.go <log-file-path> [-format=text|json] [-partitions=N] [-window=1s] [-html]")
+		fmt.Println("       go run analyze-logs.go -compare <log-file-path> <log-file-path>... [-window=1s] [-html]")
+		fmt.Println("Example: go run analyze-logs.go failover-test-results.log -format=json")
 		os.Exit(1)
 	}
 
+	if os.Args[1] == "-compare" {
+		if len(os.Args) < 4 {
+			fmt.Println("-compare needs at least two log files")
+			os.Exit(1)
+		}
+		runComparison(os.Args[2:])
+		return
+	}
+
 	logFile := os.Args[1]
-	
+
+	format := "text"
+	partitionCount := 0
+	window := time.Second
+	html := false
+	for _, arg := range os.Args[2:] {
+		switch {
+		case arg == "-html":
+			html = true
+		case strings.HasPrefix(arg, "-format="):
+			format = strings.TrimPrefix(arg, "-format=")
+		case strings.HasPrefix(arg, "-partitions="):
+			n, err := strconv.Atoi(strings.TrimPrefix(arg, "-partitions="))
+			if err != nil || n <= 0 {
+				fmt.Printf("Invalid partition count '%s'\n", arg)
+				os.Exit(1)
+			}
+			partitionCount = n
+		case strings.HasPrefix(arg, "-window="):
+			d, err := time.ParseDuration(strings.TrimPrefix(arg, "-window="))
+			if err != nil || d <= 0 {
+				fmt.Printf("Invalid timeline window '%s'\n", arg)
+				os.Exit(1)
+			}
+			window = d
+		default:
+			fmt.Printf("Unknown argument '%s'\n", arg)
+			os.Exit(1)
+		}
+	}
+	if format != "text" && format != "json" {
+		fmt.Printf("Unknown format '%s'. Valid options: 'text', 'json'\n", format)
+		os.Exit(1)
+	}
+
 	fmt.Printf("Analyzing Atomix Failover Test Logs: %s\n", logFile)
 	fmt.Println("=" + strings.Repeat("=", 60))
 	
 	analyzer := NewLogAnalyzer()
+	analyzer.partitionCount = partitionCount
 	result, err := analyzer.AnalyzeLog(logFile)
 	if err != nil {
 		fmt.Printf("Error analyzing log: %v\n", err)
@@ -121,7 +240,41 @@ func main() {
 	}
 
 	analyzer.PrintSummary(result)
-	
+
+	csvFile := strings.TrimSuffix(logFile, ".log") + "-operations.csv"
+	if err := analyzer.generateCSV(result, csvFile); err != nil {
+		fmt.Printf("Warning: Could not write CSV file: %v\n", err)
+	} else {
+		fmt.Printf("\nCSV file generated: %s\n", csvFile)
+	}
+
+	timelineFile := strings.TrimSuffix(logFile, ".log") + "-timeline.csv"
+	if err := analyzer.generateTimelineCSV(result, window, timelineFile); err != nil {
+		fmt.Printf("Warning: Could not write timeline CSV: %v\n", err)
+	} else {
+		fmt.Printf("Timeline CSV generated: %s\n", timelineFile)
+	}
+
+	if html {
+		htmlFile := strings.TrimSuffix(logFile, ".log") + "-report.html"
+		if err := analyzer.WriteHTMLReport([]*AnalysisResult{result}, window, htmlFile); err != nil {
+			fmt.Printf("Warning: Could not write HTML report: %v\n", err)
+		} else {
+			fmt.Printf("HTML report generated: %s\n", htmlFile)
+		}
+	}
+
+	if format == "json" {
+		outputFile := strings.TrimSuffix(logFile, ".log") + "-analysis.json"
+		err = analyzer.WriteJSONReport(result, outputFile)
+		if err != nil {
+			fmt.Printf("Warning: Could not write JSON report: %v\n", err)
+		} else {
+			fmt.Printf("\nJSON analysis report written to: %s\n", outputFile)
+		}
+		return
+	}
+
 	outputFile := strings.TrimSuffix(logFile, ".log") + "-analysis.txt"
 	err = analyzer.WriteDetailedReport(result, outputFile)
 	if err != nil {
@@ -136,17 +289,22 @@ type LogAnalyzer struct {
 	writeRegex       *regexp.Regexp
 	readRegex        *regexp.Regexp
 	leaderRegex      *regexp.Regexp
+	noLeaderRegex    *regexp.Regexp
 	durationRegex    *regexp.Regexp
 	seqRegex         *regexp.Regexp
+
+	// partitionCount is the number of partitions keys are hashed across; 0 infers it from LEADER_CHANGE lines
+	partitionCount int
 }
 
 func NewLogAnalyzer() *LogAnalyzer {
 	return &LogAnalyzer{
 		timestampRegex: regexp.MustCompile(`\[(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d{3})\]`),
-		writeRegex:     regexp.MustCompile(`WRITE_(SUCCESS|FAILED): (seq-\d+) -> (.+?) \(duration: ([^,)]+)(?:, error: (.+))?\)`),
-		readRegex:      regexp.MustCompile(`READ_(SUCCESS|FAILED|INCONSISTENT): (seq-\d+)(?: -> (.+?))? \(duration: ([^,)]+)(?:, error: (.+))?\)`),
+		writeRegex:     regexp.MustCompile(`WRITE_(SUCCESS|FAILED): (seq-\d+) -> (.+?) \(duration: ([^,)]+)(?:, category: (\w+))?(?:, error: (.+))?\)`),
+		readRegex:      regexp.MustCompile(`READ_(SUCCESS|FAILED|INCONSISTENT): (seq-\d+)(?: -> (.+?))? \(duration: ([^,)]+)(?:, category: (\w+))?(?:, error: (.+))?\)`),
 		leaderRegex:    regexp.MustCompile(`LEADER_CHANGE: (.+)`),
-		durationRegex:  regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|s|µs|ns)`),
+		noLeaderRegex:  regexp.MustCompile(`Partition (\d+): No Leader`),
+		durationRegex:  regexp.MustCompile(`(\d+(?:\.\d+)?)(ms|µs|us|ns|s)`),
 		seqRegex:       regexp.MustCompile(`seq-(\d+)`),
 	}
 }
@@ -166,13 +324,54 @@ func (la *LogAnalyzer) AnalyzeLog(filename string) (*AnalysisResult, error) {
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
-		
+
+		var event *jsonEvent
 		timestamp := la.extractTimestamp(line)
+		if strings.HasPrefix(line, "{") {
+			if event = parseJSONEvent(line); event != nil {
+				timestamp = event.Timestamp
+			}
+		}
 		if startTime.IsZero() {
 			startTime = timestamp
 		}
 		endTime = timestamp
 
+		if event != nil {
+			switch event.EventType {
+			case "WRITE_SUCCESS", "WRITE_FAILED":
+				writes = append(writes, WriteOperation{
+					Timestamp: timestamp,
+					Key:       event.Key,
+					Value:     event.Value,
+					Duration:  time.Duration(event.DurationNs),
+					Success:   event.EventType == "WRITE_SUCCESS",
+					Error:     event.Error,
+					Category:  event.Category,
+					SeqNum:    la.extractSeqNum(event.Key),
+				})
+			case "READ_SUCCESS", "READ_FAILED", "READ_INCONSISTENT":
+				reads = append(reads, ReadOperation{
+					Timestamp: timestamp,
+					Key:       event.Key,
+					Value:     event.Value,
+					Duration:  time.Duration(event.DurationNs),
+					Success:   event.EventType == "READ_SUCCESS",
+					Error:     event.Error,
+					Category:  event.Category,
+					Expected:  event.Expected,
+					SeqNum:    la.extractSeqNum(event.Key),
+
+					SeqDistance: la.seqDistance(event.Value, event.Expected),
+				})
+			case "LEADER_CHANGE":
+				if leader := la.parseLeaderChange("LEADER_CHANGE: "+event.Message, timestamp); leader != nil {
+					leaderChanges = append(leaderChanges, *leader)
+				}
+			}
+			continue
+		}
+
 		if write := la.parseWrite(line, timestamp); write != nil {
 			writes = append(writes, *write)
 		} else if read := la.parseRead(line, timestamp); read != nil {
@@ -186,7 +385,59 @@ func (la *LogAnalyzer) AnalyzeLog(filename string) (*AnalysisResult, error) {
 		return nil, fmt.Errorf("error reading file: %v", err)
 	}
 
-	return la.generateAnalysis(writes, reads, leaderChanges, startTime, endTime), nil
+	partitionCount := la.partitionCount
+	if partitionCount == 0 {
+		partitionCount = inferPartitionCount(leaderChanges)
+	}
+	for i := range writes {
+		writes[i].Partition = keyPartition(writes[i].Key, partitionCount)
+	}
+	for i := range reads {
+		reads[i].Partition = keyPartition(reads[i].Key, partitionCount)
+	}
+
+	result := la.generateAnalysis(writes, reads, leaderChanges, startTime, endTime)
+	result.Name = strings.TrimSuffix(filepath.Base(filename), ".log")
+	return result, nil
+}
+
+// keyPartition uses the same sha256-mod mapping as experiment-4's getKeyPartition, offset by one
+// so partitions line up with the RaftGroup numbering in LEADER_CHANGE lines
+func keyPartition(key string, partitionCount int) int {
+	hash := sha256.Sum256([]byte(key))
+	return int(hash[0])%partitionCount + 1
+}
+
+func inferPartitionCount(leaderChanges []LeaderChange) int {
+	count := 0
+	for _, change := range leaderChanges {
+		count = max(count, len(change.Partitions))
+	}
+	if count == 0 {
+		return 3
+	}
+	return count
+}
+
+// jsonEvent is one line of a LOG_FORMAT=jsonl log; operation events carry their details as fields
+type jsonEvent struct {
+	Timestamp  time.Time `json:"timestamp"`
+	EventType  string    `json:"event_type"`
+	Message    string    `json:"message"`
+	Key        string    `json:"key"`
+	Value      string    `json:"value"`
+	Expected   string    `json:"expected"`
+	DurationNs int64     `json:"duration_ns"`
+	Error      string    `json:"error"`
+	Category   string    `json:"category"`
+}
+
+func parseJSONEvent(line string) *jsonEvent {
+	var event jsonEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return nil
+	}
+	return &event
 }
 
 func (la *LogAnalyzer) extractTimestamp(line string) time.Time {
@@ -212,18 +463,14 @@ func (la *LogAnalyzer) parseWrite(line string, timestamp time.Time) *WriteOperat
 	duration := la.parseDuration(matches[4])
 	success := matches[1] == "SUCCESS"
 	
-	var errorMsg string
-	if len(matches) > 5 {
-		errorMsg = matches[5]
-	}
-
 	return &WriteOperation{
 		Timestamp: timestamp,
 		Key:       matches[2],
 		Value:     matches[3],
 		Duration:  duration,
 		Success:   success,
-		Error:     errorMsg,
+		Error:     matches[6],
+		Category:  matches[5],
 		SeqNum:    seqNum,
 	}
 }
@@ -242,18 +489,14 @@ func (la *LogAnalyzer) parseRead(line string, timestamp time.Time) *ReadOperatio
 	duration := la.parseDuration(matches[4])
 	success := matches[1] == "SUCCESS"
 	
-	var errorMsg string
-	if len(matches) > 5 {
-		errorMsg = matches[5]
-	}
-
 	return &ReadOperation{
 		Timestamp: timestamp,
 		Key:       matches[2],
 		Value:     matches[3],
 		Duration:  duration,
 		Success:   success,
-		Error:     errorMsg,
+		Error:     matches[6],
+		Category:  matches[5],
 		SeqNum:    seqNum,
 	}
 }
@@ -276,9 +519,26 @@ func (la *LogAnalyzer) parseInconsistentRead(line string, timestamp time.Time) *
 		Success:   false,
 		Expected:  matches[3],
 		SeqNum:    seqNum,
+
+		SeqDistance: la.seqDistance(matches[2], matches[3]),
 	}
 }
 
+var valueSeqRegex = regexp.MustCompile(`^value-(\d+)-`)
+
+// seqDistance returns how many sequence numbers the got value trails the expected one,
+// or -1 if either isn't a "value-<seq>-<unix>" value written by the experiment
+func (la *LogAnalyzer) seqDistance(got, expected string) int {
+	gotMatch := valueSeqRegex.FindStringSubmatch(got)
+	expectedMatch := valueSeqRegex.FindStringSubmatch(expected)
+	if gotMatch == nil || expectedMatch == nil {
+		return -1
+	}
+	gotSeq, _ := strconv.Atoi(gotMatch[1])
+	expectedSeq, _ := strconv.Atoi(expectedMatch[1])
+	return expectedSeq - gotSeq
+}
+
 func (la *LogAnalyzer) parseLeaderChange(line string, timestamp time.Time) *LeaderChange {
 	matches := la.leaderRegex.FindStringSubmatch(line)
 	if len(matches) < 2 {
@@ -286,18 +546,22 @@ func (la *LogAnalyzer) parseLeaderChange(line string, timestamp time.Time) *Lead
 	}
 
 	partitions := make(map[int]PartitionInfo)
-	partitionRegex := regexp.MustCompile(`Partition (\d+): Pod (\d+) \(term: (\d+), (\w+)\)`)
+	partitionRegex := regexp.MustCompile(`Partition (\d+): Pod (\S+) \(term: (\d+), (\w+)\)`)
 	partitionMatches := partitionRegex.FindAllStringSubmatch(matches[1], -1)
+
+	for _, match := range la.noLeaderRegex.FindAllStringSubmatch(matches[1], -1) {
+		partNum, _ := strconv.Atoi(match[1])
+		partitions[partNum] = PartitionInfo{NoLeader: true}
+	}
 	
 	for _, match := range partitionMatches {
 		if len(match) >= 5 {
 			partNum, _ := strconv.Atoi(match[1])
-			podNum, _ := strconv.Atoi(match[2])
 			term, _ := strconv.Atoi(match[3])
 			state := match[4]
 			
 			partitions[partNum] = PartitionInfo{
-				Pod:   podNum,
+				Pod:   match[2],
 				Term:  term,
 				State: state,
 			}
@@ -346,12 +610,16 @@ func (la *LogAnalyzer) parseDuration(durationStr string) time.Duration {
 }
 
 func (la *LogAnalyzer) generateAnalysis(writes []WriteOperation, reads []ReadOperation, leaderChanges []LeaderChange, startTime, endTime time.Time) *AnalysisResult {
-	result := &AnalysisResult{}
+	result := &AnalysisResult{Writes: writes, Reads: reads, LeaderChangeLog: leaderChanges}
 	
 	result.TotalWrites = len(writes)
 	result.TotalReads = len(reads)
 	result.LeaderChanges = len(leaderChanges)
 	result.TestDuration = endTime.Sub(startTime)
+	if seconds := result.TestDuration.Seconds(); seconds > 0 {
+		result.WriteThroughput = float64(result.TotalWrites) / seconds
+		result.ReadThroughput = float64(result.TotalReads) / seconds
+	}
 
 	successfulWrites := 0
 	failedWrites := 0
@@ -362,6 +630,7 @@ func (la *LogAnalyzer) generateAnalysis(writes []WriteOperation, reads []ReadOpe
 			successfulWrites++
 		} else {
 			failedWrites++
+			result.WriteFailureCategories = countCategory(result.WriteFailureCategories, write.Category)
 		}
 		writeDurations = append(writeDurations, write.Duration)
 	}
@@ -384,6 +653,18 @@ func (la *LogAnalyzer) generateAnalysis(writes []WriteOperation, reads []ReadOpe
 			failedReads++
 			if read.Expected != "" {
 				inconsistentReads++
+				result.StaleReads = append(result.StaleReads, StaleRead{
+					Timestamp:   read.Timestamp,
+					Key:         read.Key,
+					Got:         read.Value,
+					Expected:    read.Expected,
+					SeqDistance: read.SeqDistance,
+				})
+				if read.SeqDistance > result.MaxStaleness {
+					result.MaxStaleness = read.SeqDistance
+				}
+			} else {
+				result.ReadFailureCategories = countCategory(result.ReadFailureCategories, read.Category)
 			}
 		}
 		readDurations = append(readDurations, read.Duration)
@@ -405,13 +686,137 @@ func (la *LogAnalyzer) generateAnalysis(writes []WriteOperation, reads []ReadOpe
 	}
 
 	result.WriteGaps = la.detectSequenceGaps(writes)
+	result.InterleavingAnomalies = la.detectInterleavingAnomalies(writes, reads)
+	for _, anomaly := range result.InterleavingAnomalies {
+		if anomaly.Kind == anomalyReadBeforeWrite {
+			result.ReadsBeforeWrite++
+		} else {
+			result.UnwrittenValueReads++
+		}
+	}
 	result.FailoverEvents = la.detectFailoverEvents(writes, reads, leaderChanges)
 	
 	result.BaselinePerf, result.FailoverPerf = la.calculatePerformanceComparison(writes, reads, leaderChanges)
+	result.PartitionLatency = la.calculatePartitionLatency(writes, reads)
+
+	result.Leaderless = la.calculateLeaderless(leaderChanges, endTime)
+	for _, id := range sortedLeaderless(result.Leaderless) {
+		if stats := result.Leaderless[id]; stats.Longest > result.LongestLeaderless {
+			result.LongestLeaderless = stats.Longest
+			result.LongestLeaderlessPartition = id
+		}
+	}
 
 	return result
 }
 
+// countCategory adds one failure to counts, allocating it on first use
+func countCategory(counts map[string]int, category string) map[string]int {
+	if category == "" {
+		category = "Unknown"
+	}
+	if counts == nil {
+		counts = make(map[string]int)
+	}
+	counts[category]++
+	return counts
+}
+
+// formatCategories lists failure counts by category, most frequent first
+func formatCategories(counts map[string]int) string {
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s %d", category, counts[category])
+	}
+	return strings.Join(parts, ", ")
+}
+
+// calculateLeaderless measures, per partition, the windows between a LEADER_CHANGE reporting
+// "No Leader" and the next one reporting a leader. Partitions missing from a snapshot keep their state.
+func (la *LogAnalyzer) calculateLeaderless(leaderChanges []LeaderChange, endTime time.Time) map[int]LeaderlessStats {
+	stats := make(map[int]LeaderlessStats)
+	since := make(map[int]time.Time) // start of each partition's open leaderless window
+
+	closeWindow := func(partition int, at time.Time) {
+		window := at.Sub(since[partition])
+		delete(since, partition)
+		s := stats[partition]
+		s.Windows++
+		s.Total += window
+		s.Longest = max(s.Longest, window)
+		stats[partition] = s
+	}
+
+	for _, change := range leaderChanges {
+		for partition, info := range change.Partitions {
+			_, open := since[partition]
+			if info.NoLeader && !open {
+				since[partition] = change.Timestamp
+			} else if !info.NoLeader && open {
+				closeWindow(partition, change.Timestamp)
+			}
+		}
+	}
+	for partition := range since {
+		closeWindow(partition, endTime)
+	}
+	return stats
+}
+
+func sortedLeaderless(leaderless map[int]LeaderlessStats) []int {
+	ids := make([]int, 0, len(leaderless))
+	for id := range leaderless {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (la *LogAnalyzer) calculatePartitionLatency(writes []WriteOperation, reads []ReadOperation) map[int]PartitionLatency {
+	writeDurations := make(map[int][]time.Duration)
+	readDurations := make(map[int][]time.Duration)
+	for _, write := range writes {
+		writeDurations[write.Partition] = append(writeDurations[write.Partition], write.Duration)
+	}
+	for _, read := range reads {
+		readDurations[read.Partition] = append(readDurations[read.Partition], read.Duration)
+	}
+
+	partitions := make(map[int]PartitionLatency)
+	for partition, durations := range writeDurations {
+		stats := partitions[partition]
+		stats.Writes = len(durations)
+		stats.WriteLatency = la.calculateLatencyStats(durations)
+		partitions[partition] = stats
+	}
+	for partition, durations := range readDurations {
+		stats := partitions[partition]
+		stats.Reads = len(durations)
+		stats.ReadLatency = la.calculateLatencyStats(durations)
+		partitions[partition] = stats
+	}
+	return partitions
+}
+
+func sortedPartitions(partitions map[int]PartitionLatency) []int {
+	ids := make([]int, 0, len(partitions))
+	for id := range partitions {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
 func (la *LogAnalyzer) calculateLatencyStats(durations []time.Duration) LatencyStats {
 	if len(durations) == 0 {
 		return LatencyStats{}
@@ -440,17 +845,29 @@ func (la *LogAnalyzer) calculateLatencyStats(durations []time.Duration) LatencyS
 		Max:    durations[len(durations)-1],
 		Mean:   mean,
 		Median: durations[len(durations)/2],
+		P95:    percentile(durations, 0.95),
+		P99:    percentile(durations, 0.99),
 		StdDev: stdDev,
 	}
 
-	if len(durations) > 1 {
-		p95Index := int(0.95 * float64(len(durations)))
-		p99Index := int(0.99 * float64(len(durations)))
-		stats.P95 = durations[p95Index]
-		stats.P99 = durations[p99Index]
+	return stats
+}
+
+// percentile linearly interpolates between the two nearest ranks of a sorted slice
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
 	}
 
-	return stats
+	weight := rank - float64(lower)
+	return sorted[lower] + time.Duration(weight*float64(sorted[upper]-sorted[lower]))
 }
 
 func (la *LogAnalyzer) detectSequenceGaps(writes []WriteOperation) []int {
@@ -476,6 +893,39 @@ func (la *LogAnalyzer) detectSequenceGaps(writes []WriteOperation) []int {
 	return gaps
 }
 
+// detectInterleavingAnomalies cross-checks every read that returned a value against the successful
+// writes. Values carry their sequence number and write time, so each one identifies a single write.
+// Log timestamps are completion times, so a read only counts as before its write when it completed
+// before the write started; overlapping operations are legitimately concurrent. A value no
+// successful write produced usually means a write that logged a failure was applied anyway.
+func (la *LogAnalyzer) detectInterleavingAnomalies(writes []WriteOperation, reads []ReadOperation) []InterleavingAnomaly {
+	written := make(map[string]WriteOperation)
+	for _, write := range writes {
+		if _, seen := written[write.Value]; write.Success && !seen {
+			written[write.Value] = write
+		}
+	}
+
+	var anomalies []InterleavingAnomaly
+	for _, read := range reads {
+		if read.Value == "" || (!read.Success && read.Expected == "") {
+			continue
+		}
+		anomaly := InterleavingAnomaly{Timestamp: read.Timestamp, Key: read.Key, Value: read.Value}
+		write, ok := written[read.Value]
+		switch {
+		case !ok:
+			anomaly.Kind = anomalyUnwrittenValue
+		case !read.Timestamp.IsZero() && !write.Timestamp.IsZero() && read.Timestamp.Before(write.Timestamp.Add(-write.Duration)):
+			anomaly.Kind = anomalyReadBeforeWrite
+		default:
+			continue
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies
+}
+
 func (la *LogAnalyzer) detectFailoverEvents(writes []WriteOperation, reads []ReadOperation, leaderChanges []LeaderChange) []FailoverEvent {
 	var events []FailoverEvent
 	
@@ -597,11 +1047,15 @@ func (la *LogAnalyzer) PrintSummary(result *AnalysisResult) {
 	
 	fmt.Printf("Test Duration: %v\n", result.TestDuration)
 	fmt.Printf("Leader Changes Detected: %d\n", result.LeaderChanges)
+	fmt.Printf("Throughput: %.1f writes/sec, %.1f reads/sec\n", result.WriteThroughput, result.ReadThroughput)
 	
 	fmt.Println("\nWRITE OPERATIONS:")
 	fmt.Printf("  Total Writes: %d\n", result.TotalWrites)
 	fmt.Printf("  Successful: %d (%.2f%%)\n", result.SuccessfulWrites, result.WriteSuccessRate)
 	fmt.Printf("  Failed: %d\n", result.FailedWrites)
+	if len(result.WriteFailureCategories) > 0 {
+		fmt.Printf("    By category: %s\n", formatCategories(result.WriteFailureCategories))
+	}
 	if len(result.WriteGaps) > 0 {
 		fmt.Printf("  ❌ SEQUENCE GAPS DETECTED: %d missing sequences\n", len(result.WriteGaps))
 		fmt.Printf("     Missing sequences: %v\n", result.WriteGaps[:min(10, len(result.WriteGaps))])
@@ -613,7 +1067,19 @@ func (la *LogAnalyzer) PrintSummary(result *AnalysisResult) {
 	fmt.Printf("  Total Reads: %d\n", result.TotalReads)
 	fmt.Printf("  Successful: %d (%.2f%%)\n", result.SuccessfulReads, result.ReadSuccessRate)
 	fmt.Printf("  Failed: %d\n", result.FailedReads)
+	if len(result.ReadFailureCategories) > 0 {
+		fmt.Printf("    By category: %s\n", formatCategories(result.ReadFailureCategories))
+	}
 	fmt.Printf("  Inconsistent: %d\n", result.InconsistentReads)
+	if result.InconsistentReads > 0 {
+		fmt.Printf("  Max Staleness: %d sequence numbers\n", result.MaxStaleness)
+	}
+	if len(result.InterleavingAnomalies) > 0 {
+		fmt.Printf("  ❌ INTERLEAVING ANOMALIES: %d read(s) completed before their write, %d read(s) of values never successfully written\n",
+			result.ReadsBeforeWrite, result.UnwrittenValueReads)
+	} else {
+		fmt.Printf("  ✅ NO INTERLEAVING ANOMALIES: Every value read follows its write\n")
+	}
 	if result.ConsistencyRate >= 100 {
 		fmt.Printf("  ✅ LINEARIZABILITY: %.2f%% (Perfect consistency)\n", result.ConsistencyRate)
 	} else if result.ConsistencyRate >= 99 {
@@ -625,7 +1091,28 @@ func (la *LogAnalyzer) PrintSummary(result *AnalysisResult) {
 	fmt.Println("\nPERFORMANCE METRICS:")
 	la.printLatencyStats("Write Latency", result.WriteLatency)
 	la.printLatencyStats("Read Latency", result.ReadLatency)
+
+	if len(result.PartitionLatency) > 0 {
+		fmt.Println("\nPER-PARTITION LATENCY:")
+		for _, id := range sortedPartitions(result.PartitionLatency) {
+			stats := result.PartitionLatency[id]
+			fmt.Printf("  Partition %d: %d writes (mean %v, P99 %v), %d reads (mean %v, P99 %v)\n",
+				id, stats.Writes, stats.WriteLatency.Mean, stats.WriteLatency.P99,
+				stats.Reads, stats.ReadLatency.Mean, stats.ReadLatency.P99)
+		}
+	}
 	
+	fmt.Println("\nLEADER AVAILABILITY:")
+	if len(result.Leaderless) == 0 {
+		fmt.Printf("  ✅ No leaderless windows observed\n")
+	} else {
+		fmt.Printf("  Longest Leaderless Window: %v (partition %d)\n", result.LongestLeaderless, result.LongestLeaderlessPartition)
+		for _, id := range sortedLeaderless(result.Leaderless) {
+			stats := result.Leaderless[id]
+			fmt.Printf("  Partition %d: %v leaderless over %d window(s), longest %v\n", id, stats.Total, stats.Windows, stats.Longest)
+		}
+	}
+
 	if len(result.FailoverEvents) > 0 {
 		fmt.Printf("\nFAILOVER IMPACT ANALYSIS:\n")
 		fmt.Printf("  Failover Events: %d\n", len(result.FailoverEvents))
@@ -695,19 +1182,27 @@ func (la *LogAnalyzer) WriteDetailedReport(result *AnalysisResult, filename stri
 
 	fmt.Fprintf(file, "ATOMIX FAILOVER TEST - DETAILED ANALYSIS REPORT\n")
 	fmt.Fprintf(file, "Generated: %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Fprintf(file, "="+ strings.Repeat("=", 70) + "\n\n")
+	fmt.Fprint(file, "="+ strings.Repeat("=", 70) + "\n\n")
 
 	fmt.Fprintf(file, "EXECUTIVE SUMMARY\n")
-	fmt.Fprintf(file, "-" + strings.Repeat("-", 20) + "\n")
+	fmt.Fprint(file, "-" + strings.Repeat("-", 20) + "\n")
 	fmt.Fprintf(file, "Test Duration: %v\n", result.TestDuration)
 	fmt.Fprintf(file, "Total Operations: %d writes, %d reads\n", result.TotalWrites, result.TotalReads)
 	fmt.Fprintf(file, "Leader Changes: %d\n", result.LeaderChanges)
+	fmt.Fprintf(file, "Throughput: %.1f writes/sec, %.1f reads/sec\n", result.WriteThroughput, result.ReadThroughput)
 	fmt.Fprintf(file, "Write Success Rate: %.2f%%\n", result.WriteSuccessRate)
 	fmt.Fprintf(file, "Read Success Rate: %.2f%%\n", result.ReadSuccessRate)
-	fmt.Fprintf(file, "Data Consistency Rate: %.2f%%\n\n", result.ConsistencyRate)
+	fmt.Fprintf(file, "Data Consistency Rate: %.2f%%\n", result.ConsistencyRate)
+	if len(result.WriteFailureCategories) > 0 {
+		fmt.Fprintf(file, "Write Failures by Category: %s\n", formatCategories(result.WriteFailureCategories))
+	}
+	if len(result.ReadFailureCategories) > 0 {
+		fmt.Fprintf(file, "Read Failures by Category: %s\n", formatCategories(result.ReadFailureCategories))
+	}
+	fmt.Fprintf(file, "Longest Leaderless Window: %v\n\n", result.LongestLeaderless)
 
 	fmt.Fprintf(file, "DETAILED FINDINGS\n")
-	fmt.Fprintf(file, "-" + strings.Repeat("-", 20) + "\n")
+	fmt.Fprint(file, "-" + strings.Repeat("-", 20) + "\n")
 
 	fmt.Fprintf(file, "\n1. WRITE DURABILITY ANALYSIS\n")
 	if len(result.WriteGaps) == 0 {
@@ -731,6 +1226,24 @@ func (la *LogAnalyzer) WriteDetailedReport(result *AnalysisResult, filename stri
 	}
 	fmt.Fprintf(file, "   Consistency Rate: %.2f%%\n", result.ConsistencyRate)
 
+	if len(result.StaleReads) > 0 {
+		fmt.Fprintf(file, "\n   Stale Read Details (max staleness: %d sequence numbers)\n", result.MaxStaleness)
+		for _, stale := range result.StaleReads {
+			distance := "unknown"
+			if stale.SeqDistance >= 0 {
+				distance = fmt.Sprintf("%d behind", stale.SeqDistance)
+			}
+			fmt.Fprintf(file, "   [%s] %s: got '%s', expected '%s' (%s)\n",
+				stale.Timestamp.Format("15:04:05.000"), stale.Key, stale.Got, stale.Expected, distance)
+		}
+	}
+
+	fmt.Fprintf(file, "\n   Interleaving Anomalies: %d read(s) completed before their write, %d read(s) of values never successfully written\n",
+		result.ReadsBeforeWrite, result.UnwrittenValueReads)
+	for _, anomaly := range result.InterleavingAnomalies {
+		fmt.Fprintf(file, "   [%s] %s: %s (%s)\n", anomaly.Timestamp.Format("15:04:05.000"), anomaly.Key, anomaly.Value, anomaly.Kind)
+	}
+
 	fmt.Fprintf(file, "\n3. AUTOMATIC RECOVERY ANALYSIS\n")
 	if len(result.FailoverEvents) == 0 && result.LeaderChanges > 0 {
 		fmt.Fprintf(file, "   STATUS: ✅ PASSED\n")
@@ -747,13 +1260,35 @@ func (la *LogAnalyzer) WriteDetailedReport(result *AnalysisResult, filename stri
 		fmt.Fprintf(file, "   No failover scenarios were detected during the test.\n")
 	}
 
+	fmt.Fprintf(file, "\n4. LEADER AVAILABILITY ANALYSIS\n")
+	if len(result.Leaderless) == 0 {
+		fmt.Fprintf(file, "   No partition was observed without a leader.\n")
+	} else {
+		fmt.Fprintf(file, "   Longest leaderless window: %v (partition %d)\n", result.LongestLeaderless, result.LongestLeaderlessPartition)
+		for _, id := range sortedLeaderless(result.Leaderless) {
+			stats := result.Leaderless[id]
+			fmt.Fprintf(file, "   Partition %d: %v total across %d window(s), longest %v\n", id, stats.Total, stats.Windows, stats.Longest)
+		}
+	}
+
 	fmt.Fprintf(file, "\nPERFORMANCE STATISTICS\n")
-	fmt.Fprintf(file, "-" + strings.Repeat("-", 25) + "\n")
+	fmt.Fprint(file, "-" + strings.Repeat("-", 25) + "\n")
 	la.writeLatencyStats(file, "Write Operations", result.WriteLatency)
 	la.writeLatencyStats(file, "Read Operations", result.ReadLatency)
 
+	if len(result.PartitionLatency) > 0 {
+		fmt.Fprintf(file, "\nPER-PARTITION LATENCY\n")
+		fmt.Fprint(file, "-"+strings.Repeat("-", 25)+"\n")
+		for _, id := range sortedPartitions(result.PartitionLatency) {
+			stats := result.PartitionLatency[id]
+			fmt.Fprintf(file, "\nPartition %d (%d writes, %d reads)\n", id, stats.Writes, stats.Reads)
+			la.writeLatencyStats(file, "Write Operations", stats.WriteLatency)
+			la.writeLatencyStats(file, "Read Operations", stats.ReadLatency)
+		}
+	}
+
 	fmt.Fprintf(file, "\nRECOMMendations\n")
-	fmt.Fprintf(file, "-" + strings.Repeat("-", 20) + "\n")
+	fmt.Fprint(file, "-" + strings.Repeat("-", 20) + "\n")
 	
 	if len(result.WriteGaps) > 0 {
 		fmt.Fprintf(file, "• Investigate write durability failures\n")
@@ -780,6 +1315,273 @@ func (la *LogAnalyzer) WriteDetailedReport(result *AnalysisResult, filename stri
 	return nil
 }
 
+func (la *LogAnalyzer) generateCSV(result *AnalysisResult, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	type csvRow struct {
+		timestamp time.Time
+		record    []string
+	}
+
+	var rows []csvRow
+	var totalDuration time.Duration
+	successful := 0
+
+	for _, w := range result.Writes {
+		rows = append(rows, csvRow{w.Timestamp, []string{
+			w.Timestamp.Format("2006-01-02 15:04:05.000"), "write", w.Key, strconv.Itoa(w.SeqNum),
+			fmt.Sprintf("%.3f", float64(w.Duration.Nanoseconds())/1e6), strconv.FormatBool(w.Success), w.Category, w.Error,
+		}})
+		totalDuration += w.Duration
+		if w.Success {
+			successful++
+		}
+	}
+	for _, r := range result.Reads {
+		opType := "read"
+		errorMsg := r.Error
+		if r.Expected != "" {
+			opType = "read_inconsistent"
+			errorMsg = fmt.Sprintf("got '%s', expected '%s'", r.Value, r.Expected)
+		}
+		rows = append(rows, csvRow{r.Timestamp, []string{
+			r.Timestamp.Format("2006-01-02 15:04:05.000"), opType, r.Key, strconv.Itoa(r.SeqNum),
+			fmt.Sprintf("%.3f", float64(r.Duration.Nanoseconds())/1e6), strconv.FormatBool(r.Success), r.Category, errorMsg,
+		}})
+		totalDuration += r.Duration
+		if r.Success {
+			successful++
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].timestamp.Before(rows[j].timestamp)
+	})
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"timestamp", "type", "key", "seq", "duration_ms", "success", "category", "error"})
+	for _, row := range rows {
+		writer.Write(row.record)
+	}
+
+	// Summary row: total operations in seq, mean latency in duration_ms, successful/total in success
+	var meanMs float64
+	if len(rows) > 0 {
+		meanMs = float64(totalDuration.Nanoseconds()) / 1e6 / float64(len(rows))
+	}
+	writer.Write([]string{
+		"", "summary", "", strconv.Itoa(len(rows)),
+		fmt.Sprintf("%.3f", meanMs), fmt.Sprintf("%d/%d", successful, len(rows)), "", "",
+	})
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// TimelineWindow is the operations that completed in one fixed-size window of the run
+type TimelineWindow struct {
+	Start         time.Time
+	Writes        int
+	Reads         int
+	Successful    int
+	Failed        int
+	LeaderChanges []time.Time
+}
+
+func (w TimelineWindow) Operations() int {
+	return w.Writes + w.Reads
+}
+
+// buildTimeline buckets operations by completion time into consecutive windows from the first
+// logged event to the last. Windows without operations are kept, since an outage shows up as a
+// run of empty windows rather than failures.
+func (la *LogAnalyzer) buildTimeline(result *AnalysisResult, window time.Duration) []TimelineWindow {
+	var start, end time.Time
+	observe := func(t time.Time) {
+		if t.IsZero() {
+			return
+		}
+		if start.IsZero() || t.Before(start) {
+			start = t
+		}
+		if t.After(end) {
+			end = t
+		}
+	}
+	for _, w := range result.Writes {
+		observe(w.Timestamp)
+	}
+	for _, r := range result.Reads {
+		observe(r.Timestamp)
+	}
+	for _, change := range result.LeaderChangeLog {
+		observe(change.Timestamp)
+	}
+	if start.IsZero() {
+		return nil
+	}
+
+	windows := make([]TimelineWindow, int(end.Sub(start)/window)+1)
+	for i := range windows {
+		windows[i].Start = start.Add(time.Duration(i) * window)
+	}
+	bucket := func(t time.Time) *TimelineWindow {
+		if t.IsZero() {
+			return nil
+		}
+		return &windows[int(t.Sub(start)/window)]
+	}
+
+	for _, w := range result.Writes {
+		if b := bucket(w.Timestamp); b != nil {
+			b.Writes++
+			if w.Success {
+				b.Successful++
+			} else {
+				b.Failed++
+			}
+		}
+	}
+	for _, r := range result.Reads {
+		if b := bucket(r.Timestamp); b != nil {
+			b.Reads++
+			if r.Success {
+				b.Successful++
+			} else {
+				b.Failed++
+			}
+		}
+	}
+	for _, change := range result.LeaderChangeLog {
+		if b := bucket(change.Timestamp); b != nil {
+			b.LeaderChanges = append(b.LeaderChanges, change.Timestamp)
+		}
+	}
+	return windows
+}
+
+// generateTimelineCSV writes the availability time series: one row per window with its operation
+// count, success rate and throughput, and the times of any leader changes inside it
+func (la *LogAnalyzer) generateTimelineCSV(result *AnalysisResult, window time.Duration, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"window_start", "elapsed_s", "operations", "writes", "reads", "successful", "failed",
+		"success_rate", "ops_per_sec", "leader_changes"})
+
+	windows := la.buildTimeline(result, window)
+	for i, w := range windows {
+		// An empty window has no success rate; leaving it blank keeps it from plotting as 0% or 100%
+		successRate := ""
+		if w.Operations() > 0 {
+			successRate = fmt.Sprintf("%.2f", float64(w.Successful)/float64(w.Operations())*100)
+		}
+		markers := make([]string, len(w.LeaderChanges))
+		for j, t := range w.LeaderChanges {
+			markers[j] = t.Format("15:04:05.000")
+		}
+		writer.Write([]string{
+			w.Start.Format("2006-01-02 15:04:05.000"),
+			fmt.Sprintf("%.3f", (time.Duration(i) * window).Seconds()),
+			strconv.Itoa(w.Operations()), strconv.Itoa(w.Writes), strconv.Itoa(w.Reads),
+			strconv.Itoa(w.Successful), strconv.Itoa(w.Failed),
+			successRate,
+			fmt.Sprintf("%.2f", float64(w.Operations())/window.Seconds()),
+			strings.Join(markers, ";"),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// jsonDuration is how durations appear in the JSON report: raw nanoseconds plus a readable string
+type jsonDuration struct {
+	Nanoseconds int64  `json:"nanoseconds"`
+	String      string `json:"string"`
+}
+
+func newJSONDuration(d time.Duration) jsonDuration {
+	return jsonDuration{Nanoseconds: d.Nanoseconds(), String: d.String()}
+}
+
+func (s LatencyStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Min    jsonDuration
+		Max    jsonDuration
+		Mean   jsonDuration
+		Median jsonDuration
+		P95    jsonDuration
+		P99    jsonDuration
+		StdDev jsonDuration
+	}{
+		Min:    newJSONDuration(s.Min),
+		Max:    newJSONDuration(s.Max),
+		Mean:   newJSONDuration(s.Mean),
+		Median: newJSONDuration(s.Median),
+		P95:    newJSONDuration(s.P95),
+		P99:    newJSONDuration(s.P99),
+		StdDev: newJSONDuration(s.StdDev),
+	})
+}
+
+func (e FailoverEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		StartTime    time.Time
+		EndTime      time.Time
+		Duration     jsonDuration
+		ImpactedOps  int
+		RecoveryTime jsonDuration
+	}{
+		StartTime:    e.StartTime,
+		EndTime:      e.EndTime,
+		Duration:     newJSONDuration(e.Duration),
+		ImpactedOps:  e.ImpactedOps,
+		RecoveryTime: newJSONDuration(e.RecoveryTime),
+	})
+}
+
+func (s LeaderlessStats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Windows int
+		Total   jsonDuration
+		Longest jsonDuration
+	}{
+		Windows: s.Windows,
+		Total:   newJSONDuration(s.Total),
+		Longest: newJSONDuration(s.Longest),
+	})
+}
+
+func (r AnalysisResult) MarshalJSON() ([]byte, error) {
+	type analysisResult AnalysisResult
+	return json.Marshal(struct {
+		analysisResult
+		TestDuration      jsonDuration
+		LongestLeaderless jsonDuration
+	}{
+		analysisResult:    analysisResult(r),
+		TestDuration:      newJSONDuration(r.TestDuration),
+		LongestLeaderless: newJSONDuration(r.LongestLeaderless),
+	})
+}
+
+func (la *LogAnalyzer) WriteJSONReport(result *AnalysisResult, filename string) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
 func (la *LogAnalyzer) writeLatencyStats(file *os.File, title string, stats LatencyStats) {
 	if stats.Mean == 0 {
 		return
@@ -792,6 +1594,437 @@ func (la *LogAnalyzer) writeLatencyStats(file *os.File, title string, stats Late
 	fmt.Fprintf(file, "  Standard Deviation: %v\n", stats.StdDev)
 }
 
+// comparisonMetric is one row of the multi-run comparison
+type comparisonMetric struct {
+	Name  string
+	Unit  string
+	Value func(*AnalysisResult) float64
+}
+
+var comparisonMetrics = []comparisonMetric{
+	{"Write Success Rate", "%", func(r *AnalysisResult) float64 { return r.WriteSuccessRate }},
+	{"Read Success Rate", "%", func(r *AnalysisResult) float64 { return r.ReadSuccessRate }},
+	{"Consistency Rate", "%", func(r *AnalysisResult) float64 { return r.ConsistencyRate }},
+	{"Leader Changes", "", func(r *AnalysisResult) float64 { return float64(r.LeaderChanges) }},
+	{"Write Throughput", "/s", func(r *AnalysisResult) float64 { return r.WriteThroughput }},
+	{"Read Throughput", "/s", func(r *AnalysisResult) float64 { return r.ReadThroughput }},
+	{"Mean Write Latency", "ms", func(r *AnalysisResult) float64 { return float64(r.WriteLatency.Mean.Nanoseconds()) / 1e6 }},
+	{"Mean Read Latency", "ms", func(r *AnalysisResult) float64 { return float64(r.ReadLatency.Mean.Nanoseconds()) / 1e6 }},
+	{"Longest Leaderless", "ms", func(r *AnalysisResult) float64 { return float64(r.LongestLeaderless.Nanoseconds()) / 1e6 }},
+}
+
+// RunComparison holds each run's metric values in comparisonMetrics order, with deltas against the first run
+type RunComparison struct {
+	Name   string
+	Values []float64
+	Deltas []float64
+}
+
+func runComparison(args []string) {
+	var logFiles []string
+	window := time.Second
+	html := false
+	for _, arg := range args {
+		switch {
+		case arg == "-html":
+			html = true
+		case strings.HasPrefix(arg, "-window="):
+			d, err := time.ParseDuration(strings.TrimPrefix(arg, "-window="))
+			if err != nil || d <= 0 {
+				fmt.Printf("Invalid timeline window '%s'\n", arg)
+				os.Exit(1)
+			}
+			window = d
+		case strings.HasPrefix(arg, "-"):
+			fmt.Printf("Unknown argument '%s'\n", arg)
+			os.Exit(1)
+		default:
+			logFiles = append(logFiles, arg)
+		}
+	}
+	if len(logFiles) < 2 {
+		fmt.Println("-compare needs at least two log files")
+		os.Exit(1)
+	}
+
+	analyzer := NewLogAnalyzer()
+	var results []*AnalysisResult
+	for _, logFile := range logFiles {
+		result, err := analyzer.AnalyzeLog(logFile)
+		if err != nil {
+			fmt.Printf("Error analyzing log %s: %v\n", logFile, err)
+			os.Exit(1)
+		}
+		results = append(results, result)
+	}
+
+	runs := compareRuns(results)
+	printComparison(runs)
+
+	csvFile := "runs-comparison.csv"
+	if err := writeComparisonCSV(runs, csvFile); err != nil {
+		fmt.Printf("Warning: Could not write comparison CSV: %v\n", err)
+	} else {
+		fmt.Printf("\nComparison CSV generated: %s\n", csvFile)
+	}
+
+	if html {
+		htmlFile := "runs-comparison.html"
+		if err := analyzer.WriteHTMLReport(results, window, htmlFile); err != nil {
+			fmt.Printf("Warning: Could not write HTML report: %v\n", err)
+		} else {
+			fmt.Printf("HTML report generated: %s\n", htmlFile)
+		}
+	}
+}
+
+func compareRuns(results []*AnalysisResult) []RunComparison {
+	runs := make([]RunComparison, 0, len(results))
+	for _, result := range results {
+		run := RunComparison{Name: result.Name}
+		for i, metric := range comparisonMetrics {
+			value := metric.Value(result)
+			run.Values = append(run.Values, value)
+			var delta float64
+			if len(runs) > 0 {
+				delta = value - runs[0].Values[i]
+			}
+			run.Deltas = append(run.Deltas, delta)
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+func printComparison(runs []RunComparison) {
+	fmt.Println("\nATOMIX FAILOVER TEST RUN COMPARISON")
+	fmt.Println("=" + strings.Repeat("=", 60))
+
+	const metricWidth = 20
+	const columnWidth = 24
+
+	fmt.Printf("%-*s", metricWidth, "Metric")
+	for i, run := range runs {
+		name := run.Name
+		if i > 0 {
+			name += " (delta)"
+		}
+		fmt.Printf(" %*s", columnWidth, name)
+	}
+	fmt.Println()
+	fmt.Println(strings.Repeat("-", metricWidth+len(runs)*(columnWidth+1)))
+
+	for m, metric := range comparisonMetrics {
+		fmt.Printf("%-*s", metricWidth, metric.Name)
+		for i, run := range runs {
+			precision := 2
+			if metric.Unit == "" {
+				precision = 0
+			}
+			cell := fmt.Sprintf("%.*f%s", precision, run.Values[m], metric.Unit)
+			if i > 0 {
+				cell += fmt.Sprintf(" (%+.*f)", precision, run.Deltas[m])
+			}
+			fmt.Printf(" %*s", columnWidth, cell)
+		}
+		fmt.Println()
+	}
+}
+
+func writeComparisonCSV(runs []RunComparison, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+
+	header := []string{"run"}
+	for _, metric := range comparisonMetrics {
+		column := strings.ToLower(strings.ReplaceAll(metric.Name, " ", "_"))
+		switch metric.Unit {
+		case "ms":
+			column += "_ms"
+		case "/s":
+			column += "_per_sec"
+		}
+		header = append(header, column, column+"_delta")
+	}
+	writer.Write(header)
+
+	for _, run := range runs {
+		record := []string{run.Name}
+		for m := range comparisonMetrics {
+			record = append(record, fmt.Sprintf("%.3f", run.Values[m]), fmt.Sprintf("%.3f", run.Deltas[m]))
+		}
+		writer.Write(record)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// htmlRun is one run's section of the HTML report, with its charts pre-rendered as inline SVG
+type htmlRun struct {
+	*AnalysisResult
+	SuccessChart  template.HTML
+	LatencyChart  template.HTML
+	TimelineChart template.HTML
+}
+
+type htmlReport struct {
+	Generated  string
+	Window     time.Duration
+	Metrics    []comparisonMetric
+	Comparison []RunComparison
+	Runs       []htmlRun
+}
+
+var htmlFuncs = template.FuncMap{
+	"ms": func(d time.Duration) string { return fmt.Sprintf("%.2f", float64(d.Nanoseconds())/1e6) },
+	"pct": func(v float64) string { return fmt.Sprintf("%.2f%%", v) },
+	"value": func(v float64, unit string) string {
+		if unit == "" {
+			return fmt.Sprintf("%.0f", v)
+		}
+		return fmt.Sprintf("%.2f%s", v, unit)
+	},
+	"delta": func(v float64, unit string) string {
+		if unit == "" {
+			return fmt.Sprintf("%+.0f", v)
+		}
+		return fmt.Sprintf("%+.2f", v)
+	},
+	"clock": func(t time.Time) string { return t.Format("15:04:05.000") },
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(htmlFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Atomix Failover Test Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+th { background: #f0f0f0; }
+svg { display: block; margin: 1em 0; }
+</style>
+</head>
+<body>
+<h1>Atomix Failover Test Report</h1>
+<p>Generated {{.Generated}}, timeline window {{.Window}}</p>
+{{if gt (len .Comparison) 1}}
+<h2>Run Comparison</h2>
+<table>
+<tr><th>Metric</th>{{range $i, $run := .Comparison}}<th>{{$run.Name}}{{if $i}} (delta){{end}}</th>{{end}}</tr>
+{{range $m, $metric := .Metrics}}<tr><td>{{$metric.Name}}</td>{{range $i, $run := $.Comparison}}<td>{{value (index $run.Values $m) $metric.Unit}}{{if $i}} ({{delta (index $run.Deltas $m) $metric.Unit}}){{end}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+{{range .Runs}}
+<h2>{{.Name}}</h2>
+<table>
+<tr><th>Operation</th><th>Total</th><th>Successful</th><th>Failed</th><th>Success Rate</th><th>Throughput</th></tr>
+<tr><td>Writes</td><td>{{.TotalWrites}}</td><td>{{.SuccessfulWrites}}</td><td>{{.FailedWrites}}</td><td>{{pct .WriteSuccessRate}}</td><td>{{printf "%.2f/s" .WriteThroughput}}</td></tr>
+<tr><td>Reads</td><td>{{.TotalReads}}</td><td>{{.SuccessfulReads}}</td><td>{{.FailedReads}}</td><td>{{pct .ReadSuccessRate}}</td><td>{{printf "%.2f/s" .ReadThroughput}}</td></tr>
+</table>
+<p>Test duration {{.TestDuration}}, {{.LeaderChanges}} leader change(s), {{.InconsistentReads}} inconsistent read(s), {{.ReadsBeforeWrite}} read(s) before their write, {{.UnwrittenValueReads}} read(s) of unwritten values, longest leaderless window {{.LongestLeaderless}}</p>
+{{.SuccessChart}}
+<h3>Latency (ms)</h3>
+<table>
+<tr><th>Operation</th><th>Min</th><th>Median</th><th>Mean</th><th>P95</th><th>P99</th><th>Max</th></tr>
+<tr><td>Writes</td><td>{{ms .WriteLatency.Min}}</td><td>{{ms .WriteLatency.Median}}</td><td>{{ms .WriteLatency.Mean}}</td><td>{{ms .WriteLatency.P95}}</td><td>{{ms .WriteLatency.P99}}</td><td>{{ms .WriteLatency.Max}}</td></tr>
+<tr><td>Reads</td><td>{{ms .ReadLatency.Min}}</td><td>{{ms .ReadLatency.Median}}</td><td>{{ms .ReadLatency.Mean}}</td><td>{{ms .ReadLatency.P95}}</td><td>{{ms .ReadLatency.P99}}</td><td>{{ms .ReadLatency.Max}}</td></tr>
+</table>
+{{.LatencyChart}}
+<h3>Failover Timeline</h3>
+<p>Success rate per window (line), operations per window (bars) and leader changes (red)</p>
+{{.TimelineChart}}
+{{if .FailoverEvents}}
+<table>
+<tr><th>Failover</th><th>Start</th><th>End</th><th>Duration (ms)</th><th>Impacted Ops</th><th>Recovery (ms)</th></tr>
+{{range $i, $e := .FailoverEvents}}<tr><td>{{$i}}</td><td>{{clock $e.StartTime}}</td><td>{{clock $e.EndTime}}</td><td>{{ms $e.Duration}}</td><td>{{$e.ImpactedOps}}</td><td>{{ms $e.RecoveryTime}}</td></tr>
+{{end}}</table>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTMLReport renders a self-contained report for one or more runs. With more than one run it
+// starts with the comparison table; every run then gets its tables and charts.
+func (la *LogAnalyzer) WriteHTMLReport(results []*AnalysisResult, window time.Duration, filename string) error {
+	report := htmlReport{
+		Generated: time.Now().Format("2006-01-02 15:04:05"),
+		Window:    window,
+		Metrics:   comparisonMetrics,
+	}
+	if len(results) > 1 {
+		report.Comparison = compareRuns(results)
+	}
+	for _, result := range results {
+		report.Runs = append(report.Runs, htmlRun{
+			AnalysisResult: result,
+			SuccessChart:   successRateSVG(result),
+			LatencyChart:   latencySVG(result),
+			TimelineChart:  timelineSVG(la.buildTimeline(result, window), window),
+		})
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return htmlTemplate.Execute(file, report)
+}
+
+const (
+	svgWidth  = 720
+	svgHeight = 240
+	svgMargin = 50
+)
+
+// successRateSVG is a bar per rate on a fixed 0-100% scale
+func successRateSVG(result *AnalysisResult) template.HTML {
+	bars := []struct {
+		label string
+		value float64
+	}{
+		{"Write Success", result.WriteSuccessRate},
+		{"Read Success", result.ReadSuccessRate},
+		{"Consistency", result.ConsistencyRate},
+	}
+
+	var b strings.Builder
+	plotHeight := float64(svgHeight - 2*svgMargin)
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`, svgWidth, svgHeight)
+	svgAxes(&b, "100%", "0%")
+	slot := float64(svgWidth-2*svgMargin) / float64(len(bars))
+	for i, bar := range bars {
+		h := plotHeight * math.Max(0, math.Min(bar.value, 100)) / 100
+		x := svgMargin + float64(i)*slot + slot*0.2
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#4a7fb5"/>`,
+			x, svgMargin+plotHeight-h, slot*0.6, h)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" font-size="12">%.2f%%</text>`,
+			x+slot*0.3, svgMargin+plotHeight-h-4, bar.value)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" font-size="12">%s</text>`,
+			x+slot*0.3, svgHeight-svgMargin+16, bar.label)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// latencySVG groups write and read bars by percentile, scaled to the larger of the two maximums
+func latencySVG(result *AnalysisResult) template.HTML {
+	labels := []string{"Min", "Median", "P95", "P99", "Max"}
+	series := []struct {
+		name   string
+		color  string
+		values []time.Duration
+	}{
+		{"Writes", "#4a7fb5", []time.Duration{result.WriteLatency.Min, result.WriteLatency.Median, result.WriteLatency.P95, result.WriteLatency.P99, result.WriteLatency.Max}},
+		{"Reads", "#e39c37", []time.Duration{result.ReadLatency.Min, result.ReadLatency.Median, result.ReadLatency.P95, result.ReadLatency.P99, result.ReadLatency.Max}},
+	}
+	scale := time.Duration(1)
+	for _, s := range series {
+		for _, v := range s.values {
+			if v > scale {
+				scale = v
+			}
+		}
+	}
+
+	var b strings.Builder
+	plotHeight := float64(svgHeight - 2*svgMargin)
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`, svgWidth, svgHeight)
+	svgAxes(&b, fmt.Sprintf("%.1fms", float64(scale.Nanoseconds())/1e6), "0")
+	slot := float64(svgWidth-2*svgMargin) / float64(len(labels))
+	barWidth := slot * 0.8 / float64(len(series))
+	for i, label := range labels {
+		for j, s := range series {
+			h := plotHeight * float64(s.values[i]) / float64(scale)
+			x := svgMargin + float64(i)*slot + slot*0.1 + float64(j)*barWidth
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s %s: %v</title></rect>`,
+				x, svgMargin+plotHeight-h, barWidth, h, s.color, s.name, label, s.values[i])
+		}
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" font-size="12">%s</text>`,
+			svgMargin+float64(i)*slot+slot/2, svgHeight-svgMargin+16, label)
+	}
+	for j, s := range series {
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/><text x="%d" y="%d" font-size="12">%s</text>`,
+			svgWidth-svgMargin-120+j*60, 10, s.color, svgWidth-svgMargin-106+j*60, 19, s.name)
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// timelineSVG plots each window's operation count as a bar and its success rate as a line, with a
+// marker at every leader change. Empty windows break the line instead of plotting as 0%.
+func timelineSVG(windows []TimelineWindow, window time.Duration) template.HTML {
+	if len(windows) == 0 {
+		return template.HTML(`<p>No timestamped operations to plot</p>`)
+	}
+	maxOps := 1
+	for _, w := range windows {
+		if w.Operations() > maxOps {
+			maxOps = w.Operations()
+		}
+	}
+
+	var b strings.Builder
+	plotHeight := float64(svgHeight - 2*svgMargin)
+	slot := float64(svgWidth-2*svgMargin) / float64(len(windows))
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d">`, svgWidth, svgHeight)
+	svgAxes(&b, "100%", "0%")
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" font-size="12">%d ops</text>`, svgWidth-4, svgMargin+4, maxOps)
+
+	var line []string
+	flush := func() {
+		if len(line) > 0 {
+			fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="#2a8a3a" stroke-width="2"/>`, strings.Join(line, " "))
+			line = nil
+		}
+	}
+	for i, w := range windows {
+		x := svgMargin + float64(i)*slot
+		h := plotHeight * float64(w.Operations()) / float64(maxOps)
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#d8d8d8"/>`,
+			x, svgMargin+plotHeight-h, math.Max(slot-1, 0.5), h)
+		if w.Operations() == 0 {
+			flush()
+			continue
+		}
+		rate := float64(w.Successful) / float64(w.Operations())
+		line = append(line, fmt.Sprintf("%.1f,%.1f", x+slot/2, svgMargin+plotHeight*(1-rate)))
+	}
+	flush()
+
+	start := windows[0].Start
+	for _, w := range windows {
+		for _, t := range w.LeaderChanges {
+			x := svgMargin + float64(t.Sub(start))/float64(window)*slot
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#c0392b" stroke-dasharray="4,2"><title>Leader change at %s</title></line>`,
+				x, svgMargin, x, svgHeight-svgMargin, t.Format("15:04:05.000"))
+		}
+	}
+	elapsed := time.Duration(len(windows)) * window
+	fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="12">0s</text>`, svgMargin, svgHeight-svgMargin+16)
+	fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" font-size="12">%v</text>`, svgWidth-svgMargin, svgHeight-svgMargin+16, elapsed)
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// svgAxes draws the plot area's left and bottom axes with labels for the top and bottom of the scale
+func svgAxes(b *strings.Builder, top, bottom string) {
+	fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#555"/>`, svgMargin, svgMargin, svgMargin, svgHeight-svgMargin)
+	fmt.Fprintf(b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#555"/>`, svgMargin, svgHeight-svgMargin, svgWidth-svgMargin, svgHeight-svgMargin)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end" font-size="12">%s</text>`, svgMargin-4, svgMargin+4, top)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end" font-size="12">%s</text>`, svgMargin-4, svgHeight-svgMargin, bottom)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
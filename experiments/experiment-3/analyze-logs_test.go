@@ -0,0 +1,88 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// main.go is a separate program in the same directory, so run these with
+// go test analyze-logs.go analyze-logs_test.go
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{in: "523us", want: 523 * time.Microsecond},
+		{in: "750µs", want: 750 * time.Microsecond},
+		{in: "1.2ms", want: 1200 * time.Microsecond},
+		{in: "3s", want: 3 * time.Second},
+		{in: "42ns", want: 42 * time.Nanosecond},
+		{in: "12.5µs", want: 12500 * time.Nanosecond},
+		{in: "soon", want: 0},
+	}
+
+	la := NewLogAnalyzer()
+	for _, tt := range tests {
+		if got := la.parseDuration(tt.in); got != tt.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	// 1ms..100ms, shuffled so calculateLatencyStats has to sort them
+	durations := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+
+	stats := NewLogAnalyzer().calculateLatencyStats(durations)
+	for _, tt := range []struct {
+		name      string
+		got, want time.Duration
+	}{
+		{name: "P95", got: stats.P95, want: 95 * time.Millisecond},
+		{name: "P99", got: stats.P99, want: 99 * time.Millisecond},
+		{name: "Min", got: stats.Min, want: time.Millisecond},
+		{name: "Max", got: stats.Max, want: 100 * time.Millisecond},
+	} {
+		if diff := tt.got - tt.want; diff < -100*time.Microsecond || diff > 100*time.Microsecond {
+			t.Errorf("%s = %v, want about %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// Interpolating between the two nearest ranks, not truncating to the lower one
+	sorted := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}
+	if got, want := percentile(sorted, 0.95), 19500*time.Microsecond; got != want {
+		t.Errorf("percentile(%v, 0.95) = %v, want %v", sorted, got, want)
+	}
+	if got := percentile(sorted, 1); got != sorted[1] {
+		t.Errorf("percentile(%v, 1) = %v, want %v", sorted, got, sorted[1])
+	}
+
+	single := []time.Duration{7 * time.Millisecond}
+	for _, p := range []float64{0.5, 0.95, 0.99} {
+		if got := percentile(single, p); got != single[0] {
+			t.Errorf("percentile of a single sample at %v = %v, want %v", p, got, single[0])
+		}
+	}
+}
+
+func TestParseLeaderChange(t *testing.T) {
+	line := "LEADER_CHANGE: Partition 1: Pod atomix-raft-store-0 (term: 3, Leader), Partition 2: No Leader, Partition 3: Pod unknown (term: 2, Leader)"
+	change := NewLogAnalyzer().parseLeaderChange(line, time.Time{})
+	if change == nil {
+		t.Fatal("parseLeaderChange returned nil")
+	}
+
+	want := map[int]PartitionInfo{
+		1: {Pod: "atomix-raft-store-0", Term: 3, State: "Leader"},
+		2: {NoLeader: true},
+		3: {Pod: "unknown", Term: 2, State: "Leader"},
+	}
+	if !reflect.DeepEqual(change.Partitions, want) {
+		t.Errorf("Partitions = %+v, want %+v", change.Partitions, want)
+	}
+}
@@ -2,10 +2,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math/rand"
 	"os"
 	"os/signal"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,13 +18,12 @@ import (
 	"time"
 
 	"github.com/atomix/go-sdk/pkg/atomix"
-	"github.com/atomix/go-sdk/pkg/generic"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
+	"github.com/atomix/runtime/sdk/pkg/errors"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+
+	"example.com/envutil"
+	"example.com/raftwatch"
+	"example.com/valuemap"
 )
 
 type FailoverTest struct {
@@ -28,37 +31,168 @@ type FailoverTest struct {
 	writeLog      map[string]string
 	writeLogMux   sync.RWMutex
 	leaderStatus  string
+	leaders       map[int]raftwatch.LeaderInfo // as last seen by leaderMonitor
 	leaderMux     sync.RWMutex
-	k8sClient     *kubernetes.Clientset
-	dynamicClient dynamic.Interface
+	raftWatcher   *raftwatch.Watcher
 	logFile       *os.File
 	writeInterval time.Duration
 	readInterval  time.Duration
 	testDuration  time.Duration
 	namespace     string
+	testPrimitive string
+	mapName       string
+	counterHigh   int64
+	regressions   int64
+	splitBrains   int64
+
+	autoFailoverInterval time.Duration
+
+	// termLeaders records the leader first seen for each partition and term; only leaderMonitor touches it
+	termLeaders map[int]map[int64]string
+	// unparseableGroups is how many RaftGroups were last reported as not matching GROUP_NAME_PATTERN; only leaderMonitor touches it
+	unparseableGroups int
+
+	writeConcurrency int
+	writesCompleted  int64
+	writeJitter      float64
+	logFormat        string
+
+	// slowWriteThreshold, if non-zero, logs WRITE_SLOW for every Put that takes longer, successful or not
+	slowWriteThreshold time.Duration
+
+	// mapSizeInterval, if non-zero, logs the test map's entry count as MAP_SIZE that often. maxKeys, if
+	// non-zero, makes the writer cycle through seq-000001..seq-<maxKeys> instead of a new key per write
+	mapSizeInterval time.Duration
+	maxKeys         int64
+
+	// maxMapEntries, if non-zero, trims the oldest seq keys from the map and writeLog once writeLog holds
+	// more. trimmedSeq is the highest seq trimmed so far and writeOrder holds writeLog's seqs in ascending
+	// order, both guarded by writeLogMux.
+	maxMapEntries int64
+	trimmedSeq    int64
+	writeOrder    []int64
+
+	valueType  string
+	testMap    valuemap.Map
+	testMapMux sync.Mutex
+
+	// TEST_MODE=benchmark replaces the failover writer/reader with benchmarkClients goroutines doing
+	// random Puts and Gets over benchmarkKeys keys, with no pod termination
+	testMode           string
+	benchmarkClients   int
+	benchmarkKeys      int
+	benchmarkReadRatio float64
+	valueSize          int
 }
 
 func NewFailoverTest() (*FailoverTest, error) {
-	config, err := rest.InClusterConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get in-cluster config: %v", err)
-	}
-
-	clientset, err := kubernetes.NewForConfig(config)
+	config, source, err := raftwatch.KubeConfig()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Kubernetes client: %v", err)
+		return nil, fmt.Errorf("failed to get Kubernetes config: %v", err)
 	}
+	log.Printf("Using %s", source)
 
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
 	}
 
-	writeInterval := getEnvDuration("WRITE_INTERVAL", 1*time.Second)
-	readInterval := getEnvDuration("READ_INTERVAL", 2*time.Second)
-	testDuration := getEnvDuration("TEST_DURATION", 10*time.Minute)
-	logFileName := getEnv("LOG_FILE", "failover-test-results.log")
-	namespace := getEnv("NAMESPACE", "default")
+	writeInterval := envutil.Duration("WRITE_INTERVAL", 1*time.Second)
+	readInterval := envutil.Duration("READ_INTERVAL", 2*time.Second)
+	testDuration := envutil.Duration("TEST_DURATION", 10*time.Minute)
+	autoFailoverInterval := envutil.Duration("AUTO_FAILOVER_INTERVAL", 0)
+	mapSizeInterval := envutil.Duration("MAP_SIZE_INTERVAL", 30*time.Second)
+	logFileName := envutil.Get("LOG_FILE", "failover-test-results.log")
+	namespace := envutil.Get("NAMESPACE", "default")
+	storeName := envutil.Get("STORE_NAME", raftwatch.DefaultStoreName)
+	testPrimitive := envutil.Get("TEST_PRIMITIVE", "map")
+	mapName := envutil.Get("MAP_NAME", "test-map")
+	if testPrimitive != "map" && testPrimitive != "counter" {
+		return nil, fmt.Errorf("invalid TEST_PRIMITIVE '%s'. Valid options: 'map', 'counter'", testPrimitive)
+	}
+	valueType := envutil.Get("VALUE_TYPE", "string")
+	if valueType != "string" && valueType != "int64" && valueType != "float64" {
+		return nil, fmt.Errorf("invalid VALUE_TYPE '%s'. Valid options: 'string', 'int64', 'float64'", valueType)
+	}
+
+	writeConcurrency, err := strconv.Atoi(envutil.Get("WRITE_CONCURRENCY", "1"))
+	if err != nil || writeConcurrency < 1 {
+		return nil, fmt.Errorf("invalid WRITE_CONCURRENCY '%s'. Must be a positive integer", os.Getenv("WRITE_CONCURRENCY"))
+	}
+
+	writeJitter, err := strconv.ParseFloat(envutil.Get("WRITE_JITTER", "0"), 64)
+	if err != nil || writeJitter < 0 || writeJitter >= 1 {
+		return nil, fmt.Errorf("invalid WRITE_JITTER '%s'. Must be a fraction in [0, 1)", os.Getenv("WRITE_JITTER"))
+	}
+
+	logFormat := envutil.Get("LOG_FORMAT", "text")
+	if logFormat != "text" && logFormat != "jsonl" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT '%s'. Valid options: 'text', 'jsonl'", logFormat)
+	}
+
+	testMode := envutil.Get("TEST_MODE", "failover")
+	if testMode != "failover" && testMode != "benchmark" {
+		return nil, fmt.Errorf("invalid TEST_MODE '%s'. Valid options: 'failover', 'benchmark'", testMode)
+	}
+	if testMode == "benchmark" && testPrimitive != "map" {
+		return nil, fmt.Errorf("TEST_MODE 'benchmark' requires TEST_PRIMITIVE 'map'")
+	}
+
+	benchmarkClients, err := strconv.Atoi(envutil.Get("BENCHMARK_CLIENTS", "4"))
+	if err != nil || benchmarkClients < 1 {
+		return nil, fmt.Errorf("invalid BENCHMARK_CLIENTS '%s'. Must be a positive integer", os.Getenv("BENCHMARK_CLIENTS"))
+	}
+	benchmarkKeys, err := strconv.Atoi(envutil.Get("BENCHMARK_KEYS", "100"))
+	if err != nil || benchmarkKeys < 1 {
+		return nil, fmt.Errorf("invalid BENCHMARK_KEYS '%s'. Must be a positive integer", os.Getenv("BENCHMARK_KEYS"))
+	}
+	benchmarkReadRatio, err := strconv.ParseFloat(envutil.Get("BENCHMARK_READ_RATIO", "0.5"), 64)
+	if err != nil || benchmarkReadRatio < 0 || benchmarkReadRatio > 1 {
+		return nil, fmt.Errorf("invalid BENCHMARK_READ_RATIO '%s'. Must be a fraction in [0, 1]", os.Getenv("BENCHMARK_READ_RATIO"))
+	}
+	valueSize, err := strconv.Atoi(envutil.Get("VALUE_SIZE", "0"))
+	if err != nil || valueSize < 0 {
+		return nil, fmt.Errorf("invalid VALUE_SIZE '%s'. Must be a non-negative integer", os.Getenv("VALUE_SIZE"))
+	}
+	if valueSize > 0 && valueType != "string" {
+		return nil, fmt.Errorf("VALUE_SIZE requires VALUE_TYPE 'string'")
+	}
+
+	leaderRefresh, err := time.ParseDuration(envutil.Get("LEADER_REFRESH_INTERVAL", "1s"))
+	if err != nil || leaderRefresh < 0 {
+		return nil, fmt.Errorf("invalid LEADER_REFRESH_INTERVAL '%s'. Must be a non-negative duration", os.Getenv("LEADER_REFRESH_INTERVAL"))
+	}
+
+	maxKeys, err := strconv.ParseInt(envutil.Get("MAX_KEYS", "0"), 10, 64)
+	if err != nil || maxKeys < 0 {
+		return nil, fmt.Errorf("invalid MAX_KEYS '%s'. Must be a non-negative integer, 0 for a new key per write", os.Getenv("MAX_KEYS"))
+	}
+
+	maxMapEntries, err := strconv.ParseInt(envutil.Get("MAX_MAP_ENTRIES", "0"), 10, 64)
+	if err != nil || maxMapEntries < 0 {
+		return nil, fmt.Errorf("invalid MAX_MAP_ENTRIES '%s'. Must be a non-negative integer, 0 to never trim", os.Getenv("MAX_MAP_ENTRIES"))
+	}
+	// Trimming goes by seq order, which MAX_KEYS breaks by reusing keys
+	if maxMapEntries > 0 && maxKeys > 0 {
+		return nil, fmt.Errorf("MAX_MAP_ENTRIES and MAX_KEYS can't both be set")
+	}
+
+	slowWriteThreshold, err := time.ParseDuration(envutil.Get("SLOW_WRITE_THRESHOLD", "0"))
+	if err != nil || slowWriteThreshold < 0 {
+		return nil, fmt.Errorf("invalid SLOW_WRITE_THRESHOLD '%s'. Must be a non-negative duration, 0 to disable", os.Getenv("SLOW_WRITE_THRESHOLD"))
+	}
+
+	raftWatcher := raftwatch.New(dynamicClient, namespace, storeName, leaderRefresh)
+	if pattern := os.Getenv("GROUP_NAME_PATTERN"); pattern != "" {
+		if err := raftWatcher.SetGroupPattern(pattern); err != nil {
+			return nil, fmt.Errorf("invalid GROUP_NAME_PATTERN '%s': %v", pattern, err)
+		}
+	}
+	checkCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := raftWatcher.CheckStore(checkCtx); err != nil {
+		return nil, fmt.Errorf("STORE_NAME '%s': %v", storeName, err)
+	}
 
 	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
@@ -68,25 +202,96 @@ func NewFailoverTest() (*FailoverTest, error) {
 	return &FailoverTest{
 		writeSeq:      0,
 		writeLog:      make(map[string]string),
-		k8sClient:     clientset,
-		dynamicClient: dynamicClient,
+		termLeaders:   make(map[int]map[int64]string),
+		leaders:       make(map[int]raftwatch.LeaderInfo),
+		raftWatcher:   raftWatcher,
 		logFile:       logFile,
 		writeInterval: writeInterval,
 		readInterval:  readInterval,
 		testDuration:  testDuration,
 		namespace:     namespace,
+		testPrimitive: testPrimitive,
+		mapName:       mapName,
+		valueType:     valueType,
+
+		writeConcurrency: writeConcurrency,
+		writeJitter:      writeJitter,
+		logFormat:        logFormat,
+
+		slowWriteThreshold: slowWriteThreshold,
+
+		mapSizeInterval: mapSizeInterval,
+		maxKeys:         maxKeys,
+
+		maxMapEntries: maxMapEntries,
+
+		autoFailoverInterval: autoFailoverInterval,
+
+		testMode:           testMode,
+		benchmarkClients:   benchmarkClients,
+		benchmarkKeys:      benchmarkKeys,
+		benchmarkReadRatio: benchmarkReadRatio,
+		valueSize:          valueSize,
 	}, nil
 }
 
+// eventTypeRegex splits "EVENT_TYPE: details" log messages for the jsonl format
+var eventTypeRegex = regexp.MustCompile(`(?s)^([A-Z][A-Z0-9_]*): (.*)$`)
+
+// logMessage writes a free-form "EVENT_TYPE: details" line. In jsonl mode the event type is
+// split out and the details become the message field.
 func (ft *FailoverTest) logMessage(message string) {
+	if ft.logFormat == "jsonl" {
+		eventType, details := "MESSAGE", message
+		if matches := eventTypeRegex.FindStringSubmatch(message); matches != nil {
+			eventType, details = matches[1], matches[2]
+		}
+		ft.writeJSONEvent(eventType, details, nil)
+		return
+	}
+
 	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	logEntry := fmt.Sprintf("[%s] %s\n", timestamp, message)
+	ft.writeLogEntry(fmt.Sprintf("[%s] %s\n", timestamp, message))
+}
+
+// logEvent writes an event with structured fields. The text format prints only "EVENT_TYPE: message",
+// so the message must still carry everything the text analyzers parse.
+func (ft *FailoverTest) logEvent(eventType, message string, fields map[string]any) {
+	if ft.logFormat == "jsonl" {
+		ft.writeJSONEvent(eventType, message, fields)
+		return
+	}
+	ft.logMessage(eventType + ": " + message)
+}
+
+func (ft *FailoverTest) writeJSONEvent(eventType, message string, fields map[string]any) {
+	event := make(map[string]any, len(fields)+3)
+	for name, value := range fields {
+		event[name] = value
+	}
+	event["timestamp"] = time.Now().Format(time.RFC3339Nano)
+	event["event_type"] = eventType
+	event["message"] = message
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"event_type": "LOG_ERROR", "message": err.Error()})
+	}
+	ft.writeLogEntry(string(data) + "\n")
+}
+
+func (ft *FailoverTest) writeLogEntry(logEntry string) {
 	fmt.Print(logEntry)
 	ft.logFile.WriteString(logEntry)
 	ft.logFile.Sync()
 }
 
 func (ft *FailoverTest) continuousWriter(ctx context.Context) {
+	if ft.writeJitter > 0 {
+		ft.jitteredWriter(ctx)
+		return
+	}
+
 	ticker := time.NewTicker(ft.writeInterval)
 	defer ticker.Stop()
 
@@ -95,29 +300,285 @@ func (ft *FailoverTest) continuousWriter(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			testMap, err := atomix.Map[string, string]("test-map").Codec(generic.Scalar[string]()).Get(ctx)
-			if err != nil {
-				ft.logMessage(fmt.Sprintf("WRITE_ERROR: Failed to get map instance: %v", err))
-				continue
+			ft.writeNext(ctx)
+		}
+	}
+}
+
+// jitteredWriter sleeps writeInterval ± up to writeJitter of it between writes instead of using a ticker
+func (ft *FailoverTest) jitteredWriter(ctx context.Context) {
+	for {
+		factor := 1 + ft.writeJitter*(2*rand.Float64()-1)
+		sleep := time.Duration(float64(ft.writeInterval) * factor)
+		ft.logMessage(fmt.Sprintf("WRITE_SLEEP: %v", sleep))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sleep):
+			ft.writeNext(ctx)
+		}
+	}
+}
+
+// concurrentWriters runs WRITE_CONCURRENCY writers back-to-back (no ticker) sharing writeSeq
+func (ft *FailoverTest) concurrentWriters(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < ft.writeConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				ft.writeNext(ctx)
 			}
+		}()
+	}
 
-			seq := atomic.AddInt64(&ft.writeSeq, 1)
-			key := fmt.Sprintf("seq-%06d", seq)
-			value := fmt.Sprintf("value-%06d-%d", seq, time.Now().Unix())
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ft.throughputReporter(ctx)
+	}()
 
-			start := time.Now()
-			_, err = testMap.Put(ctx, key, value)
-			duration := time.Since(start)
+	wg.Wait()
+}
 
-			if err != nil {
-				ft.logMessage(fmt.Sprintf("WRITE_FAILED: %s -> %s (duration: %v, error: %v)", key, value, duration, err))
-			} else {
-				ft.writeLogMux.Lock()
-				ft.writeLog[key] = value
-				ft.writeLogMux.Unlock()
-				ft.logMessage(fmt.Sprintf("WRITE_SUCCESS: %s -> %s (duration: %v)", key, value, duration))
+func (ft *FailoverTest) throughputReporter(ctx context.Context) {
+	interval := 5 * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last int64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			total := atomic.LoadInt64(&ft.writesCompleted)
+			writes := total - last
+			last = total
+			ft.logMessage(fmt.Sprintf("THROUGHPUT: %.1f writes/sec (%d writes in last %v, %d writers)", float64(writes)/interval.Seconds(), writes, interval, ft.writeConcurrency))
+		}
+	}
+}
+
+// getTestMap returns the cached test-map handle, acquiring it if there isn't one
+func (ft *FailoverTest) getTestMap(ctx context.Context) (valuemap.Map, error) {
+	ft.testMapMux.Lock()
+	defer ft.testMapMux.Unlock()
+
+	if ft.testMap != nil {
+		return ft.testMap, nil
+	}
+
+	testMap, err := valuemap.Open(ctx, ft.mapName, ft.valueType)
+	if err != nil {
+		return nil, err
+	}
+	ft.testMap = testMap
+	return testMap, nil
+}
+
+// resetTestMap drops the cached handle after an operation error so the next operation re-acquires it
+func (ft *FailoverTest) resetTestMap(testMap valuemap.Map) {
+	ft.testMapMux.Lock()
+	defer ft.testMapMux.Unlock()
+
+	if ft.testMap == testMap {
+		ft.testMap = nil
+	}
+}
+
+func (ft *FailoverTest) writeNext(ctx context.Context) {
+	testMap, err := ft.getTestMap(ctx)
+	if err != nil {
+		ft.logMessage(fmt.Sprintf("WRITE_ERROR: Failed to get map instance: %v", err))
+		return
+	}
+
+	seq := atomic.AddInt64(&ft.writeSeq, 1)
+	key := ft.writeKey(seq)
+	value := ft.padValue(ft.testValue(seq))
+
+	start := time.Now()
+	err = ft.putWithRetry(ctx, testMap, key, value)
+	duration := time.Since(start)
+
+	if ft.slowWriteThreshold > 0 && duration > ft.slowWriteThreshold {
+		ft.logEvent("WRITE_SLOW", fmt.Sprintf("%s (latency: %v, threshold: %v, success: %t)", key, duration, ft.slowWriteThreshold, err == nil),
+			map[string]any{"key": key, "latency_ns": duration.Nanoseconds(), "threshold_ns": ft.slowWriteThreshold.Nanoseconds(), "success": err == nil})
+	}
+
+	if err != nil {
+		ft.resetTestMap(testMap)
+		ft.logEvent("WRITE_FAILED", fmt.Sprintf("%s -> %s (duration: %v, category: %s, error: %v)", key, value, duration, valuemap.ErrorCategory(err), err),
+			map[string]any{"key": key, "value": value, "duration_ns": duration.Nanoseconds(), "category": valuemap.ErrorCategory(err), "error": err.Error()})
+	} else {
+		atomic.AddInt64(&ft.writesCompleted, 1)
+		ft.writeLogMux.Lock()
+		// A slow write can land after a trim has already passed its seq
+		late := seq <= ft.trimmedSeq
+		if !late {
+			ft.writeLog[key] = value
+			if ft.maxMapEntries > 0 {
+				ft.insertWriteOrder(seq)
 			}
 		}
+		ft.writeLogMux.Unlock()
+		ft.logEvent("WRITE_SUCCESS", fmt.Sprintf("%s -> %s (duration: %v)", key, value, duration),
+			map[string]any{"key": key, "value": value, "duration_ns": duration.Nanoseconds()})
+
+		if late {
+			ft.removeTrimmed(ctx, testMap, seq, seq)
+		} else if ft.maxMapEntries > 0 {
+			ft.trimMap(ctx, testMap)
+		}
+	}
+}
+
+// trimMap drops the oldest seq keys once writeLog holds more than MAX_MAP_ENTRIES, keeping the map
+// bounded on long soak tests. Every seq up to the cutoff is removed, including failed writes that
+// may have been applied anyway.
+func (ft *FailoverTest) trimMap(ctx context.Context, testMap valuemap.Map) {
+	ft.writeLogMux.Lock()
+	excess := int64(len(ft.writeLog)) - ft.maxMapEntries
+	if excess <= 0 {
+		ft.writeLogMux.Unlock()
+		return
+	}
+	trimmed := ft.writeOrder[:excess]
+	for _, seq := range trimmed {
+		delete(ft.writeLog, ft.writeKey(seq))
+	}
+	from, through := ft.trimmedSeq+1, trimmed[excess-1]
+	ft.writeOrder = ft.writeOrder[excess:]
+	ft.trimmedSeq = through
+	remaining := len(ft.writeLog)
+	ft.writeLogMux.Unlock()
+
+	removed := ft.removeTrimmed(ctx, testMap, from, through)
+	ft.logEvent("MAP_TRIM", fmt.Sprintf("Removed %d keys %s..%s (write log: %d entries, max: %d)", removed, ft.writeKey(from), ft.writeKey(through), remaining, ft.maxMapEntries),
+		map[string]any{"from": ft.writeKey(from), "through": ft.writeKey(through), "removed": removed, "remaining": remaining, "max": ft.maxMapEntries})
+}
+
+// insertWriteOrder adds seq to writeOrder, keeping it sorted. Concurrent writers are acknowledged
+// nearly in seq order, so this only walks back past the few later seqs that finished first. Must be
+// called with writeLogMux held.
+func (ft *FailoverTest) insertWriteOrder(seq int64) {
+	i := len(ft.writeOrder)
+	for i > 0 && ft.writeOrder[i-1] > seq {
+		i--
+	}
+	ft.writeOrder = append(ft.writeOrder, 0)
+	copy(ft.writeOrder[i+1:], ft.writeOrder[i:])
+	ft.writeOrder[i] = seq
+}
+
+// removeTrimmed removes the keys for seqs from..through and returns how many were present
+func (ft *FailoverTest) removeTrimmed(ctx context.Context, testMap valuemap.Map, from, through int64) int {
+	removed := 0
+	for seq := from; seq <= through; seq++ {
+		key := ft.writeKey(seq)
+		err := testMap.Remove(ctx, key)
+		switch {
+		case err == nil:
+			removed++
+		case errors.IsNotFound(err):
+			// Never written, or the write failed without being applied
+		default:
+			ft.logMessage(fmt.Sprintf("MAP_TRIM_FAILED: %s -> %v", key, err))
+		}
+	}
+	return removed
+}
+
+// wasTrimmed reports whether key was removed on purpose by trimMap, so reads of it aren't data loss
+func (ft *FailoverTest) wasTrimmed(key string) bool {
+	ft.writeLogMux.RLock()
+	defer ft.writeLogMux.RUnlock()
+	return ft.trimmedSeq > 0 && keySeq(key) <= ft.trimmedSeq
+}
+
+// keySeq parses the sequence number out of a seq-N key, or returns 0
+func keySeq(key string) int64 {
+	seq, _ := strconv.ParseInt(strings.TrimPrefix(key, "seq-"), 10, 64)
+	return seq
+}
+
+// writeKey returns the key written for seq, cycling through MAX_KEYS keys when it is set. Values still
+// carry the full sequence number, so overwritten keys stay distinguishable.
+func (ft *FailoverTest) writeKey(seq int64) string {
+	if ft.maxKeys > 0 {
+		seq = (seq-1)%ft.maxKeys + 1
+	}
+	return fmt.Sprintf("seq-%06d", seq)
+}
+
+// mapSizeMonitor logs how many entries the test map holds every MAP_SIZE_INTERVAL, to catch unbounded growth
+func (ft *FailoverTest) mapSizeMonitor(ctx context.Context) {
+	ticker := time.NewTicker(ft.mapSizeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ft.logMessage("MAP_SIZE: " + ft.mapSizeLabel(ctx))
+		}
+	}
+}
+
+// mapSizeLabel describes the test map's entry count for MAP_SIZE and the COMPLETED line
+func (ft *FailoverTest) mapSizeLabel(ctx context.Context) string {
+	testMap, err := ft.getTestMap(ctx)
+	if err != nil {
+		return fmt.Sprintf("unknown (failed to get map instance: %v)", err)
+	}
+	size, err := testMap.Len(ctx)
+	if err != nil {
+		ft.resetTestMap(testMap)
+		return fmt.Sprintf("unknown (%v)", err)
+	}
+	return fmt.Sprintf("%d entries", size)
+}
+
+// testValue returns the value written for seq: "value-<seq>-<unix>" for strings, or a number with
+// the sequence in its high digits for the numeric VALUE_TYPEs
+func (ft *FailoverTest) testValue(seq int64) string {
+	now := time.Now().Unix()
+	switch ft.valueType {
+	case "int64":
+		return strconv.FormatInt(seq*10_000_000_000+now, 10)
+	case "float64":
+		return strconv.FormatFloat(float64(seq)+float64(now%1_000_000)/1_000_000, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("value-%06d-%d", seq, now)
+	}
+}
+
+// putWithRetry retries transient (unavailable/timeout) Put failures with exponential backoff,
+// giving up once the next backoff would overrun the write interval
+func (ft *FailoverTest) putWithRetry(ctx context.Context, testMap valuemap.Map, key, value string) error {
+	deadline := time.Now().Add(ft.writeInterval)
+	backoff := 50 * time.Millisecond
+
+	for attempt := 1; ; attempt++ {
+		err := testMap.Put(ctx, key, value)
+		if err == nil || !(errors.IsUnavailable(err) || errors.IsTimeout(err)) {
+			return err
+		}
+		if time.Now().Add(backoff).After(deadline) {
+			return err
+		}
+
+		ft.logMessage(fmt.Sprintf("WRITE_RETRY: %s attempt %d failed, retrying in %v (error: %v)", key, attempt, backoff, err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
 	}
 }
 
@@ -143,125 +604,269 @@ func (ft *FailoverTest) continuousReader(ctx context.Context) {
 			ft.writeLogMux.RUnlock()
 
 			if len(keys) > 0 {
-				testMap, err := atomix.Map[string, string]("test-map").Codec(generic.Scalar[string]()).Get(ctx)
+				testMap, err := ft.getTestMap(ctx)
 				if err != nil {
 					ft.logMessage(fmt.Sprintf("READ_ERROR: Failed to get map instance: %v", err))
 					continue
 				}
 
 				recentKey := keys[len(keys)-1]
-				expectedValue := ft.writeLog[recentKey]
+				ft.writeLogMux.RLock()
+				expectedValue, ok := ft.writeLog[recentKey]
+				ft.writeLogMux.RUnlock()
+				if !ok {
+					// Trimmed since the keys were collected
+					continue
+				}
 
 				start := time.Now()
 				entry, err := testMap.Get(ctx, recentKey)
 				duration := time.Since(start)
 
-				if err != nil {
-					ft.logMessage(fmt.Sprintf("READ_FAILED: %s (duration: %v, error: %v)", recentKey, duration, err))
+				if (errors.IsNotFound(err) || (err == nil && entry == nil)) && ft.wasTrimmed(recentKey) {
+					ft.logEvent("READ_TRIMMED", fmt.Sprintf("%s -> key trimmed by MAX_MAP_ENTRIES during the read (duration: %v)", recentKey, duration),
+						map[string]any{"key": recentKey, "duration_ns": duration.Nanoseconds()})
+				} else if err != nil {
+					ft.resetTestMap(testMap)
+					ft.logEvent("READ_FAILED", fmt.Sprintf("%s (duration: %v, category: %s, error: %v)", recentKey, duration, valuemap.ErrorCategory(err), err),
+						map[string]any{"key": recentKey, "duration_ns": duration.Nanoseconds(), "category": valuemap.ErrorCategory(err), "error": err.Error()})
 				} else if entry == nil {
-					ft.logMessage(fmt.Sprintf("READ_FAILED: %s -> key not found (duration: %v)", recentKey, duration))
+					ft.logEvent("READ_FAILED", fmt.Sprintf("%s -> key not found (duration: %v, category: %s)", recentKey, duration, valuemap.CategoryNotFound),
+						map[string]any{"key": recentKey, "duration_ns": duration.Nanoseconds(), "category": valuemap.CategoryNotFound, "error": "key not found"})
 				} else if entry.Value != expectedValue {
-					ft.logMessage(fmt.Sprintf("READ_INCONSISTENT: %s -> got '%s', expected '%s' (duration: %v)", recentKey, entry.Value, expectedValue, duration))
+					ft.logEvent("READ_INCONSISTENT", fmt.Sprintf("%s -> got '%s', expected '%s' (duration: %v)", recentKey, entry.Value, expectedValue, duration),
+						map[string]any{"key": recentKey, "value": entry.Value, "expected": expectedValue, "duration_ns": duration.Nanoseconds()})
 				} else {
-					ft.logMessage(fmt.Sprintf("READ_SUCCESS: %s -> %s (duration: %v)", recentKey, entry.Value, duration))
+					ft.logEvent("READ_SUCCESS", fmt.Sprintf("%s -> %s (duration: %v)", recentKey, entry.Value, duration),
+						map[string]any{"key": recentKey, "value": entry.Value, "duration_ns": duration.Nanoseconds()})
 				}
 			}
 		}
 	}
 }
 
-func (ft *FailoverTest) leaderMonitor(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
+func (ft *FailoverTest) continuousCounterWriter(ctx context.Context) {
+	ticker := time.NewTicker(ft.writeInterval)
 	defer ticker.Stop()
 
-	raftGroupGVR := schema.GroupVersionResource{
-		Group:    "consensus.atomix.io",
-		Version:  "v1beta1",
-		Resource: "raftgroups",
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			counter, err := atomix.Counter("test-counter").Get(ctx)
+			if err != nil {
+				ft.logMessage(fmt.Sprintf("COUNTER_WRITE_ERROR: Failed to get counter instance: %v", err))
+				continue
+			}
+
+			start := time.Now()
+			value, err := counter.Increment(ctx, 1)
+			duration := time.Since(start)
+
+			if err != nil {
+				ft.logMessage(fmt.Sprintf("COUNTER_WRITE_FAILED: (duration: %v, category: %s, error: %v)", duration, valuemap.ErrorCategory(err), err))
+				continue
+			}
+			ft.observeCounter(value)
+			ft.logMessage(fmt.Sprintf("COUNTER_WRITE: %d (duration: %v)", value, duration))
+		}
 	}
+}
+
+func (ft *FailoverTest) continuousCounterReader(ctx context.Context) {
+	ticker := time.NewTicker(ft.readInterval)
+	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			raftGroups, err := ft.dynamicClient.Resource(raftGroupGVR).Namespace(ft.namespace).List(ctx, metav1.ListOptions{
-				LabelSelector: "atomix.io/store=consensus-store",
-			})
+			counter, err := atomix.Counter("test-counter").Get(ctx)
 			if err != nil {
-				ft.logMessage(fmt.Sprintf("LEADER_ERROR: Failed to list RaftGroups: %v", err))
+				ft.logMessage(fmt.Sprintf("COUNTER_READ_ERROR: Failed to get counter instance: %v", err))
 				continue
 			}
 
-			var leaderInfo []string
+			// Any value acknowledged before the read was issued must be visible to it
+			expectedMin := atomic.LoadInt64(&ft.counterHigh)
 
-			for _, item := range raftGroups.Items {
-				groupName := item.GetName()
+			start := time.Now()
+			value, err := counter.Get(ctx)
+			duration := time.Since(start)
 
-				partNum, err := strconv.Atoi(strings.Split(groupName, "-")[2])
-				if err != nil {
-					ft.logMessage(fmt.Sprintf("LEADER_ERROR: Failed to convert group name to int: %v", err))
-				}
+			if err != nil {
+				ft.logMessage(fmt.Sprintf("COUNTER_READ_FAILED: (duration: %v, category: %s, error: %v)", duration, valuemap.ErrorCategory(err), err))
+				continue
+			}
+			if value < expectedMin {
+				atomic.AddInt64(&ft.regressions, 1)
+				ft.logMessage(fmt.Sprintf("COUNTER_REGRESSION: got %d, previously observed %d (duration: %v)", value, expectedMin, duration))
+				continue
+			}
+			ft.observeCounter(value)
+			ft.logMessage(fmt.Sprintf("COUNTER_READ: %d (duration: %v)", value, duration))
+		}
+	}
+}
 
-				status, found, err := unstructured.NestedMap(item.Object, "status")
-				if err != nil || !found {
-					ft.logMessage(fmt.Sprintf("LEADER_ERROR: Failed to get status for RaftGroup %s: %v", groupName, err))
-					continue
-				}
+// observeCounter raises the highest counter value seen so far.
+func (ft *FailoverTest) observeCounter(value int64) {
+	for {
+		high := atomic.LoadInt64(&ft.counterHigh)
+		if value <= high || atomic.CompareAndSwapInt64(&ft.counterHigh, high, value) {
+			return
+		}
+	}
+}
 
-				leader, found, err := unstructured.NestedMap(status, "leader")
-				if err != nil || !found {
-					leaderInfo = append(leaderInfo, fmt.Sprintf("Partition %d: No Leader", partNum))
-					continue
-				}
+func (ft *FailoverTest) leaderMonitor(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
 
-				leaderName, found, err := unstructured.NestedString(leader, "name")
-				if err != nil || !found {
-					leaderInfo = append(leaderInfo, fmt.Sprintf("%s:unknown-leader", groupName))
-					continue
-				}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			leaders, err := ft.raftWatcher.Leaders(ctx)
+			if err != nil {
+				ft.logMessage(fmt.Sprintf("LEADER_ERROR: %v", err))
+				continue
+			}
 
-				term, found, err := unstructured.NestedInt64(status, "term")
-				if err != nil || !found {
-					term = 0
+			if unparseable := ft.raftWatcher.UnparseableGroups(); len(unparseable) != ft.unparseableGroups {
+				ft.unparseableGroups = len(unparseable)
+				if len(unparseable) > 0 {
+					ft.logMessage(fmt.Sprintf("WARNING: %d RaftGroup(s) ignored because their names don't match GROUP_NAME_PATTERN %s: %s", len(unparseable), ft.raftWatcher.GroupPattern(), strings.Join(unparseable, ", ")))
 				}
+			}
+
+			partitions := make([]int, 0, len(leaders))
+			for partNum := range leaders {
+				partitions = append(partitions, partNum)
+			}
+			sort.Ints(partitions)
 
-				state, found, err := unstructured.NestedString(status, "state")
-				if err != nil || !found {
-					state = "unknown"
+			var leaderInfo []string
+			claimedBy := make(map[string]int)
+
+			for _, partNum := range partitions {
+				leader := leaders[partNum]
+				if leader.MemberName == "" {
+					leaderInfo = append(leaderInfo, fmt.Sprintf("Partition %d: No Leader", partNum))
+					continue
 				}
 
-				podNum, err := strconv.Atoi(strings.Split(leaderName, "-")[3])
+				ft.checkSplitBrain(partNum, leader.Term, leader.MemberName, claimedBy)
+				podName, err := ft.raftWatcher.LeaderPod(leader)
 				if err != nil {
-					ft.logMessage(fmt.Sprintf("LEADER_ERROR: Failed to get pod number: %v", err))
+					podName = "unknown"
 				}
-				leaderInfo = append(leaderInfo, fmt.Sprintf("Partition %d: Pod %d (term: %d, %s)", partNum, podNum-1, term, state))
+				leaderInfo = append(leaderInfo, fmt.Sprintf("Partition %d: Pod %s (term: %d, %s)", partNum, podName, leader.Term, leader.State))
 			}
 
-			newStatus := fmt.Sprintf("%s", strings.Join(leaderInfo, ", "))
+			newStatus := strings.Join(leaderInfo, ", ")
 
 			ft.leaderMux.Lock()
 			if ft.leaderStatus != newStatus {
 				ft.logMessage(fmt.Sprintf("LEADER_CHANGE: %s", newStatus))
 				ft.leaderStatus = newStatus
 			}
+			ft.leaders = leaders
 			ft.leaderMux.Unlock()
 		}
 	}
 }
 
+// autoFailover terminates the leader of the next partition in rotation every AUTO_FAILOVER_INTERVAL,
+// using the leaders leaderMonitor last observed
+func (ft *FailoverTest) autoFailover(ctx context.Context) {
+	ticker := time.NewTicker(ft.autoFailoverInterval)
+	defer ticker.Stop()
+
+	next := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ft.leaderMux.RLock()
+			partitions := make([]int, 0, len(ft.leaders))
+			for partNum, leader := range ft.leaders {
+				if leader.MemberName != "" {
+					partitions = append(partitions, partNum)
+				}
+			}
+			sort.Ints(partitions)
+			var leader raftwatch.LeaderInfo
+			if len(partitions) > 0 {
+				leader = ft.leaders[partitions[next%len(partitions)]]
+			}
+			ft.leaderMux.RUnlock()
+
+			if len(partitions) == 0 {
+				ft.logMessage("AUTO_FAILOVER_SKIPPED: No known partition leaders")
+				continue
+			}
+			next++
+
+			podName, err := ft.raftWatcher.LeaderPod(leader)
+			if err != nil {
+				ft.logMessage(fmt.Sprintf("TERMINATION_FAILED: %v", err))
+				continue
+			}
+			ft.logMessage(fmt.Sprintf("FORCED_TERMINATION: Terminating leader pod %s (member %s) for partition %d", podName, leader.MemberName, leader.PartitionID))
+			if err := ft.raftWatcher.TerminateLeader(ctx, leader); err != nil {
+				ft.logMessage(fmt.Sprintf("TERMINATION_FAILED: %v", err))
+				continue
+			}
+			ft.logMessage(fmt.Sprintf("TERMINATION_SUCCESS: Pod %s terminated", podName))
+		}
+	}
+}
+
+// checkSplitBrain flags a partition reporting a different leader for a term it already has a
+// leader for, and a leader name claimed by two partitions in the same sample. claimedBy maps
+// leader names to the partition that claimed them earlier in the current sample.
+func (ft *FailoverTest) checkSplitBrain(partNum int, term int64, leaderName string, claimedBy map[string]int) {
+	if term > 0 {
+		leaders, ok := ft.termLeaders[partNum]
+		if !ok {
+			leaders = make(map[int64]string)
+			ft.termLeaders[partNum] = leaders
+		}
+		if previous, ok := leaders[term]; !ok {
+			leaders[term] = leaderName
+		} else if previous != leaderName {
+			atomic.AddInt64(&ft.splitBrains, 1)
+			ft.logMessage(fmt.Sprintf("SPLIT_BRAIN: Partition %d term %d reported conflicting leaders %s and %s", partNum, term, previous, leaderName))
+		}
+	}
+
+	if other, ok := claimedBy[leaderName]; ok && other != partNum {
+		atomic.AddInt64(&ft.splitBrains, 1)
+		ft.logMessage(fmt.Sprintf("SPLIT_BRAIN: %s claimed as leader by partitions %d and %d", leaderName, other, partNum))
+	}
+	claimedBy[leaderName] = partNum
+}
+
 func (ft *FailoverTest) runTest(ctx context.Context) error {
 	ft.logMessage("STARTING Atomix Failover Capability Test")
-	ft.logMessage(fmt.Sprintf("CONFIG: Write interval: %v, Read interval: %v, Test duration: %v", ft.writeInterval, ft.readInterval, ft.testDuration))
+	ft.logMessage(fmt.Sprintf("CONFIG: Mode: %s, Store: %s, Primitive: %s, Map: %s, Value type: %s, Value size: %s, Write interval: %v, Write jitter: %.2f, Write concurrency: %d, Read interval: %v, Test duration: %v, Auto failover interval: %v, Slow write threshold: %v, Max keys: %s, Max map entries: %s", ft.testMode, ft.raftWatcher.StoreName(), ft.testPrimitive, ft.mapName, ft.valueType, valuemap.SizeLabel(ft.valueSize), ft.writeInterval, ft.writeJitter, ft.writeConcurrency, ft.readInterval, ft.testDuration, ft.autoFailoverInterval, ft.slowWriteThreshold, maxKeysLabel(ft.maxKeys), maxKeysLabel(ft.maxMapEntries)))
 
-	testMap, err := atomix.Map[string, string]("test-map").Codec(generic.Scalar[string]()).Get(ctx)
+	testMap, err := ft.getTestMap(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to initialize test map: %v", err)
 	}
 
 	initialKey := "test-connectivity"
 	initialValue := fmt.Sprintf("initialized-%d", time.Now().Unix())
-	_, err = testMap.Put(ctx, initialKey, initialValue)
+	if ft.valueType != "string" {
+		initialValue = ft.testValue(0)
+	}
+	err = testMap.Put(ctx, initialKey, initialValue)
 	if err != nil {
 		return fmt.Errorf("failed initial connectivity test: %v", err)
 	}
@@ -281,6 +886,10 @@ func (ft *FailoverTest) runTest(ctx context.Context) error {
 
 	ft.logMessage("CONNECTIVITY: Initial connectivity and consistency verified")
 
+	if ft.testMode == "benchmark" {
+		return ft.runBenchmark(ctx)
+	}
+
 	var wg sync.WaitGroup
 
 	testCtx, cancel := context.WithTimeout(ctx, ft.testDuration)
@@ -289,12 +898,22 @@ func (ft *FailoverTest) runTest(ctx context.Context) error {
 	wg.Add(3)
 	go func() {
 		defer wg.Done()
-		ft.continuousWriter(testCtx)
+		if ft.testPrimitive == "counter" {
+			ft.continuousCounterWriter(testCtx)
+		} else if ft.writeConcurrency > 1 {
+			ft.concurrentWriters(testCtx)
+		} else {
+			ft.continuousWriter(testCtx)
+		}
 	}()
 
 	go func() {
 		defer wg.Done()
-		ft.continuousReader(testCtx)
+		if ft.testPrimitive == "counter" {
+			ft.continuousCounterReader(testCtx)
+		} else {
+			ft.continuousReader(testCtx)
+		}
 	}()
 
 	go func() {
@@ -302,37 +921,258 @@ func (ft *FailoverTest) runTest(ctx context.Context) error {
 		ft.leaderMonitor(testCtx)
 	}()
 
+	if ft.autoFailoverInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ft.autoFailover(testCtx)
+		}()
+	}
+
+	if ft.testPrimitive == "map" && ft.mapSizeInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ft.mapSizeMonitor(testCtx)
+		}()
+	}
+
 	wg.Wait()
 
-	ft.writeLogMux.RLock()
-	totalWrites := len(ft.writeLog)
-	ft.writeLogMux.RUnlock()
+	if ft.testPrimitive == "counter" {
+		ft.logMessage(fmt.Sprintf("COMPLETED: Test finished. Highest counter value: %d, Regressions: %d, Split-brain detections: %d", atomic.LoadInt64(&ft.counterHigh), atomic.LoadInt64(&ft.regressions), atomic.LoadInt64(&ft.splitBrains)))
+		return nil
+	}
+
+	// Count writes rather than writeLog keys, which MAX_KEYS reuses
+	totalWrites := atomic.LoadInt64(&ft.writesCompleted)
+
+	ft.logMessage(fmt.Sprintf("COMPLETED: Test finished. Total successful writes: %d, Final sequence: %d, Split-brain detections: %d, Map size: %s", totalWrites, atomic.LoadInt64(&ft.writeSeq), atomic.LoadInt64(&ft.splitBrains), ft.mapSizeLabel(ctx)))
 
-	ft.logMessage(fmt.Sprintf("COMPLETED: Test finished. Total successful writes: %d, Final sequence: %d", totalWrites, atomic.LoadInt64(&ft.writeSeq)))
+	ft.verifyAllWrites(ctx)
 
 	return nil
 }
 
-func (ft *FailoverTest) Close() {
-	if ft.logFile != nil {
-		ft.logFile.Close()
+// runBenchmark measures raw Put/Get throughput and latency with no failover. Operations are logged in
+// the same WRITE_*/READ_* format as the failover test, so analyze-logs.go computes the same latency
+// statistics and CSV for both and the runs can be compared directly.
+func (ft *FailoverTest) runBenchmark(ctx context.Context) error {
+	ft.logMessage(fmt.Sprintf("BENCHMARK_CONFIG: Clients: %d, Keys: %d, Read ratio: %.2f, Value size: %s, Duration: %v",
+		ft.benchmarkClients, ft.benchmarkKeys, ft.benchmarkReadRatio, valuemap.SizeLabel(ft.valueSize), ft.testDuration))
+	if ft.autoFailoverInterval > 0 {
+		ft.logMessage("BENCHMARK_CONFIG: AUTO_FAILOVER_INTERVAL is ignored in benchmark mode")
 	}
+
+	// Populate every key first so reads during the timed run never miss
+	testMap, err := ft.getTestMap(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get map instance: %v", err)
+	}
+	for i := 1; i <= ft.benchmarkKeys; i++ {
+		if err := testMap.Put(ctx, benchmarkKey(i), ft.benchmarkValue()); err != nil {
+			return fmt.Errorf("failed to populate benchmark keys: %v", err)
+		}
+	}
+	ft.logMessage(fmt.Sprintf("BENCHMARK_POPULATED: %d keys", ft.benchmarkKeys))
+
+	testCtx, cancel := context.WithTimeout(ctx, ft.testDuration)
+	defer cancel()
+
+	var writes, writeErrors, reads, readErrors int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < ft.benchmarkClients; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for testCtx.Err() == nil {
+				key := benchmarkKey(rand.Intn(ft.benchmarkKeys) + 1)
+				if rand.Float64() < ft.benchmarkReadRatio {
+					if ok, counted := ft.benchmarkRead(testCtx, key); counted {
+						atomic.AddInt64(&reads, 1)
+						if !ok {
+							atomic.AddInt64(&readErrors, 1)
+						}
+					}
+				} else if ok, counted := ft.benchmarkWrite(testCtx, key); counted {
+					atomic.AddInt64(&writes, 1)
+					if !ok {
+						atomic.AddInt64(&writeErrors, 1)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ft.leaderMonitor(testCtx)
+	}()
+
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	ops := writes + reads
+	ft.logMessage(fmt.Sprintf("BENCHMARK_SUMMARY: %d ops in %v (%.1f ops/sec), Writes: %d (%.1f/sec, %s errors), Reads: %d (%.1f/sec, %s errors)",
+		ops, elapsed.Round(time.Millisecond), float64(ops)/elapsed.Seconds(),
+		writes, float64(writes)/elapsed.Seconds(), errorRate(writeErrors, writes),
+		reads, float64(reads)/elapsed.Seconds(), errorRate(readErrors, reads)))
+	ft.logMessage(fmt.Sprintf("COMPLETED: Benchmark finished. Total operations: %d, Errors: %d, Split-brain detections: %d",
+		ops, writeErrors+readErrors, atomic.LoadInt64(&ft.splitBrains)))
+	return nil
+}
+
+// benchmarkKey uses the failover test's seq-N key format so the analyzer parses benchmark operations
+func benchmarkKey(i int) string {
+	return fmt.Sprintf("seq-%06d", i)
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
+func (ft *FailoverTest) benchmarkValue() string {
+	return ft.padValue(ft.testValue(atomic.AddInt64(&ft.writeSeq, 1)))
+}
+
+// padValue pads a string value with '.' up to VALUE_SIZE bytes. The generated prefix is kept intact so
+// the analyzer can still parse sequence numbers, and reads compare the whole value.
+func (ft *FailoverTest) padValue(value string) string {
+	if len(value) >= ft.valueSize {
 		return value
 	}
-	return defaultValue
+	return value + strings.Repeat(".", ft.valueSize-len(value))
+}
+
+// benchmarkWrite puts one value and logs it. counted is false for operations cut off by the end of the run.
+func (ft *FailoverTest) benchmarkWrite(ctx context.Context, key string) (ok, counted bool) {
+	testMap, err := ft.getTestMap(ctx)
+	if err != nil {
+		return false, ctx.Err() == nil
+	}
+
+	value := ft.benchmarkValue()
+	start := time.Now()
+	err = testMap.Put(ctx, key, value)
+	duration := time.Since(start)
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, false
+		}
+		ft.resetTestMap(testMap)
+		ft.logEvent("WRITE_FAILED", fmt.Sprintf("%s -> %s (duration: %v, category: %s, error: %v)", key, value, duration, valuemap.ErrorCategory(err), err),
+			map[string]any{"key": key, "value": value, "duration_ns": duration.Nanoseconds(), "category": valuemap.ErrorCategory(err), "error": err.Error()})
+		return false, true
+	}
+	ft.logEvent("WRITE_SUCCESS", fmt.Sprintf("%s -> %s (duration: %v)", key, value, duration),
+		map[string]any{"key": key, "value": value, "duration_ns": duration.Nanoseconds()})
+	return true, true
+}
+
+// benchmarkRead gets one key and logs it. Values aren't checked, since concurrent clients overwrite
+// the same keys; counted is false for operations cut off by the end of the run.
+func (ft *FailoverTest) benchmarkRead(ctx context.Context, key string) (ok, counted bool) {
+	testMap, err := ft.getTestMap(ctx)
+	if err != nil {
+		return false, ctx.Err() == nil
+	}
+
+	start := time.Now()
+	entry, err := testMap.Get(ctx, key)
+	duration := time.Since(start)
+	switch {
+	case err != nil:
+		if ctx.Err() != nil {
+			return false, false
+		}
+		ft.resetTestMap(testMap)
+		ft.logEvent("READ_FAILED", fmt.Sprintf("%s (duration: %v, category: %s, error: %v)", key, duration, valuemap.ErrorCategory(err), err),
+			map[string]any{"key": key, "duration_ns": duration.Nanoseconds(), "category": valuemap.ErrorCategory(err), "error": err.Error()})
+		return false, true
+	case entry == nil:
+		ft.logEvent("READ_FAILED", fmt.Sprintf("%s -> key not found (duration: %v, category: %s)", key, duration, valuemap.CategoryNotFound),
+			map[string]any{"key": key, "duration_ns": duration.Nanoseconds(), "category": valuemap.CategoryNotFound, "error": "key not found"})
+		return false, true
+	}
+	ft.logEvent("READ_SUCCESS", fmt.Sprintf("%s -> %s (duration: %v)", key, entry.Value, duration),
+		map[string]any{"key": key, "value": entry.Value, "duration_ns": duration.Nanoseconds()})
+	return true, true
 }
 
-func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if seconds, err := strconv.Atoi(value); err == nil {
-			return time.Duration(seconds) * time.Second
+func errorRate(failed, total int64) string {
+	if total == 0 {
+		return "0.00%"
+	}
+	return fmt.Sprintf("%.2f%%", float64(failed)/float64(total)*100)
+}
+
+// verifyAllWrites reads back every acknowledged write in writeLog and reports which keys are
+// missing or hold a different value
+func (ft *FailoverTest) verifyAllWrites(ctx context.Context) {
+	ft.writeLogMux.RLock()
+	expected := make(map[string]string, len(ft.writeLog))
+	for key, value := range ft.writeLog {
+		expected[key] = value
+	}
+	ft.writeLogMux.RUnlock()
+
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ft.logMessage(fmt.Sprintf("FINAL_VERIFY_START: Reading back %d acknowledged writes", len(keys)))
+	ft.writeLogMux.RLock()
+	trimmedSeq := ft.trimmedSeq
+	ft.writeLogMux.RUnlock()
+	if trimmedSeq > 0 {
+		ft.logMessage(fmt.Sprintf("FINAL_VERIFY_TRIMMED: %s..%s were trimmed by MAX_MAP_ENTRIES and are not checked", ft.writeKey(1), ft.writeKey(trimmedSeq)))
+	}
+
+	var matched, missing, mismatched, failed int
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			break
+		}
+
+		testMap, err := ft.getTestMap(ctx)
+		if err != nil {
+			failed++
+			ft.logMessage(fmt.Sprintf("FINAL_VERIFY_ERROR: %s -> failed to get map instance: %v", key, err))
+			continue
+		}
+
+		entry, err := testMap.Get(ctx, key)
+		switch {
+		case errors.IsNotFound(err) || (err == nil && entry == nil):
+			missing++
+			ft.logMessage(fmt.Sprintf("FINAL_VERIFY_MISSING: %s -> expected '%s'", key, expected[key]))
+		case err != nil:
+			failed++
+			ft.resetTestMap(testMap)
+			ft.logMessage(fmt.Sprintf("FINAL_VERIFY_ERROR: %s -> %v", key, err))
+		case entry.Value != expected[key]:
+			mismatched++
+			ft.logMessage(fmt.Sprintf("FINAL_VERIFY_MISMATCH: %s -> got '%s', expected '%s'", key, entry.Value, expected[key]))
+		default:
+			matched++
 		}
 	}
-	return defaultValue
+
+	ft.logMessage(fmt.Sprintf("FINAL_VERIFY: Total: %d, Matched: %d, Missing: %d, Mismatched: %d, Errors: %d", len(keys), matched, missing, mismatched, failed))
+}
+
+func (ft *FailoverTest) Close() {
+	if ft.logFile != nil {
+		ft.logFile.Close()
+	}
+}
+
+// maxKeysLabel describes MAX_KEYS or MAX_MAP_ENTRIES for the CONFIG line
+func maxKeysLabel(maxKeys int64) string {
+	if maxKeys == 0 {
+		return "unbounded"
+	}
+	return strconv.FormatInt(maxKeys, 10)
 }
 
 func main() {
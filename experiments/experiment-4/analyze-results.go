@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"regexp"
 	"sort"
@@ -20,6 +22,19 @@ type TestResult struct {
 	RecoveryTime time.Duration
 	WriteTime    time.Time
 	FailureTime  time.Time
+
+	// InvalidRecovery marks a missing, unparseable, zero or negative recovery duration
+	InvalidRecovery bool
+}
+
+type ImmediateResult struct {
+	TestID              string
+	Scenario            string
+	ImmediateSuccess    bool
+	ImmediateError      string
+	PostRecoverySuccess bool
+	Duration            time.Duration
+	RecoveryTime        time.Duration
 }
 
 type ScenarioStats struct {
@@ -31,94 +46,229 @@ type ScenarioStats struct {
 	AvgRecovery time.Duration
 	MinRecovery time.Duration
 	MaxRecovery time.Duration
+
+	// Successful tests excluded from the recovery aggregates because their recovery time was invalid
+	InvalidRecoveries int
+
+	ImmediateTests               int
+	ImmediateReadSuccess         int
+	ImmediatePostRecoverySuccess int
+	ImmediateReadRate            float64
+	ImmediatePostRecoveryRate    float64
+}
+
+// RecoveryDistribution summarizes valid recovery times across every test, so bimodal or long-tailed
+// recovery shows up where an average would hide it
+type RecoveryDistribution struct {
+	Count   int
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Buckets []RecoveryBucket
 }
 
+// RecoveryBucket counts recoveries up to and including Upper; the last bucket has no upper bound (Upper is 0)
+type RecoveryBucket struct {
+	Upper time.Duration
+	Count int
+}
+
+var recoveryBucketBounds = []time.Duration{
+	250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second,
+	6 * time.Second, 8 * time.Second, 10 * time.Second, 15 * time.Second, 30 * time.Second,
+}
+
+// scenarioNames maps the numeric FailoverTestScenario values logged by main.go to their names
+var scenarioNames = []string{"ImmediateFailure", "DuringReplication", "RapidSequential", "PrecisionTimed", "MultiPartition"}
+
 func main() {
 	logFile := "enhanced-failover-test-results.log"
 	if len(os.Args) > 1 {
 		logFile = os.Args[1]
 	}
 
-	results, err := parseLogFile(logFile)
+	results, immediateResults, err := parseLogFile(logFile)
 	if err != nil {
 		log.Fatalf("Failed to parse log file: %v", err)
 	}
 
-	stats := analyzeResults(results)
-	generateReport(stats, results)
+	stats := analyzeResults(results, immediateResults)
+	distribution := recoveryDistribution(results, immediateResults)
+	generateReport(stats, distribution, results, immediateResults)
+}
+
+// resultEvent is one log line the analyzer uses, from either log format
+type resultEvent struct {
+	Timestamp time.Time
+	Type      string
+	TestID    string
+	Scenario  string
+	Error     string
+	Duration  time.Duration
+}
+
+// jsonEvent is one line of a LOG_FORMAT=jsonl log; test events carry their details as fields
+type jsonEvent struct {
+	Timestamp  time.Time `json:"timestamp"`
+	EventType  string    `json:"event_type"`
+	TestID     string    `json:"test_id"`
+	Scenario   int       `json:"scenario"`
+	DurationNs int64     `json:"duration_ns"`
+	Error      string    `json:"error"`
+}
+
+func parseJSONEvent(line string) *resultEvent {
+	var event jsonEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil || event.TestID == "" {
+		return nil
+	}
+	return &resultEvent{
+		Timestamp: event.Timestamp,
+		Type:      event.EventType,
+		TestID:    event.TestID,
+		Scenario:  scenarioName(strconv.Itoa(event.Scenario)),
+		Error:     event.Error,
+		Duration:  time.Duration(event.DurationNs),
+	}
+}
+
+// Text log patterns, one per event type the analyzer uses; the capture groups are the test ID and
+// then the scenario, error or duration
+var textEventPatterns = map[string]*regexp.Regexp{
+	"PRECISION_TEST_START":      regexp.MustCompile(`PRECISION_TEST_START: (test-\d+), Scenario: (\w+)`),
+	"WRITE_COMPLETE":            regexp.MustCompile(`WRITE_COMPLETE: ((?:imm-)?test-\d+) \(duration: ([^)]+)\)`),
+	"LEADER_RECOVERY":           regexp.MustCompile(`LEADER_RECOVERY: ((?:imm-)?test-\d+) \(duration: ([^)]+)\)`),
+	"PRECISION_SUCCESS":         regexp.MustCompile(`PRECISION_SUCCESS: (test-\d+) verified \(total duration: ([^)]+)\)`),
+	"IMMEDIATE_READ_TEST_START": regexp.MustCompile(`IMMEDIATE_READ_TEST_START: (imm-test-\d+), Scenario: (\w+)`),
+	"IMMEDIATE_READ_SUCCESS":    regexp.MustCompile(`IMMEDIATE_READ_SUCCESS: (imm-test-\d+)`),
+	"IMMEDIATE_READ_FAILED":     regexp.MustCompile(`IMMEDIATE_READ_FAILED: (imm-test-\d+) - (.+)`),
+	"IMMEDIATE_TEST_COMPLETE":   regexp.MustCompile(`IMMEDIATE_TEST_COMPLETE: (imm-test-\d+) - Post-recovery: success, Immediate: \w+ \(total duration: ([^)]+)\)`),
+}
+
+// textLinePattern splits a text log line into its timestamp and event type
+var textLinePattern = regexp.MustCompile(`^\[([^\]]+)\] ([A-Z][A-Z0-9_]*):`)
+
+func parseTextEvent(line string) *resultEvent {
+	lineMatch := textLinePattern.FindStringSubmatch(line)
+	if lineMatch == nil {
+		return nil
+	}
+	eventType := lineMatch[2]
+	pattern, ok := textEventPatterns[eventType]
+	if !ok {
+		return nil
+	}
+	match := pattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	event := &resultEvent{Type: eventType, TestID: match[1]}
+	event.Timestamp, _ = time.Parse("2006-01-02 15:04:05.000", lineMatch[1])
+	switch eventType {
+	case "PRECISION_TEST_START", "IMMEDIATE_READ_TEST_START":
+		event.Scenario = scenarioName(match[2])
+	case "IMMEDIATE_READ_FAILED":
+		event.Error = match[2]
+	case "WRITE_COMPLETE", "LEADER_RECOVERY", "PRECISION_SUCCESS", "IMMEDIATE_TEST_COMPLETE":
+		event.Duration = parseDuration(match[2])
+	}
+	return event
 }
 
-func parseLogFile(filename string) ([]TestResult, error) {
+func parseLogFile(filename string) ([]TestResult, []ImmediateResult, error) {
 	file, err := os.Open(filename)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer file.Close()
 
 	var results []TestResult
 	testMap := make(map[string]*TestResult)
 
-	scanner := bufio.NewScanner(file)
-
-	// Regex patterns for different log events
-	testStartPattern := regexp.MustCompile(`PRECISION_TEST_START: (test-\d+), Scenario: (\w+)`)
-	writeCompletePattern := regexp.MustCompile(`WRITE_COMPLETE: (test-\d+) \(duration: ([^)]+)\)`)
-	terminationPattern := regexp.MustCompile(`TERMINATION_SUCCESS: Pod (.+) terminated`)
-	recoveryPattern := regexp.MustCompile(`LEADER_RECOVERY: (test-\d+) \(duration: ([^)]+)\)`)
-	successPattern := regexp.MustCompile(`PRECISION_SUCCESS: (test-\d+) verified \(total duration: ([^)]+)\)`)
-	timestampPattern := regexp.MustCompile(`\[([^\]]+)\]`)
+	var immediateOrder []string
+	immediateMap := make(map[string]*ImmediateResult)
 
+	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		// Extract timestamp
-		timestampMatch := timestampPattern.FindStringSubmatch(line)
-		var timestamp time.Time
-		if len(timestampMatch) > 1 {
-			timestamp, _ = time.Parse("2006-01-02 15:04:05.000", timestampMatch[1])
+		var event *resultEvent
+		if strings.HasPrefix(line, "{") {
+			event = parseJSONEvent(line)
+		} else {
+			event = parseTextEvent(line)
 		}
-
-		// Parse test start
-		if match := testStartPattern.FindStringSubmatch(line); len(match) > 2 {
-			testID := match[1]
-			scenario := match[2]
-			testMap[testID] = &TestResult{
-				TestID:   testID,
-				Scenario: scenario,
-			}
+		if event == nil {
+			continue
 		}
 
-		// Parse write complete
-		if match := writeCompletePattern.FindStringSubmatch(line); len(match) > 2 {
-			testID := match[1]
-			if result, exists := testMap[testID]; exists {
-				result.WriteTime = timestamp
+		switch event.Type {
+		case "PRECISION_TEST_START":
+			testMap[event.TestID] = &TestResult{
+				TestID:   event.TestID,
+				Scenario: event.Scenario,
 			}
-		}
 
-		// Parse recovery time
-		if match := recoveryPattern.FindStringSubmatch(line); len(match) > 2 {
-			testID := match[1]
-			recoveryStr := match[2]
-			if result, exists := testMap[testID]; exists {
-				result.FailureTime = timestamp.Add(-parseDuration(recoveryStr))
-				result.RecoveryTime = parseDuration(recoveryStr)
+		// Immediate-read mode (comprehensive TEST_MODE)
+		case "IMMEDIATE_READ_TEST_START":
+			immediateMap[event.TestID] = &ImmediateResult{
+				TestID:   event.TestID,
+				Scenario: event.Scenario,
+			}
+			immediateOrder = append(immediateOrder, event.TestID)
+		case "IMMEDIATE_READ_SUCCESS":
+			if result, exists := immediateMap[event.TestID]; exists {
+				result.ImmediateSuccess = true
+			}
+		case "IMMEDIATE_READ_FAILED":
+			if result, exists := immediateMap[event.TestID]; exists {
+				result.ImmediateError = event.Error
+			}
+		// Only logged when post-recovery verification succeeds
+		case "IMMEDIATE_TEST_COMPLETE":
+			if result, exists := immediateMap[event.TestID]; exists {
+				result.PostRecoverySuccess = true
+				result.Duration = event.Duration
 			}
-		}
 
-		// Parse success
-		if match := successPattern.FindStringSubmatch(line); len(match) > 2 {
-			testID := match[1]
-			durationStr := match[2]
-			if result, exists := testMap[testID]; exists {
+		case "WRITE_COMPLETE":
+			if result, exists := testMap[event.TestID]; exists {
+				result.WriteTime = event.Timestamp
+			}
+		case "LEADER_RECOVERY":
+			if result, exists := testMap[event.TestID]; exists {
+				result.RecoveryTime = event.Duration
+				if event.Duration > 0 {
+					result.FailureTime = event.Timestamp.Add(-event.Duration)
+				}
+			}
+			if result, exists := immediateMap[event.TestID]; exists {
+				result.RecoveryTime = event.Duration
+			}
+		case "PRECISION_SUCCESS":
+			if result, exists := testMap[event.TestID]; exists {
 				result.Success = true
-				result.Duration = parseDuration(durationStr)
+				result.Duration = event.Duration
+				result.InvalidRecovery = result.RecoveryTime <= 0
 				results = append(results, *result)
 			}
 		}
 	}
 
-	return results, scanner.Err()
+	var immediateResults []ImmediateResult
+	for _, testID := range immediateOrder {
+		immediateResults = append(immediateResults, *immediateMap[testID])
+	}
+
+	return results, immediateResults, scanner.Err()
+}
+
+func scenarioName(s string) string {
+	if idx, err := strconv.Atoi(s); err == nil && idx >= 0 && idx < len(scenarioNames) {
+		return scenarioNames[idx]
+	}
+	return s
 }
 
 func parseDuration(s string) time.Duration {
@@ -147,7 +297,7 @@ func parseDuration(s string) time.Duration {
 	return 0
 }
 
-func analyzeResults(results []TestResult) map[string]ScenarioStats {
+func analyzeResults(results []TestResult, immediateResults []ImmediateResult) map[string]ScenarioStats {
 	scenarioMap := make(map[string][]TestResult)
 
 	// Group results by scenario
@@ -159,6 +309,7 @@ func analyzeResults(results []TestResult) map[string]ScenarioStats {
 
 	for scenario, testResults := range scenarioMap {
 		successful := 0
+		invalidRecoveries := 0
 		var totalDuration, totalRecovery time.Duration
 		var recoveryTimes []time.Duration
 
@@ -166,6 +317,10 @@ func analyzeResults(results []TestResult) map[string]ScenarioStats {
 			if result.Success {
 				successful++
 				totalDuration += result.Duration
+				if result.InvalidRecovery {
+					invalidRecoveries++
+					continue
+				}
 				totalRecovery += result.RecoveryTime
 				recoveryTimes = append(recoveryTimes, result.RecoveryTime)
 			}
@@ -180,11 +335,15 @@ func analyzeResults(results []TestResult) map[string]ScenarioStats {
 			TotalTests:  len(testResults),
 			Successful:  successful,
 			SuccessRate: float64(successful) / float64(len(testResults)) * 100,
+
+			InvalidRecoveries: invalidRecoveries,
 		}
 
 		if successful > 0 {
 			stat.AvgDuration = totalDuration / time.Duration(successful)
-			stat.AvgRecovery = totalRecovery / time.Duration(successful)
+		}
+		if len(recoveryTimes) > 0 {
+			stat.AvgRecovery = totalRecovery / time.Duration(len(recoveryTimes))
 		}
 
 		if len(recoveryTimes) > 0 {
@@ -195,40 +354,160 @@ func analyzeResults(results []TestResult) map[string]ScenarioStats {
 		stats[scenario] = stat
 	}
 
+	for _, result := range immediateResults {
+		stat := stats[result.Scenario]
+		stat.Name = result.Scenario
+		stat.ImmediateTests++
+		if result.ImmediateSuccess {
+			stat.ImmediateReadSuccess++
+		}
+		if result.PostRecoverySuccess {
+			stat.ImmediatePostRecoverySuccess++
+		}
+		stat.ImmediateReadRate = float64(stat.ImmediateReadSuccess) / float64(stat.ImmediateTests) * 100
+		stat.ImmediatePostRecoveryRate = float64(stat.ImmediatePostRecoverySuccess) / float64(stat.ImmediateTests) * 100
+		stats[result.Scenario] = stat
+	}
+
 	return stats
 }
 
-func generateReport(stats map[string]ScenarioStats, results []TestResult) {
+func recoveryDistribution(results []TestResult, immediateResults []ImmediateResult) RecoveryDistribution {
+	var recoveries []time.Duration
+	for _, result := range results {
+		if result.Success && !result.InvalidRecovery {
+			recoveries = append(recoveries, result.RecoveryTime)
+		}
+	}
+	for _, result := range immediateResults {
+		if result.PostRecoverySuccess && result.RecoveryTime > 0 {
+			recoveries = append(recoveries, result.RecoveryTime)
+		}
+	}
+	sort.Slice(recoveries, func(i, j int) bool {
+		return recoveries[i] < recoveries[j]
+	})
+
+	dist := RecoveryDistribution{Count: len(recoveries)}
+	for _, upper := range recoveryBucketBounds {
+		dist.Buckets = append(dist.Buckets, RecoveryBucket{Upper: upper})
+	}
+	dist.Buckets = append(dist.Buckets, RecoveryBucket{})
+	if len(recoveries) == 0 {
+		return dist
+	}
+
+	dist.P50 = percentile(recoveries, 0.50)
+	dist.P90 = percentile(recoveries, 0.90)
+	dist.P99 = percentile(recoveries, 0.99)
+	for _, recovery := range recoveries {
+		dist.Buckets[recoveryBucket(recovery)].Count++
+	}
+	return dist
+}
+
+// recoveryBucket returns the index of the histogram bucket a recovery time falls in
+func recoveryBucket(recovery time.Duration) int {
+	for i, upper := range recoveryBucketBounds {
+		if recovery <= upper {
+			return i
+		}
+	}
+	return len(recoveryBucketBounds)
+}
+
+func (b RecoveryBucket) Label() string {
+	if b.Upper == 0 {
+		return fmt.Sprintf(">%v", recoveryBucketBounds[len(recoveryBucketBounds)-1])
+	}
+	return fmt.Sprintf("<=%v", b.Upper)
+}
+
+// percentile linearly interpolates between the two nearest ranks of a sorted slice
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	weight := rank - float64(lower)
+	return sorted[lower] + time.Duration(weight*float64(sorted[upper]-sorted[lower]))
+}
+
+func generateReport(stats map[string]ScenarioStats, distribution RecoveryDistribution, results []TestResult, immediateResults []ImmediateResult) {
 	fmt.Println("=== ENHANCED FAILOVER TEST ANALYSIS REPORT ===")
 	fmt.Printf("Analysis Date: %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Printf("Total Tests Analyzed: %d\n\n", len(results))
+	fmt.Printf("Total Tests Analyzed: %d\n\n", len(results)+len(immediateResults))
 
 	// Overall Statistics
 	totalTests := 0
 	totalSuccessful := 0
 	var overallDuration, overallRecovery time.Duration
 	successfulCount := 0
+	recoveryCount := 0
+	invalidRecoveries := 0
 
 	for _, stat := range stats {
 		totalTests += stat.TotalTests
 		totalSuccessful += stat.Successful
+		invalidRecoveries += stat.InvalidRecoveries
 		if stat.Successful > 0 {
 			overallDuration += stat.AvgDuration * time.Duration(stat.Successful)
-			overallRecovery += stat.AvgRecovery * time.Duration(stat.Successful)
 			successfulCount += stat.Successful
 		}
+		if validRecoveries := stat.Successful - stat.InvalidRecoveries; validRecoveries > 0 {
+			overallRecovery += stat.AvgRecovery * time.Duration(validRecoveries)
+			recoveryCount += validRecoveries
+		}
 	}
 
 	overallSuccessRate := float64(totalSuccessful) / float64(totalTests) * 100
 	if successfulCount > 0 {
 		overallDuration /= time.Duration(successfulCount)
-		overallRecovery /= time.Duration(successfulCount)
+	}
+	if recoveryCount > 0 {
+		overallRecovery /= time.Duration(recoveryCount)
 	}
 
 	fmt.Printf("OVERALL RESULTS:\n")
 	fmt.Printf("  Success Rate: %.1f%% (%d/%d tests)\n", overallSuccessRate, totalSuccessful, totalTests)
 	fmt.Printf("  Average Test Duration: %v\n", overallDuration.Round(time.Millisecond))
-	fmt.Printf("  Average Recovery Time: %v\n\n", overallRecovery.Round(time.Millisecond))
+	fmt.Printf("  Average Recovery Time: %v\n", overallRecovery.Round(time.Millisecond))
+	if invalidRecoveries > 0 {
+		fmt.Printf("  WARNING: %d successful tests had a missing, zero or negative recovery time and were excluded from recovery statistics\n", invalidRecoveries)
+	}
+	fmt.Println()
+
+	if distribution.Count > 0 {
+		fmt.Printf("RECOVERY TIME DISTRIBUTION (%d recoveries):\n", distribution.Count)
+		fmt.Printf("  P50: %v, P90: %v, P99: %v\n",
+			distribution.P50.Round(time.Millisecond), distribution.P90.Round(time.Millisecond), distribution.P99.Round(time.Millisecond))
+		for _, bucket := range distribution.Buckets {
+			fmt.Printf("  %8s: %4d %s\n", bucket.Label(), bucket.Count, strings.Repeat("#", bucket.Count*40/distribution.Count))
+		}
+		fmt.Println()
+	}
+
+	if len(immediateResults) > 0 {
+		immediateReadSuccess := 0
+		immediatePostRecoverySuccess := 0
+		for _, result := range immediateResults {
+			if result.ImmediateSuccess {
+				immediateReadSuccess++
+			}
+			if result.PostRecoverySuccess {
+				immediatePostRecoverySuccess++
+			}
+		}
+
+		fmt.Printf("IMMEDIATE READ RESULTS:\n")
+		fmt.Printf("  Immediate Read Success Rate: %.1f%% (%d/%d tests)\n",
+			float64(immediateReadSuccess)/float64(len(immediateResults))*100, immediateReadSuccess, len(immediateResults))
+		fmt.Printf("  Post-Recovery Success Rate: %.1f%% (%d/%d tests)\n\n",
+			float64(immediatePostRecoverySuccess)/float64(len(immediateResults))*100, immediatePostRecoverySuccess, len(immediateResults))
+	}
 
 	// Scenario-specific results
 	fmt.Println("SCENARIO BREAKDOWN:")
@@ -241,6 +520,12 @@ func generateReport(stats map[string]ScenarioStats, results []TestResult) {
 			fmt.Printf("  Tests: %d\n", stat.TotalTests)
 			fmt.Printf("  Success Rate: %.1f%% (%d/%d)\n", stat.SuccessRate, stat.Successful, stat.TotalTests)
 
+			if stat.ImmediateTests > 0 {
+				fmt.Printf("  Immediate Read Tests: %d\n", stat.ImmediateTests)
+				fmt.Printf("  Immediate Read Success: %.1f%% (%d/%d)\n", stat.ImmediateReadRate, stat.ImmediateReadSuccess, stat.ImmediateTests)
+				fmt.Printf("  Immediate Post-Recovery Success: %.1f%% (%d/%d)\n", stat.ImmediatePostRecoveryRate, stat.ImmediatePostRecoverySuccess, stat.ImmediateTests)
+			}
+
 			if stat.Successful > 0 {
 				fmt.Printf("  Average Duration: %v\n", stat.AvgDuration.Round(time.Millisecond))
 				fmt.Printf("  Average Recovery: %v\n", stat.AvgRecovery.Round(time.Millisecond))
@@ -248,6 +533,9 @@ func generateReport(stats map[string]ScenarioStats, results []TestResult) {
 					stat.MinRecovery.Round(time.Millisecond),
 					stat.MaxRecovery.Round(time.Millisecond))
 			}
+			if stat.InvalidRecoveries > 0 {
+				fmt.Printf("  WARNING: %d tests with invalid recovery time excluded\n", stat.InvalidRecoveries)
+			}
 		}
 	}
 
@@ -262,6 +550,26 @@ func generateReport(stats map[string]ScenarioStats, results []TestResult) {
 			result.TestID, result.Scenario, status,
 			result.Duration.Round(time.Millisecond),
 			result.RecoveryTime.Round(time.Millisecond))
+		if result.InvalidRecovery {
+			fmt.Printf("    WARNING: invalid recovery time, excluded from recovery statistics\n")
+		}
+	}
+	for _, result := range immediateResults {
+		immediateStatus := "FAILED"
+		if result.ImmediateSuccess {
+			immediateStatus = "SUCCESS"
+		}
+		postRecoveryStatus := "FAILED"
+		if result.PostRecoverySuccess {
+			postRecoveryStatus = "SUCCESS"
+		}
+		fmt.Printf("  %s (%s): Immediate %s, Post-Recovery %s - Duration: %v, Recovery: %v\n",
+			result.TestID, result.Scenario, immediateStatus, postRecoveryStatus,
+			result.Duration.Round(time.Millisecond),
+			result.RecoveryTime.Round(time.Millisecond))
+		if result.ImmediateError != "" {
+			fmt.Printf("    Immediate read error: %s\n", result.ImmediateError)
+		}
 	}
 
 	// Academic summary
@@ -278,11 +586,12 @@ func generateReport(stats map[string]ScenarioStats, results []TestResult) {
 	}
 
 	// Generate CSV for further analysis
-	generateCSV(results)
+	generateCSV(results, distribution)
 }
 
-func generateCSV(results []TestResult) {
-	filename := fmt.Sprintf("test-results-%s.csv", time.Now().Format("20060102-150405"))
+func generateCSV(results []TestResult, distribution RecoveryDistribution) {
+	timestamp := time.Now().Format("20060102-150405")
+	filename := fmt.Sprintf("test-results-%s.csv", timestamp)
 	file, err := os.Create(filename)
 	if err != nil {
 		fmt.Printf("Warning: Could not create CSV file: %v\n", err)
@@ -291,17 +600,48 @@ func generateCSV(results []TestResult) {
 	defer file.Close()
 
 	// CSV Header
-	file.WriteString("TestID,Scenario,Success,Duration(ms),RecoveryTime(ms),WriteTime\n")
+	file.WriteString("TestID,Scenario,Success,Duration(ms),RecoveryTime(ms),RecoveryBucket,WriteTime\n")
 
 	for _, result := range results {
-		file.WriteString(fmt.Sprintf("%s,%s,%t,%.1f,%.1f,%s\n",
+		bucket := ""
+		if result.Success && !result.InvalidRecovery {
+			bucket = distribution.Buckets[recoveryBucket(result.RecoveryTime)].Label()
+		}
+		file.WriteString(fmt.Sprintf("%s,%s,%t,%.1f,%.1f,%s,%s\n",
 			result.TestID,
 			result.Scenario,
 			result.Success,
 			float64(result.Duration.Nanoseconds())/1e6,
 			float64(result.RecoveryTime.Nanoseconds())/1e6,
+			bucket,
 			result.WriteTime.Format("2006-01-02 15:04:05.000")))
 	}
 
 	fmt.Printf("\nCSV file generated: %s\n", filename)
+
+	distributionFile := fmt.Sprintf("recovery-distribution-%s.csv", timestamp)
+	if err := writeDistributionCSV(distribution, distributionFile); err != nil {
+		fmt.Printf("Warning: Could not create recovery distribution CSV: %v\n", err)
+		return
+	}
+	fmt.Printf("Recovery distribution CSV generated: %s\n", distributionFile)
+}
+
+// writeDistributionCSV writes the recovery percentiles followed by the histogram buckets as metric,value rows
+func writeDistributionCSV(distribution RecoveryDistribution, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	file.WriteString("Metric,Value\n")
+	file.WriteString(fmt.Sprintf("Count,%d\n", distribution.Count))
+	file.WriteString(fmt.Sprintf("P50(ms),%.1f\n", float64(distribution.P50.Nanoseconds())/1e6))
+	file.WriteString(fmt.Sprintf("P90(ms),%.1f\n", float64(distribution.P90.Nanoseconds())/1e6))
+	file.WriteString(fmt.Sprintf("P99(ms),%.1f\n", float64(distribution.P99.Nanoseconds())/1e6))
+	for _, bucket := range distribution.Buckets {
+		file.WriteString(fmt.Sprintf("Bucket %s,%d\n", bucket.Label(), bucket.Count))
+	}
+	return nil
 }
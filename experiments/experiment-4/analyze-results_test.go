@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// main.go is a separate program in the same directory, so run these with
+// go test analyze-results.go analyze-results_test.go
+
+// TestInvalidRecoveryExcluded checks that a recovery duration that doesn't parse, or isn't positive,
+// is flagged rather than counted as an instantaneous recovery
+func TestInvalidRecoveryExcluded(t *testing.T) {
+	lines := []string{
+		"[2025-08-24 17:17:07.000] PRECISION_TEST_START: test-1, Scenario: 3",
+		"[2025-08-24 17:17:07.100] WRITE_COMPLETE: test-1 (duration: 12ms)",
+		"[2025-08-24 17:17:09.600] LEADER_RECOVERY: test-1 (duration: 2.5s)",
+		"[2025-08-24 17:17:10.000] PRECISION_SUCCESS: test-1 verified (total duration: 3s)",
+		"[2025-08-24 17:18:07.000] PRECISION_TEST_START: test-2, Scenario: 3",
+		"[2025-08-24 17:18:07.100] WRITE_COMPLETE: test-2 (duration: 12ms)",
+		"[2025-08-24 17:18:09.600] LEADER_RECOVERY: test-2 (duration: about 2 seconds)",
+		"[2025-08-24 17:18:10.000] PRECISION_SUCCESS: test-2 verified (total duration: 3s)",
+		"[2025-08-24 17:19:07.000] PRECISION_TEST_START: test-3, Scenario: 3",
+		"[2025-08-24 17:19:07.100] WRITE_COMPLETE: test-3 (duration: 12ms)",
+		"[2025-08-24 17:19:09.600] LEADER_RECOVERY: test-3 (duration: -1.5s)",
+		"[2025-08-24 17:19:10.000] PRECISION_SUCCESS: test-3 verified (total duration: 3s)",
+	}
+	logFile := filepath.Join(t.TempDir(), "results.log")
+	if err := os.WriteFile(logFile, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	results, immediateResults, err := parseLogFile(logFile)
+	if err != nil {
+		t.Fatalf("parseLogFile: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("parsed %d results, want 3", len(results))
+	}
+	for i, want := range []bool{false, true, true} {
+		if results[i].InvalidRecovery != want {
+			t.Errorf("%s InvalidRecovery = %t, want %t (recovery %v)", results[i].TestID, results[i].InvalidRecovery, want, results[i].RecoveryTime)
+		}
+	}
+
+	stats := analyzeResults(results, immediateResults)["PrecisionTimed"]
+	if stats.Successful != 3 || stats.InvalidRecoveries != 2 {
+		t.Errorf("%d successful with %d invalid recoveries, want 3 with 2", stats.Successful, stats.InvalidRecoveries)
+	}
+	want := 2500 * time.Millisecond
+	if stats.MinRecovery != want || stats.MaxRecovery != want || stats.AvgRecovery != want {
+		t.Errorf("recovery min/avg/max = %v/%v/%v, want %v for each", stats.MinRecovery, stats.AvgRecovery, stats.MaxRecovery, want)
+	}
+
+	if distribution := recoveryDistribution(results, immediateResults); distribution.Count != 1 {
+		t.Errorf("recovery distribution counted %d recoveries, want 1", distribution.Count)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,12 +17,16 @@ import (
 
 	"github.com/atomix/go-sdk/pkg/atomix"
 	"github.com/atomix/go-sdk/pkg/generic"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
+
+	"example.com/envutil"
+	"example.com/raftwatch"
+	"example.com/resultsink"
+	"example.com/valuemap"
 )
 
 type FailoverTestScenario int
@@ -31,6 +36,7 @@ const (
 	DuringReplication
 	RapidSequential
 	PrecisionTimed
+	MultiPartition
 )
 
 type ReadTestMode int
@@ -53,11 +59,13 @@ const (
 
 type LeaderInfo struct {
 	PartitionID int
-	PodName     string
+	PodName     string // pod running the leader; empty if the member doesn't map to one
+	MemberName  string // leader RaftMember as named in the RaftGroup status
 	PodIndex    int
 	Term        int64
 	State       string
 	LastUpdate  time.Time
+	PodReady    bool // the leader pod is Running and Ready per the Pod API, and not being deleted
 }
 
 type TestResult struct {
@@ -77,139 +85,308 @@ type TestResult struct {
 	LeaderAfter       LeaderInfo
 	ImmediateReadErr  string
 	ImmediateReadTime time.Time
+
+	// Set once durability is verified: whether the new leader also accepted a fresh write to the key
+	PostRecoveryWriteOK  bool
+	PostRecoveryWriteErr string
+
+	// Post-recovery verification reads issued, how many returned the written value, and the
+	// 1-based read on which it first appeared (0 if it never did)
+	VerificationReads   int
+	VerificationMatches int
+	ReadsUntilCorrect   int
+}
+
+// PartitionRecovery is one partition's part of a multi-partition failover round
+type PartitionRecovery struct {
+	PartitionID  int
+	LeaderBefore LeaderInfo
+	LeaderAfter  LeaderInfo
+	FailureTime  time.Time
+	RecoveryTime time.Time
+	Keys         map[string]string // keys written to the partition before the failure, and their values
+	Verified     int
+	Error        string
+}
+
+func (p PartitionRecovery) Recovery() time.Duration {
+	if p.RecoveryTime.IsZero() {
+		return 0
+	}
+	return p.RecoveryTime.Sub(p.FailureTime)
+}
+
+// MultiPartitionResult is one round of the multi-partition scenario: the leaders of several
+// partitions are terminated together and every partition's keys are verified after recovery
+type MultiPartitionResult struct {
+	TestID            string
+	Partitions        []PartitionRecovery
+	TerminationSpread time.Duration // time between the first and last termination
+	WorstRecovery     time.Duration
+	WorstPartition    int
+	Success           bool
+	Error             string
 }
 
 type EnhancedFailoverTest struct {
+	raftWatcher    *raftwatch.Watcher
 	k8sClient      *kubernetes.Clientset
-	dynamicClient  dynamic.Interface
-	logFile        *os.File
+	sink           resultsink.Sink
 	namespace      string
 	testCounter    int64
 	results        []TestResult
+	multiResults   []MultiPartitionResult
 	resultsMux     sync.RWMutex
 	leaderCache    map[int]LeaderInfo
 	leaderMux      sync.RWMutex
 	partitionCount int
+	observedGroups int
+	mapName        string
+	valueSize      int
+
+	stabilizationWindow  time.Duration
+	stabilizationSamples int
+
+	multiPartitionTargets int // partitions failed together in the multi-partition scenario
+	multiPartitionRounds  int
+	multiPartitionKeys    int // keys written to each target partition per round
+
+	// unparseableGroups is how many RaftGroups were last reported as not matching GROUP_NAME_PATTERN, guarded by leaderMux
+	unparseableGroups int
+
+	verificationReads   int // post-recovery Gets of the test key
+	verificationQuorum  int // how many of them must return the written value
+	verificationReadGap time.Duration
 }
 
 func NewEnhancedFailoverTest() (*EnhancedFailoverTest, error) {
-	config, err := rest.InClusterConfig()
+	config, source, err := raftwatch.KubeConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Kubernetes config: %v", err)
+	}
+	log.Printf("Using %s", source)
+
+	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get in-cluster config: %v", err)
+		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	k8sClient, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Kubernetes client: %v", err)
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(config)
+	logFileName := envutil.Get("LOG_FILE", "enhanced-failover-test-results.log")
+	resultSink := envutil.Get("RESULT_SINK", "file")
+	namespace := envutil.Get("NAMESPACE", "default")
+	storeName := envutil.Get("STORE_NAME", raftwatch.DefaultStoreName)
+	mapName := envutil.Get("MAP_NAME", "precision-test-map")
+	logFormat := envutil.Get("LOG_FORMAT", "text")
+	if logFormat != "text" && logFormat != "jsonl" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT '%s'. Valid options: 'text', 'jsonl'", logFormat)
+	}
+
+	leaderRefresh, err := time.ParseDuration(envutil.Get("LEADER_REFRESH_INTERVAL", "1s"))
+	if err != nil || leaderRefresh < 0 {
+		return nil, fmt.Errorf("invalid LEADER_REFRESH_INTERVAL '%s'. Must be a non-negative duration", os.Getenv("LEADER_REFRESH_INTERVAL"))
+	}
+
+	stabilizationWindow, err := time.ParseDuration(envutil.Get("STABILIZATION_WINDOW", "2s"))
+	if err != nil || stabilizationWindow < 0 {
+		return nil, fmt.Errorf("invalid STABILIZATION_WINDOW '%s'. Must be a non-negative duration", os.Getenv("STABILIZATION_WINDOW"))
+	}
+
+	stabilizationSamples, err := strconv.Atoi(envutil.Get("STABILIZATION_SAMPLES", "1"))
+	if err != nil || stabilizationSamples < 1 {
+		return nil, fmt.Errorf("invalid STABILIZATION_SAMPLES '%s'. Must be a positive integer", os.Getenv("STABILIZATION_SAMPLES"))
+	}
+
+	verificationReads, err := strconv.Atoi(envutil.Get("VERIFICATION_READS", "1"))
+	if err != nil || verificationReads < 1 {
+		return nil, fmt.Errorf("invalid VERIFICATION_READS '%s'. Must be a positive integer", os.Getenv("VERIFICATION_READS"))
+	}
+	verificationQuorum, err := strconv.Atoi(envutil.Get("VERIFICATION_QUORUM", strconv.Itoa(verificationReads)))
+	if err != nil || verificationQuorum < 1 || verificationQuorum > verificationReads {
+		return nil, fmt.Errorf("invalid VERIFICATION_QUORUM '%s'. Must be between 1 and VERIFICATION_READS (%d)", os.Getenv("VERIFICATION_QUORUM"), verificationReads)
+	}
+	verificationReadGap, err := time.ParseDuration(envutil.Get("VERIFICATION_READ_GAP", "200ms"))
+	if err != nil || verificationReadGap < 0 {
+		return nil, fmt.Errorf("invalid VERIFICATION_READ_GAP '%s'. Must be a non-negative duration", os.Getenv("VERIFICATION_READ_GAP"))
+	}
+
+	valueSize, err := strconv.Atoi(envutil.Get("VALUE_SIZE", "0"))
+	if err != nil || valueSize < 0 {
+		return nil, fmt.Errorf("invalid VALUE_SIZE '%s'. Must be a non-negative integer", os.Getenv("VALUE_SIZE"))
+	}
+
+	partitionCount, err := strconv.Atoi(envutil.Get("PARTITION_COUNT", "3"))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
+		return nil, fmt.Errorf("invalid PARTITION_COUNT: %v", err)
+	}
+	if partitionCount <= 0 {
+		return nil, fmt.Errorf("PARTITION_COUNT must be positive, got %d", partitionCount)
+	}
+
+	multiPartitionTargets, err := strconv.Atoi(envutil.Get("MULTI_PARTITION_TARGETS", "2"))
+	if err != nil || multiPartitionTargets < 2 || multiPartitionTargets > partitionCount {
+		return nil, fmt.Errorf("invalid MULTI_PARTITION_TARGETS '%s'. Must be between 2 and PARTITION_COUNT (%d)", os.Getenv("MULTI_PARTITION_TARGETS"), partitionCount)
+	}
+	multiPartitionRounds, err := strconv.Atoi(envutil.Get("MULTI_PARTITION_ROUNDS", "3"))
+	if err != nil || multiPartitionRounds < 1 {
+		return nil, fmt.Errorf("invalid MULTI_PARTITION_ROUNDS '%s'. Must be a positive integer", os.Getenv("MULTI_PARTITION_ROUNDS"))
+	}
+	multiPartitionKeys, err := strconv.Atoi(envutil.Get("MULTI_PARTITION_KEYS", "3"))
+	if err != nil || multiPartitionKeys < 1 {
+		return nil, fmt.Errorf("invalid MULTI_PARTITION_KEYS '%s'. Must be a positive integer", os.Getenv("MULTI_PARTITION_KEYS"))
 	}
 
-	logFileName := getEnv("LOG_FILE", "enhanced-failover-test-results.log")
-	namespace := getEnv("NAMESPACE", "default")
+	raftWatcher := raftwatch.New(dynamicClient, namespace, storeName, leaderRefresh)
+	if pattern := os.Getenv("GROUP_NAME_PATTERN"); pattern != "" {
+		if err := raftWatcher.SetGroupPattern(pattern); err != nil {
+			return nil, fmt.Errorf("invalid GROUP_NAME_PATTERN '%s': %v", pattern, err)
+		}
+	}
+	checkCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := raftWatcher.CheckStore(checkCtx); err != nil {
+		return nil, fmt.Errorf("STORE_NAME '%s': %v", storeName, err)
+	}
 
-	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	sink, err := resultsink.New(resultSink, resultsink.Options{LogFile: logFileName, LogFormat: logFormat})
 	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %v", err)
+		return nil, err
 	}
 
 	return &EnhancedFailoverTest{
-		k8sClient:      clientset,
-		dynamicClient:  dynamicClient,
-		logFile:        logFile,
+		raftWatcher:    raftWatcher,
+		k8sClient:      k8sClient,
+		sink:           sink,
 		namespace:      namespace,
 		leaderCache:    make(map[int]LeaderInfo),
-		partitionCount: 3,
+		partitionCount: partitionCount,
+		mapName:        mapName,
+		valueSize:      valueSize,
+
+		stabilizationWindow:  stabilizationWindow,
+		stabilizationSamples: stabilizationSamples,
+
+		multiPartitionTargets: multiPartitionTargets,
+		multiPartitionRounds:  multiPartitionRounds,
+		multiPartitionKeys:    multiPartitionKeys,
+
+		verificationReads:   verificationReads,
+		verificationQuorum:  verificationQuorum,
+		verificationReadGap: verificationReadGap,
 	}, nil
 }
 
+// logMessage emits "EVENT_TYPE: details" messages to the result sink
 func (eft *EnhancedFailoverTest) logMessage(message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	logEntry := fmt.Sprintf("[%s] %s\n", timestamp, message)
-	fmt.Print(logEntry)
-	eft.logFile.WriteString(logEntry)
-	eft.logFile.Sync()
+	eft.emit(resultsink.Event{Timestamp: time.Now(), Message: message})
+}
+
+// logEvent emits an event with structured fields. The text format prints only "EVENT_TYPE: message",
+// so the message must still carry everything the text analyzers parse.
+func (eft *EnhancedFailoverTest) logEvent(eventType, message string, fields map[string]any) {
+	eft.emit(resultsink.Event{Timestamp: time.Now(), Message: eventType + ": " + message, Fields: fields})
 }
 
-func (eft *EnhancedFailoverTest) updateLeaderInfo(ctx context.Context) error {
-	raftGroupGVR := schema.GroupVersionResource{
-		Group:    "consensus.atomix.io",
-		Version:  "v1beta1",
-		Resource: "raftgroups",
+func (eft *EnhancedFailoverTest) emit(event resultsink.Event) {
+	if err := eft.sink.Emit(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to emit result: %v\n", err)
 	}
+}
 
-	raftGroups, err := eft.dynamicClient.Resource(raftGroupGVR).Namespace(eft.namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: "atomix.io/store=consensus-store",
-	})
+// updateLeaderInfo refreshes leaderCache. Lookups within LEADER_REFRESH_INTERVAL of the last
+// RaftGroup list reuse its result unless force is set.
+func (eft *EnhancedFailoverTest) updateLeaderInfo(ctx context.Context, force bool) error {
+	var leaders map[int]raftwatch.LeaderInfo
+	var err error
+	if force {
+		leaders, err = eft.raftWatcher.Refresh(ctx)
+	} else {
+		leaders, err = eft.raftWatcher.Leaders(ctx)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to list RaftGroups: %v", err)
+		return err
 	}
 
-	eft.leaderMux.Lock()
-	defer eft.leaderMux.Unlock()
-
-	for _, item := range raftGroups.Items {
-		groupName := item.GetName()
-		partNum, err := strconv.Atoi(strings.Split(groupName, "-")[2])
-		if err != nil {
+	// Resolve each leader's pod and cross-check it with the Pod API before taking leaderMux
+	podNames := make(map[int]string)
+	podReady := make(map[string]bool)
+	for partNum, leader := range leaders {
+		if leader.MemberName == "" {
 			continue
 		}
-
-		status, found, err := unstructured.NestedMap(item.Object, "status")
-		if err != nil || !found {
-			continue
-		}
-
-		leader, found, err := unstructured.NestedMap(status, "leader")
-		if err != nil || !found {
-			eft.leaderCache[partNum] = LeaderInfo{
-				PartitionID: partNum,
-				State:       "no-leader",
-				LastUpdate:  time.Now(),
-			}
+		podName, err := eft.raftWatcher.LeaderPod(leader)
+		if err != nil {
+			eft.logMessage(fmt.Sprintf("POD_STATUS_ERROR: %v", err))
 			continue
 		}
-
-		leaderName, found, err := unstructured.NestedString(leader, "name")
-		if err != nil || !found {
-			continue
+		podNames[partNum] = podName
+		if _, ok := podReady[podName]; !ok {
+			podReady[podName] = eft.podReady(ctx, podName)
 		}
+	}
 
-		term, found, err := unstructured.NestedInt64(status, "term")
-		if err != nil || !found {
-			term = 0
-		}
+	eft.leaderMux.Lock()
+	defer eft.leaderMux.Unlock()
 
-		state, found, err := unstructured.NestedString(status, "state")
-		if err != nil || !found {
-			state = "unknown"
-		}
+	if len(leaders) != eft.partitionCount && len(leaders) != eft.observedGroups {
+		eft.logMessage(fmt.Sprintf("WARNING: PARTITION_COUNT is %d but found %d RaftGroups", eft.partitionCount, len(leaders)))
+	}
+	eft.observedGroups = len(leaders)
 
-		podIndex := -1
-		if strings.Contains(leaderName, "consensus-store-") {
-			if parts := strings.Split(leaderName, "-"); len(parts) >= 4 {
-				if idx, err := strconv.Atoi(parts[3]); err == nil {
-					podIndex = idx
-				}
-			}
+	if unparseable := eft.raftWatcher.UnparseableGroups(); len(unparseable) != eft.unparseableGroups {
+		eft.unparseableGroups = len(unparseable)
+		if len(unparseable) > 0 {
+			eft.logMessage(fmt.Sprintf("WARNING: %d RaftGroup(s) ignored because their names don't match GROUP_NAME_PATTERN %s: %s", len(unparseable), eft.raftWatcher.GroupPattern(), strings.Join(unparseable, ", ")))
 		}
+	}
 
-		eft.leaderCache[partNum] = LeaderInfo{
+	for partNum, leader := range leaders {
+		info := LeaderInfo{
 			PartitionID: partNum,
-			PodName:     leaderName,
-			PodIndex:    podIndex,
-			Term:        term,
-			State:       state,
+			PodName:     podNames[partNum],
+			MemberName:  leader.MemberName,
+			PodIndex:    leader.Member,
+			Term:        leader.Term,
+			State:       leader.State,
 			LastUpdate:  time.Now(),
+			PodReady:    podReady[podNames[partNum]],
 		}
+		if leader.MemberName == "" {
+			info = LeaderInfo{
+				PartitionID: partNum,
+				State:       "no-leader",
+				LastUpdate:  time.Now(),
+			}
+		}
+		eft.leaderCache[partNum] = info
 	}
 
 	return nil
 }
 
+// podReady reports whether the pod is Running and Ready and not being deleted. Raft can still report
+// a leader whose pod is being evicted.
+func (eft *EnhancedFailoverTest) podReady(ctx context.Context, podName string) bool {
+	pod, err := eft.k8sClient.CoreV1().Pods(eft.namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		eft.logMessage(fmt.Sprintf("POD_STATUS_ERROR: Failed to get leader pod %s: %v", podName, err))
+		return false
+	}
+	if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (eft *EnhancedFailoverTest) getLeaderForPartition(partitionID int) (LeaderInfo, bool) {
 	eft.leaderMux.RLock()
 	defer eft.leaderMux.RUnlock()
@@ -224,24 +401,22 @@ func (eft *EnhancedFailoverTest) getKeyPartition(key string) int {
 }
 
 func (eft *EnhancedFailoverTest) terminateLeaderPod(ctx context.Context, leader LeaderInfo) error {
-	if leader.PodName == "" {
-		return fmt.Errorf("no leader pod to terminate")
+	target := raftwatch.LeaderInfo{
+		PartitionID: leader.PartitionID,
+		MemberName:  leader.MemberName,
+		Member:      leader.PodIndex,
+		Term:        leader.Term,
+		State:       leader.State,
 	}
-
-	podNum, err := strconv.Atoi(strings.Split(leader.PodName, "-")[3])
+	podName, err := eft.raftWatcher.LeaderPod(target)
 	if err != nil {
-		return fmt.Errorf("failed to parse pod number: %v", err)
+		return err
 	}
 
-	podName := "consensus-store-" + strconv.Itoa(podNum-1)
-
-	eft.logMessage(fmt.Sprintf("FORCED_TERMINATION: Terminating leader pod %s for partition %d", podName, leader.PartitionID))
+	eft.logMessage(fmt.Sprintf("FORCED_TERMINATION: Terminating leader pod %s (member %s) for partition %d", podName, leader.MemberName, leader.PartitionID))
 
-	err = eft.k8sClient.CoreV1().Pods(eft.namespace).Delete(ctx, podName, metav1.DeleteOptions{
-		GracePeriodSeconds: new(int64),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to delete pod %s: %v", podName, err)
+	if err := eft.raftWatcher.TerminateLeader(ctx, target); err != nil {
+		return err
 	}
 
 	eft.logMessage(fmt.Sprintf("TERMINATION_SUCCESS: Pod %s terminated", podName))
@@ -263,7 +438,7 @@ func (eft *EnhancedFailoverTest) waitForLeaderElection(ctx context.Context, part
 		case <-ctx.Done():
 			return LeaderInfo{}, ctx.Err()
 		case <-ticker.C:
-			err := eft.updateLeaderInfo(ctx)
+			err := eft.updateLeaderInfo(ctx, false)
 			if err != nil {
 				continue
 			}
@@ -272,28 +447,42 @@ func (eft *EnhancedFailoverTest) waitForLeaderElection(ctx context.Context, part
 			if exists && leader.State == "Ready" && leader.Term > originalTerm {
 				eft.logMessage(fmt.Sprintf("NEW_LEADER_ELECTED: Partition %d, Pod %s, Term %d (waiting for system stabilization...)",
 					partitionID, leader.PodName, leader.Term))
-				
-				time.Sleep(2 * time.Second)
-				
-				err = eft.updateLeaderInfo(ctx)
-				if err != nil {
-					eft.logMessage(fmt.Sprintf("LEADER_STABILITY_CHECK_FAILED: Error updating leader info: %v", err))
-					continue
-				}
 
-				reconfirmedLeader, stillExists := eft.getLeaderForPartition(partitionID)
-				if stillExists && reconfirmedLeader.State == "Ready" && reconfirmedLeader.Term == leader.Term && reconfirmedLeader.PodName == leader.PodName {
-					eft.logMessage(fmt.Sprintf("LEADER_READY: Partition %d leader %s is stable and ready for reads",
-						partitionID, reconfirmedLeader.PodName))
-					return reconfirmedLeader, nil
-				} else {
-					eft.logMessage(fmt.Sprintf("LEADER_INSTABILITY: Leader state changed during stabilization check, continuing to wait..."))
+				if eft.confirmStableLeader(ctx, partitionID, leader) {
+					eft.logMessage(fmt.Sprintf("LEADER_READY: Partition %d leader %s is stable and ready for reads (window: %v, samples: %d)",
+						partitionID, leader.PodName, eft.stabilizationWindow, eft.stabilizationSamples))
+					return leader, nil
 				}
+				eft.logMessage("LEADER_INSTABILITY: Leader state changed during stabilization check, continuing to wait...")
 			}
 		}
 	}
 }
 
+// confirmStableLeader samples the partition's leader STABILIZATION_SAMPLES times, evenly spread over
+// STABILIZATION_WINDOW, and reports whether every sample saw the same Ready pod and term
+func (eft *EnhancedFailoverTest) confirmStableLeader(ctx context.Context, partitionID int, leader LeaderInfo) bool {
+	interval := eft.stabilizationWindow / time.Duration(eft.stabilizationSamples)
+	for i := 0; i < eft.stabilizationSamples; i++ {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+
+		if err := eft.updateLeaderInfo(ctx, true); err != nil {
+			eft.logMessage(fmt.Sprintf("LEADER_STABILITY_CHECK_FAILED: Error updating leader info: %v", err))
+			return false
+		}
+
+		current, exists := eft.getLeaderForPartition(partitionID)
+		if !exists || current.State != "Ready" || current.Term != leader.Term || current.PodName != leader.PodName {
+			return false
+		}
+	}
+	return true
+}
+
 func (eft *EnhancedFailoverTest) waitForReadyLeader(ctx context.Context, partitionID int) (LeaderInfo, error) {
 	timeout := time.NewTimer(45 * time.Second)
 	ticker := time.NewTicker(1 * time.Second)
@@ -301,11 +490,12 @@ func (eft *EnhancedFailoverTest) waitForReadyLeader(ctx context.Context, partiti
 	defer ticker.Stop()
 
 	eft.logMessage(fmt.Sprintf("READY_LEADER_WAIT: Waiting for ready leader on partition %d", partitionID))
-	
-	err := eft.updateLeaderInfo(ctx)
+
+	var unreadyPod string
+	err := eft.updateLeaderInfo(ctx, false)
 	if err == nil {
-		leader, exists := eft.getLeaderForPartition(partitionID)
-		if exists && leader.State == "Ready" && leader.PodName != "" {
+		leader, exists := eft.readyLeader(partitionID, &unreadyPod)
+		if exists {
 			eft.logMessage(fmt.Sprintf("READY_LEADER_IMMEDIATE: Partition %d already has ready leader %s, Term %d",
 				partitionID, leader.PodName, leader.Term))
 			return leader, nil
@@ -319,13 +509,13 @@ func (eft *EnhancedFailoverTest) waitForReadyLeader(ctx context.Context, partiti
 		case <-ctx.Done():
 			return LeaderInfo{}, ctx.Err()
 		case <-ticker.C:
-			err := eft.updateLeaderInfo(ctx)
+			err := eft.updateLeaderInfo(ctx, false)
 			if err != nil {
 				continue
 			}
 
-			leader, exists := eft.getLeaderForPartition(partitionID)
-			if exists && leader.State == "Ready" && leader.PodName != "" {
+			leader, exists := eft.readyLeader(partitionID, &unreadyPod)
+			if exists {
 				eft.logMessage(fmt.Sprintf("READY_LEADER_FOUND: Partition %d has ready leader %s, Term %d",
 					partitionID, leader.PodName, leader.Term))
 				return leader, nil
@@ -334,6 +524,24 @@ func (eft *EnhancedFailoverTest) waitForReadyLeader(ctx context.Context, partiti
 	}
 }
 
+// readyLeader returns the partition's leader if Raft reports it Ready and its pod is Ready too. A Raft
+// Ready leader whose pod isn't is logged once per pod in unreadyPod.
+func (eft *EnhancedFailoverTest) readyLeader(partitionID int, unreadyPod *string) (LeaderInfo, bool) {
+	leader, exists := eft.getLeaderForPartition(partitionID)
+	if !exists || leader.State != "Ready" || leader.PodName == "" {
+		return LeaderInfo{}, false
+	}
+	if !leader.PodReady {
+		if *unreadyPod != leader.PodName {
+			*unreadyPod = leader.PodName
+			eft.logMessage(fmt.Sprintf("READY_LEADER_POD_NOT_READY: Partition %d leader %s is Raft Ready but its pod is not Running/Ready, waiting...",
+				partitionID, leader.PodName))
+		}
+		return LeaderInfo{}, false
+	}
+	return leader, true
+}
+
 func (eft *EnhancedFailoverTest) executeImmediateReadTest(ctx context.Context, scenario FailoverTestScenario, delay time.Duration) TestResult {
 	testID := fmt.Sprintf("imm-test-%06d", atomic.AddInt64(&eft.testCounter, 1))
 
@@ -343,16 +551,17 @@ func (eft *EnhancedFailoverTest) executeImmediateReadTest(ctx context.Context, s
 		ReadMode: ImmediateRead,
 	}
 
-	eft.logMessage(fmt.Sprintf("IMMEDIATE_READ_TEST_START: %s, Scenario: %v, Delay: %v", testID, scenario, delay))
+	eft.logEvent("IMMEDIATE_READ_TEST_START", fmt.Sprintf("%s, Scenario: %v, Delay: %v", testID, scenario, delay),
+		map[string]any{"test_id": testID, "scenario": int(scenario), "delay_ns": delay.Nanoseconds()})
 
-	testMap, err := atomix.Map[string, string]("precision-test-map").Codec(generic.Scalar[string]()).Get(ctx)
+	testMap, err := atomix.Map[string, string](eft.mapName).Codec(generic.Scalar[string]()).Get(ctx)
 	if err != nil {
 		result.Error = fmt.Sprintf("Failed to get map instance: %v", err)
 		return result
 	}
 
 	result.Key = fmt.Sprintf("immediate-key-%s", testID)
-	result.Value = fmt.Sprintf("immediate-value-%s-%d", testID, time.Now().UnixNano())
+	result.Value = eft.padValue(fmt.Sprintf("immediate-value-%s-%d", testID, time.Now().UnixNano()))
 
 	partitionID := eft.getKeyPartition(result.Key)
 
@@ -363,8 +572,8 @@ func (eft *EnhancedFailoverTest) executeImmediateReadTest(ctx context.Context, s
 	}
 	result.LeaderBefore = leader
 
-	eft.logMessage(fmt.Sprintf("IMMEDIATE_WRITE: %s -> %s (partition %d, leader %s)",
-		result.Key, result.Value, partitionID, leader.PodName))
+	eft.logEvent("IMMEDIATE_WRITE", fmt.Sprintf("%s -> %s (partition %d, leader %s)", result.Key, result.Value, partitionID, leader.PodName),
+		map[string]any{"test_id": testID, "key": result.Key, "value": result.Value, "partition": partitionID, "leader": leader.PodName, "term": leader.Term})
 
 	result.WriteTime = time.Now()
 	_, err = testMap.Put(ctx, result.Key, result.Value)
@@ -374,7 +583,8 @@ func (eft *EnhancedFailoverTest) executeImmediateReadTest(ctx context.Context, s
 	}
 
 	writeDuration := time.Since(result.WriteTime)
-	eft.logMessage(fmt.Sprintf("WRITE_COMPLETE: %s (duration: %v)", testID, writeDuration))
+	eft.logEvent("WRITE_COMPLETE", fmt.Sprintf("%s (duration: %v)", testID, writeDuration),
+		map[string]any{"test_id": testID, "key": result.Key, "duration_ns": writeDuration.Nanoseconds()})
 
 	if delay > 0 {
 		eft.logMessage(fmt.Sprintf("IMMEDIATE_DELAY: Waiting %v before termination", delay))
@@ -395,17 +605,24 @@ func (eft *EnhancedFailoverTest) executeImmediateReadTest(ctx context.Context, s
 	defer cancel()
 
 	entry, err := testMap.Get(immediateCtx, result.Key)
+	readFailure := ""
 	if err != nil {
 		result.ImmediateReadErr = fmt.Sprintf("Immediate read failed: %v", err)
-		eft.logMessage(fmt.Sprintf("IMMEDIATE_READ_FAILED: %s - %s", testID, result.ImmediateReadErr))
+		readFailure = result.ImmediateReadErr
 	} else if entry == nil {
 		result.ImmediateReadErr = "Key not found during immediate read"
-		eft.logMessage(fmt.Sprintf("IMMEDIATE_READ_FAILED: %s - Key not found", testID))
+		readFailure = "Key not found"
 	} else if entry.Value != result.Value {
 		result.ImmediateReadErr = fmt.Sprintf("Immediate read value mismatch: got '%s', expected '%s'", entry.Value, result.Value)
-		eft.logMessage(fmt.Sprintf("IMMEDIATE_READ_FAILED: %s - Value mismatch", testID))
+		readFailure = "Value mismatch"
+	}
+	readDuration := time.Since(result.ImmediateReadTime)
+	if readFailure != "" {
+		eft.logEvent("IMMEDIATE_READ_FAILED", fmt.Sprintf("%s - %s", testID, readFailure),
+			map[string]any{"test_id": testID, "key": result.Key, "duration_ns": readDuration.Nanoseconds(), "error": readFailure})
 	} else {
-		eft.logMessage(fmt.Sprintf("IMMEDIATE_READ_SUCCESS: %s - Data readable immediately after failure", testID))
+		eft.logEvent("IMMEDIATE_READ_SUCCESS", fmt.Sprintf("%s - Data readable immediately after failure", testID),
+			map[string]any{"test_id": testID, "key": result.Key, "duration_ns": readDuration.Nanoseconds()})
 	}
 
 	newLeader, err := eft.waitForLeaderElection(ctx, partitionID, leader.Term)
@@ -417,45 +634,33 @@ func (eft *EnhancedFailoverTest) executeImmediateReadTest(ctx context.Context, s
 	result.RecoveryTime = time.Now()
 
 	recoveryDuration := result.RecoveryTime.Sub(result.FailureTime)
-	eft.logMessage(fmt.Sprintf("LEADER_RECOVERY: %s (duration: %v)", testID, recoveryDuration))
+	eft.logEvent("LEADER_RECOVERY", fmt.Sprintf("%s (duration: %v)", testID, recoveryDuration),
+		map[string]any{"test_id": testID, "partition": partitionID, "duration_ns": recoveryDuration.Nanoseconds(), "leader": newLeader.PodName, "term": newLeader.Term})
 
 	eft.logMessage(fmt.Sprintf("POST_RECOVERY_WAIT: %s - Waiting for system stabilization before verification read", testID))
 	time.Sleep(1 * time.Second)
 
 	result.VerificationTime = time.Now()
-	
-	verificationCtx, verificationCancel := context.WithTimeout(ctx, 10*time.Second)
-	defer verificationCancel()
 
 	eft.logMessage(fmt.Sprintf("POST_RECOVERY_READ: %s - Attempting verification read", testID))
-	entry, err = testMap.Get(verificationCtx, result.Key)
-	if err != nil {
-		result.Error = fmt.Sprintf("Post-recovery read failed: %v", err)
-		return result
-	}
-
-	if entry == nil {
-		result.Error = "Key not found during post-recovery verification"
-		return result
-	}
-
-	if entry.Value != result.Value {
-		result.Error = fmt.Sprintf("Post-recovery value mismatch: got '%s', expected '%s'", entry.Value, result.Value)
+	if err := eft.verifyValue(ctx, testMap, &result); err != nil {
+		result.Error = fmt.Sprintf("Post-recovery verification failed: %v", err)
 		return result
 	}
 
 	result.Success = true
 	result.Duration = time.Since(result.WriteTime)
-	
+
 	eft.logMessage(fmt.Sprintf("POST_RECOVERY_READ_SUCCESS: %s - Data verified successfully after recovery", testID))
+	eft.verifyPostRecoveryWrite(ctx, testMap, &result)
 
 	immediateStatus := "failed"
 	if result.ImmediateReadErr == "" {
 		immediateStatus = "success"
 	}
 
-	eft.logMessage(fmt.Sprintf("IMMEDIATE_TEST_COMPLETE: %s - Post-recovery: success, Immediate: %s (total duration: %v)",
-		testID, immediateStatus, result.Duration))
+	eft.logEvent("IMMEDIATE_TEST_COMPLETE", fmt.Sprintf("%s - Post-recovery: success, Immediate: %s (total duration: %v)", testID, immediateStatus, result.Duration),
+		map[string]any{"test_id": testID, "immediate": immediateStatus, "duration_ns": result.Duration.Nanoseconds()})
 
 	return result
 }
@@ -469,16 +674,17 @@ func (eft *EnhancedFailoverTest) executePrecisionFailoverTest(ctx context.Contex
 		ReadMode: PostRecoveryRead,
 	}
 
-	eft.logMessage(fmt.Sprintf("PRECISION_TEST_START: %s, Scenario: %v, Delay: %v", testID, scenario, delay))
+	eft.logEvent("PRECISION_TEST_START", fmt.Sprintf("%s, Scenario: %v, Delay: %v", testID, scenario, delay),
+		map[string]any{"test_id": testID, "scenario": int(scenario), "delay_ns": delay.Nanoseconds()})
 
-	testMap, err := atomix.Map[string, string]("precision-test-map").Codec(generic.Scalar[string]()).Get(ctx)
+	testMap, err := atomix.Map[string, string](eft.mapName).Codec(generic.Scalar[string]()).Get(ctx)
 	if err != nil {
 		result.Error = fmt.Sprintf("Failed to get map instance: %v", err)
 		return result
 	}
 
 	result.Key = fmt.Sprintf("precision-key-%s", testID)
-	result.Value = fmt.Sprintf("precision-value-%s-%d", testID, time.Now().UnixNano())
+	result.Value = eft.padValue(fmt.Sprintf("precision-value-%s-%d", testID, time.Now().UnixNano()))
 
 	partitionID := eft.getKeyPartition(result.Key)
 
@@ -489,8 +695,8 @@ func (eft *EnhancedFailoverTest) executePrecisionFailoverTest(ctx context.Contex
 	}
 	result.LeaderBefore = leader
 
-	eft.logMessage(fmt.Sprintf("PRECISION_WRITE: %s -> %s (partition %d, leader %s)",
-		result.Key, result.Value, partitionID, leader.PodName))
+	eft.logEvent("PRECISION_WRITE", fmt.Sprintf("%s -> %s (partition %d, leader %s)", result.Key, result.Value, partitionID, leader.PodName),
+		map[string]any{"test_id": testID, "key": result.Key, "value": result.Value, "partition": partitionID, "leader": leader.PodName, "term": leader.Term})
 
 	result.WriteTime = time.Now()
 	_, err = testMap.Put(ctx, result.Key, result.Value)
@@ -500,7 +706,8 @@ func (eft *EnhancedFailoverTest) executePrecisionFailoverTest(ctx context.Contex
 	}
 
 	writeDuration := time.Since(result.WriteTime)
-	eft.logMessage(fmt.Sprintf("WRITE_COMPLETE: %s (duration: %v)", testID, writeDuration))
+	eft.logEvent("WRITE_COMPLETE", fmt.Sprintf("%s (duration: %v)", testID, writeDuration),
+		map[string]any{"test_id": testID, "key": result.Key, "duration_ns": writeDuration.Nanoseconds()})
 
 	if delay > 0 {
 		eft.logMessage(fmt.Sprintf("PRECISION_DELAY: Waiting %v before termination", delay))
@@ -523,30 +730,17 @@ func (eft *EnhancedFailoverTest) executePrecisionFailoverTest(ctx context.Contex
 	result.RecoveryTime = time.Now()
 
 	recoveryDuration := result.RecoveryTime.Sub(result.FailureTime)
-	eft.logMessage(fmt.Sprintf("LEADER_RECOVERY: %s (duration: %v)", testID, recoveryDuration))
+	eft.logEvent("LEADER_RECOVERY", fmt.Sprintf("%s (duration: %v)", testID, recoveryDuration),
+		map[string]any{"test_id": testID, "partition": partitionID, "duration_ns": recoveryDuration.Nanoseconds(), "leader": newLeader.PodName, "term": newLeader.Term})
 
 	eft.logMessage(fmt.Sprintf("POST_RECOVERY_WAIT: %s - Waiting for system stabilization before verification read", testID))
 	time.Sleep(1 * time.Second)
 
 	result.VerificationTime = time.Now()
-	
-	verificationCtx, verificationCancel := context.WithTimeout(ctx, 10*time.Second)
-	defer verificationCancel()
 
 	eft.logMessage(fmt.Sprintf("POST_RECOVERY_READ: %s - Attempting verification read", testID))
-	entry, err := testMap.Get(verificationCtx, result.Key)
-	if err != nil {
-		result.Error = fmt.Sprintf("Verification read failed: %v", err)
-		return result
-	}
-
-	if entry == nil {
-		result.Error = "Key not found during verification"
-		return result
-	}
-
-	if entry.Value != result.Value {
-		result.Error = fmt.Sprintf("Value mismatch: got '%s', expected '%s'", entry.Value, result.Value)
+	if err := eft.verifyValue(ctx, testMap, &result); err != nil {
+		result.Error = fmt.Sprintf("Verification failed: %v", err)
 		return result
 	}
 
@@ -554,11 +748,111 @@ func (eft *EnhancedFailoverTest) executePrecisionFailoverTest(ctx context.Contex
 	result.Duration = time.Since(result.WriteTime)
 
 	eft.logMessage(fmt.Sprintf("POST_RECOVERY_READ_SUCCESS: %s - Data verified successfully after recovery", testID))
-	eft.logMessage(fmt.Sprintf("PRECISION_SUCCESS: %s verified (total duration: %v)", testID, result.Duration))
+	eft.verifyPostRecoveryWrite(ctx, testMap, &result)
+	eft.logEvent("PRECISION_SUCCESS", fmt.Sprintf("%s verified (total duration: %v)", testID, result.Duration),
+		map[string]any{"test_id": testID, "duration_ns": result.Duration.Nanoseconds()})
 
 	return result
 }
 
+// verifyValue issues VERIFICATION_READS Gets of the test key, VERIFICATION_READ_GAP apart, and
+// succeeds once VERIFICATION_QUORUM of them returned the written value. Every read is issued even
+// after an early match, so the counts show whether stale reads persist after recovery. The error
+// describes the first read that failed.
+func (eft *EnhancedFailoverTest) verifyValue(ctx context.Context, testMap _map.Map[string, string], result *TestResult) error {
+	var firstErr error
+	for i := 1; i <= eft.verificationReads; i++ {
+		if i > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(eft.verificationReadGap):
+			}
+		}
+
+		readCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		entry, err := testMap.Get(readCtx, result.Key)
+		cancel()
+		result.VerificationReads++
+		switch {
+		case err != nil:
+			err = fmt.Errorf("read failed: %v", err)
+		case entry == nil:
+			err = fmt.Errorf("key not found")
+		case entry.Value != result.Value:
+			err = fmt.Errorf("value mismatch: got '%s', expected '%s'", entry.Value, result.Value)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			if eft.verificationReads > 1 {
+				eft.logMessage(fmt.Sprintf("VERIFICATION_READ_FAILED: %s - read %d/%d: %v", result.TestID, i, eft.verificationReads, err))
+			}
+			continue
+		}
+		result.VerificationMatches++
+		if result.ReadsUntilCorrect == 0 {
+			result.ReadsUntilCorrect = i
+		}
+	}
+
+	if eft.verificationReads > 1 {
+		eft.logMessage(fmt.Sprintf("VERIFICATION_READS: %s - %d/%d matched (quorum %d), reads until correct: %d",
+			result.TestID, result.VerificationMatches, result.VerificationReads, eft.verificationQuorum, result.ReadsUntilCorrect))
+	}
+	if result.VerificationMatches >= eft.verificationQuorum {
+		return nil
+	}
+	if eft.verificationReads == 1 {
+		return firstErr
+	}
+	return fmt.Errorf("%d/%d reads matched, quorum %d; first failure: %v", result.VerificationMatches, result.VerificationReads, eft.verificationQuorum, firstErr)
+}
+
+// padValue pads value with '.' up to VALUE_SIZE bytes. The generated prefix is kept intact, and reads
+// compare the whole value, so truncated or corrupted padding is still caught.
+func (eft *EnhancedFailoverTest) padValue(value string) string {
+	if len(value) >= eft.valueSize {
+		return value
+	}
+	return value + strings.Repeat(".", eft.valueSize-len(value))
+}
+
+// verifyPostRecoveryWrite writes a new value to the test key through the new leader and reads it back.
+// Durability is already proven at this point; this checks the partition is writable again.
+func (eft *EnhancedFailoverTest) verifyPostRecoveryWrite(ctx context.Context, testMap _map.Map[string, string], result *TestResult) {
+	value := eft.padValue(fmt.Sprintf("post-recovery-value-%s-%d", result.TestID, time.Now().UnixNano()))
+
+	writeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := testMap.Put(writeCtx, result.Key, value); err != nil {
+		result.PostRecoveryWriteErr = fmt.Sprintf("Post-recovery write failed: %v", err)
+		eft.logMessage(fmt.Sprintf("POST_RECOVERY_WRITE_FAIL: %s - %s", result.TestID, result.PostRecoveryWriteErr))
+		return
+	}
+
+	entry, err := testMap.Get(writeCtx, result.Key)
+	if err != nil {
+		result.PostRecoveryWriteErr = fmt.Sprintf("Post-recovery read-back failed: %v", err)
+	} else if entry == nil || entry.Value != value {
+		got := ""
+		if entry != nil {
+			got = entry.Value
+		}
+		result.PostRecoveryWriteErr = fmt.Sprintf("Post-recovery read-back mismatch: got '%s', expected '%s'", got, value)
+	}
+	if result.PostRecoveryWriteErr != "" {
+		eft.logMessage(fmt.Sprintf("POST_RECOVERY_WRITE_FAIL: %s - %s", result.TestID, result.PostRecoveryWriteErr))
+		return
+	}
+
+	result.PostRecoveryWriteOK = true
+	eft.logMessage(fmt.Sprintf("POST_RECOVERY_WRITE_SUCCESS: %s - New value written and read back (duration: %v)", result.TestID, time.Since(start)))
+}
+
 func (eft *EnhancedFailoverTest) runComprehensiveFailoverTests(ctx context.Context) error {
 	eft.logMessage("COMPREHENSIVE_TESTS_START: Enhanced failover testing with immediate and post-recovery read modes")
 
@@ -730,6 +1024,7 @@ func (eft *EnhancedFailoverTest) generateEnhancedReport() error {
 		DuringReplication: "During Replication",
 		RapidSequential:   "Rapid Sequential",
 		PrecisionTimed:    "Precision Timed",
+		MultiPartition:    "Multi-Partition",
 	}
 
 	readModeNames := map[ReadTestMode]string{
@@ -768,6 +1063,12 @@ func (eft *EnhancedFailoverTest) generateEnhancedReport() error {
 	immediateDataSuccess := 0
 	postRecoveryTests := 0
 	postRecoverySuccess := 0
+	writableTests := 0
+	writableSuccess := 0
+	staleTests := 0 // tests whose first verification read didn't return the written value
+	readsUntilCorrect := 0
+	maxReadsUntilCorrect := 0
+	correctTests := 0
 
 	for _, result := range eft.results {
 		if result.ReadMode == ImmediateRead {
@@ -784,6 +1085,20 @@ func (eft *EnhancedFailoverTest) generateEnhancedReport() error {
 				postRecoverySuccess++
 			}
 		}
+		if result.Success {
+			writableTests++
+			if result.PostRecoveryWriteOK {
+				writableSuccess++
+			}
+		}
+		if result.ReadsUntilCorrect > 0 {
+			correctTests++
+			readsUntilCorrect += result.ReadsUntilCorrect
+			maxReadsUntilCorrect = max(maxReadsUntilCorrect, result.ReadsUntilCorrect)
+		}
+		if result.VerificationReads > 0 && result.ReadsUntilCorrect != 1 {
+			staleTests++
+		}
 	}
 
 	immediatePostRecoveryRate := float64(immediateReadSuccess) / float64(immediateReadTests) * 100
@@ -796,6 +1111,16 @@ func (eft *EnhancedFailoverTest) generateEnhancedReport() error {
 	eft.logMessage(fmt.Sprintf("POST_RECOVERY_ANALYSIS: %d/%d tests successful (%.1f%%)",
 		postRecoverySuccess, postRecoveryTests, postRecoveryRate))
 
+	if writableTests > 0 {
+		eft.logMessage(fmt.Sprintf("POST_RECOVERY_WRITE_ANALYSIS: %d/%d durable tests accepted a new write after recovery (%.1f%%)",
+			writableSuccess, writableTests, float64(writableSuccess)/float64(writableTests)*100))
+	}
+
+	if eft.verificationReads > 1 && correctTests > 0 {
+		eft.logMessage(fmt.Sprintf("VERIFICATION_READ_ANALYSIS: %d test(s) missed the written value on their first read; reads until correct: mean %.1f, max %d (%d reads per test, quorum %d)",
+			staleTests, float64(readsUntilCorrect)/float64(correctTests), maxReadsUntilCorrect, eft.verificationReads, eft.verificationQuorum))
+	}
+
 	eft.logMessage(fmt.Sprintf("COMPARATIVE_ANALYSIS: Data durability proven in %.1f%% of immediate tests and %.1f%% of post-recovery tests",
 		immediatePostRecoveryRate, postRecoveryRate))
 
@@ -859,6 +1184,7 @@ func (eft *EnhancedFailoverTest) generateDetailedReport() error {
 		DuringReplication: "During Replication",
 		RapidSequential:   "Rapid Sequential",
 		PrecisionTimed:    "Precision Timed",
+		MultiPartition:    "Multi-Partition",
 	}
 
 	for scenario, stats := range scenarioStats {
@@ -893,17 +1219,244 @@ func (eft *EnhancedFailoverTest) generateDetailedReport() error {
 	return nil
 }
 
-func (eft *EnhancedFailoverTest) Close() {
-	if eft.logFile != nil {
-		eft.logFile.Close()
+// keyForGroup returns the first "<prefix>-<n>" key that getKeyPartition maps to the given RaftGroup.
+// getKeyPartition is 0-based while RaftGroups are numbered from 1, as in experiment-3's analyzer.
+func (eft *EnhancedFailoverTest) keyForGroup(prefix string, groupID int) string {
+	for n := 0; ; n++ {
+		key := fmt.Sprintf("%s-%d", prefix, n)
+		if eft.getKeyPartition(key)+1 == groupID {
+			return key
+		}
 	}
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// executeMultiPartitionTest writes MULTI_PARTITION_KEYS keys to each of MULTI_PARTITION_TARGETS
+// partitions, terminates all of their leaders at once, waits for every partition to elect a new
+// leader, then verifies all of the keys. Concurrent elections that interfere with each other show
+// up as longer or failed recoveries than the single-partition scenarios see.
+func (eft *EnhancedFailoverTest) executeMultiPartitionTest(ctx context.Context, round int) MultiPartitionResult {
+	testID := fmt.Sprintf("multi-test-%06d", atomic.AddInt64(&eft.testCounter, 1))
+	result := MultiPartitionResult{TestID: testID}
+
+	testMap, err := atomix.Map[string, string](eft.mapName).Codec(generic.Scalar[string]()).Get(ctx)
+	if err != nil {
+		result.Error = fmt.Sprintf("Failed to get map instance: %v", err)
+		return result
+	}
+
+	if err := eft.updateLeaderInfo(ctx, true); err != nil {
+		result.Error = fmt.Sprintf("Failed to list leaders: %v", err)
+		return result
+	}
+	var ready []int
+	eft.leaderMux.RLock()
+	for partitionID, leader := range eft.leaderCache {
+		if leader.State == "Ready" && leader.PodName != "" {
+			ready = append(ready, partitionID)
+		}
+	}
+	eft.leaderMux.RUnlock()
+	sort.Ints(ready)
+	if len(ready) < eft.multiPartitionTargets {
+		result.Error = fmt.Sprintf("Only %d partitions have a ready leader, need %d", len(ready), eft.multiPartitionTargets)
+		return result
+	}
+
+	// Rotate through the partitions so every one is failed over the rounds
+	for i := 0; i < eft.multiPartitionTargets; i++ {
+		partitionID := ready[(round+i)%len(ready)]
+		leader, _ := eft.getLeaderForPartition(partitionID)
+		result.Partitions = append(result.Partitions, PartitionRecovery{
+			PartitionID:  partitionID,
+			LeaderBefore: leader,
+			Keys:         make(map[string]string),
+		})
+	}
+	targets := make([]int, len(result.Partitions))
+	for i, p := range result.Partitions {
+		targets[i] = p.PartitionID
+	}
+	eft.logMessage(fmt.Sprintf("MULTI_PARTITION_TEST_START: %s, Partitions: %v, Keys per partition: %d", testID, targets, eft.multiPartitionKeys))
+
+	for i := range result.Partitions {
+		p := &result.Partitions[i]
+		for k := 0; k < eft.multiPartitionKeys; k++ {
+			key := eft.keyForGroup(fmt.Sprintf("multi-key-%s-%d", testID, k), p.PartitionID)
+			value := eft.padValue(fmt.Sprintf("multi-value-%s-%d-%d", testID, k, time.Now().UnixNano()))
+			if _, err := testMap.Put(ctx, key, value); err != nil {
+				result.Error = fmt.Sprintf("Write to partition %d failed: %v", p.PartitionID, err)
+				return result
+			}
+			p.Keys[key] = value
+		}
+		eft.logMessage(fmt.Sprintf("MULTI_PARTITION_WRITE: %s - Partition %d: %d keys written (leader %s)",
+			testID, p.PartitionID, len(p.Keys), p.LeaderBefore.PodName))
+	}
+
+	// Terminate every leader at once; the spread between the first and last termination is logged
+	// so rounds where the failures weren't actually simultaneous can be spotted
+	var wg sync.WaitGroup
+	terminateErrs := make([]error, len(result.Partitions))
+	for i := range result.Partitions {
+		wg.Add(1)
+		go func(p *PartitionRecovery, errp *error) {
+			defer wg.Done()
+			p.FailureTime = time.Now()
+			*errp = eft.terminateLeaderPod(ctx, p.LeaderBefore)
+		}(&result.Partitions[i], &terminateErrs[i])
+	}
+	wg.Wait()
+	first, last := result.Partitions[0].FailureTime, result.Partitions[0].FailureTime
+	for i, p := range result.Partitions {
+		if terminateErrs[i] != nil {
+			result.Error = fmt.Sprintf("Failed to terminate leader of partition %d: %v", p.PartitionID, terminateErrs[i])
+			return result
+		}
+		if p.FailureTime.Before(first) {
+			first = p.FailureTime
+		}
+		if p.FailureTime.After(last) {
+			last = p.FailureTime
+		}
+	}
+	result.TerminationSpread = last.Sub(first)
+	eft.logMessage(fmt.Sprintf("MULTI_PARTITION_TERMINATED: %s - %d leaders terminated within %v", testID, len(result.Partitions), result.TerminationSpread))
+
+	for i := range result.Partitions {
+		wg.Add(1)
+		go func(p *PartitionRecovery) {
+			defer wg.Done()
+			newLeader, err := eft.waitForLeaderElection(ctx, p.PartitionID, p.LeaderBefore.Term)
+			if err != nil {
+				p.Error = fmt.Sprintf("Leader election failed: %v", err)
+				return
+			}
+			p.LeaderAfter = newLeader
+			p.RecoveryTime = time.Now()
+			eft.logMessage(fmt.Sprintf("MULTI_PARTITION_RECOVERY: %s - Partition %d recovered (duration: %v)", testID, p.PartitionID, p.Recovery()))
+		}(&result.Partitions[i])
+	}
+	wg.Wait()
+
+	result.Success = true
+	for i := range result.Partitions {
+		p := &result.Partitions[i]
+		if p.Error == "" {
+			eft.verifyPartitionKeys(ctx, testMap, p)
+		}
+		if p.Error != "" {
+			result.Success = false
+			eft.logMessage(fmt.Sprintf("MULTI_PARTITION_PARTITION_FAILED: %s - Partition %d: %s", testID, p.PartitionID, p.Error))
+		}
+		if p.Recovery() > result.WorstRecovery {
+			result.WorstRecovery = p.Recovery()
+			result.WorstPartition = p.PartitionID
+		}
+	}
+	if !result.Success {
+		result.Error = "One or more partitions failed to recover or lost data"
+	}
+
+	eft.logMessage(fmt.Sprintf("MULTI_PARTITION_TEST_RESULT: %s - Success: %v, Worst recovery: %v (partition %d), Termination spread: %v",
+		testID, result.Success, result.WorstRecovery, result.WorstPartition, result.TerminationSpread))
+	return result
+}
+
+// verifyPartitionKeys reads back every key written to the partition, recording the first failure in p.Error
+func (eft *EnhancedFailoverTest) verifyPartitionKeys(ctx context.Context, testMap _map.Map[string, string], p *PartitionRecovery) {
+	verifyCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	keys := make([]string, 0, len(p.Keys))
+	for key := range p.Keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry, err := testMap.Get(verifyCtx, key)
+		switch {
+		case err != nil:
+			p.Error = fmt.Sprintf("Verification read of %s failed: %v", key, err)
+		case entry == nil:
+			p.Error = fmt.Sprintf("Key %s not found during verification", key)
+		case entry.Value != p.Keys[key]:
+			p.Error = fmt.Sprintf("Value mismatch for %s: got '%s', expected '%s'", key, entry.Value, p.Keys[key])
+		default:
+			p.Verified++
+			continue
+		}
+		return
+	}
+}
+
+func (eft *EnhancedFailoverTest) runMultiPartitionFailoverTests(ctx context.Context) error {
+	eft.logMessage(fmt.Sprintf("MULTI_PARTITION_TESTS_START: %d rounds, %d partitions failed together per round",
+		eft.multiPartitionRounds, eft.multiPartitionTargets))
+
+	for round := 0; round < eft.multiPartitionRounds; round++ {
+		result := eft.executeMultiPartitionTest(ctx, round)
+
+		eft.resultsMux.Lock()
+		eft.multiResults = append(eft.multiResults, result)
+		eft.resultsMux.Unlock()
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		time.Sleep(5 * time.Second)
+	}
+
+	return eft.generateMultiPartitionReport()
+}
+
+func (eft *EnhancedFailoverTest) generateMultiPartitionReport() error {
+	eft.resultsMux.RLock()
+	defer eft.resultsMux.RUnlock()
+
+	eft.logMessage("=== MULTI-PARTITION FAILOVER REPORT ===")
+
+	successful := 0
+	var worst time.Duration
+	worstTest, worstPartition := "", 0
+	var totalRecovery time.Duration
+	recovered := 0
+	for _, result := range eft.multiResults {
+		if result.Success {
+			successful++
+		}
+		if result.WorstRecovery > worst {
+			worst = result.WorstRecovery
+			worstTest, worstPartition = result.TestID, result.WorstPartition
+		}
+
+		eft.logMessage(fmt.Sprintf("TEST_DETAIL: %s (Multi-Partition) - Success: %v, Termination spread: %v, Worst recovery: %v",
+			result.TestID, result.Success, result.TerminationSpread, result.WorstRecovery))
+		if result.Error != "" {
+			eft.logMessage(fmt.Sprintf("  ERROR: %s", result.Error))
+		}
+		for _, p := range result.Partitions {
+			if !p.RecoveryTime.IsZero() {
+				totalRecovery += p.Recovery()
+				recovered++
+			}
+			eft.logMessage(fmt.Sprintf("  PARTITION: %d - Leaders: %s -> %s, Recovery: %v, Verified: %d/%d keys",
+				p.PartitionID, p.LeaderBefore.PodName, p.LeaderAfter.PodName, p.Recovery(), p.Verified, len(p.Keys)))
+		}
+	}
+
+	var meanRecovery time.Duration
+	if recovered > 0 {
+		meanRecovery = totalRecovery / time.Duration(recovered)
+	}
+	eft.logMessage(fmt.Sprintf("MULTI_PARTITION_SUMMARY: %d/%d rounds successful, Mean partition recovery: %v, Worst-case recovery: %v (%s, partition %d)",
+		successful, len(eft.multiResults), meanRecovery, worst, worstTest, worstPartition))
+	return nil
+}
+
+func (eft *EnhancedFailoverTest) Close() {
+	if eft.sink != nil {
+		eft.sink.Close()
 	}
-	return defaultValue
 }
 
 func main() {
@@ -925,7 +1478,9 @@ func main() {
 		cancel()
 	}()
 
-	testMap, err := atomix.Map[string, string]("precision-test-map").Codec(generic.Scalar[string]()).Get(ctx)
+	enhancedTest.logMessage(fmt.Sprintf("CONFIG: Store: %s, Map: %s, Value size: %s, Partition count: %d, Namespace: %s, Stabilization window: %v, Stabilization samples: %d, Verification reads: %d (quorum %d, gap %v)", enhancedTest.raftWatcher.StoreName(), enhancedTest.mapName, valuemap.SizeLabel(enhancedTest.valueSize), enhancedTest.partitionCount, enhancedTest.namespace, enhancedTest.stabilizationWindow, enhancedTest.stabilizationSamples, enhancedTest.verificationReads, enhancedTest.verificationQuorum, enhancedTest.verificationReadGap))
+
+	testMap, err := atomix.Map[string, string](enhancedTest.mapName).Codec(generic.Scalar[string]()).Get(ctx)
 	if err != nil {
 		enhancedTest.logMessage(fmt.Sprintf("FATAL: Failed to initialize test map: %v", err))
 		os.Exit(1)
@@ -947,7 +1502,7 @@ func main() {
 
 	enhancedTest.logMessage("CONNECTIVITY: Initial connectivity and consistency verified")
 
-	testMode := getEnv("TEST_MODE", "comprehensive")
+	testMode := envutil.Get("TEST_MODE", "comprehensive")
 
 	switch testMode {
 	case "precision":
@@ -964,8 +1519,15 @@ func main() {
 			os.Exit(1)
 		}
 		enhancedTest.logMessage("EXPERIMENT_COMPLETE: Comprehensive failover testing with immediate and post-recovery reads completed successfully")
+	case "multi-partition":
+		enhancedTest.logMessage("TEST_MODE: Running multi-partition failover tests (simultaneous leader failures)")
+		if err := enhancedTest.runMultiPartitionFailoverTests(ctx); err != nil {
+			enhancedTest.logMessage(fmt.Sprintf("FATAL: Multi-partition tests failed: %v", err))
+			os.Exit(1)
+		}
+		enhancedTest.logMessage("EXPERIMENT_COMPLETE: Multi-partition failover testing completed successfully")
 	default:
-		enhancedTest.logMessage(fmt.Sprintf("FATAL: Invalid TEST_MODE '%s'. Valid options: 'precision', 'comprehensive'", testMode))
+		enhancedTest.logMessage(fmt.Sprintf("FATAL: Invalid TEST_MODE '%s'. Valid options: 'precision', 'comprehensive', 'multi-partition'", testMode))
 		os.Exit(1)
 	}
 }
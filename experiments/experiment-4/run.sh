@@ -11,7 +11,7 @@ helm install -n kube-system atomix-runtime atomix/atomix-runtime --wait
 echo "Installed atomix-runtime."
 
 echo "Building docker image..."
-docker build -t enhanced-failover-client:local .
+docker build -t enhanced-failover-client:local -f Dockerfile ..
 echo "Built docker image, loading into minikube..."
 minikube image load enhanced-failover-client:local
 echo "Docker image loaded."
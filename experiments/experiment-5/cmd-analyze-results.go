@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"log"
 	"os"
@@ -138,6 +139,12 @@ func parseLogFile(filename string) (map[string]string, error) {
 
 	for scanner.Scan() {
 		line := scanner.Text()
+		if strings.HasPrefix(line, "{") {
+			if key, value, ok := jsonResult(line); ok {
+				results[key] = value
+			}
+			continue
+		}
 		for key, pattern := range patterns {
 			if match := pattern.FindStringSubmatch(line); len(match) > 0 {
 				results[key] = strings.Join(match[1:], "|")
@@ -436,4 +443,37 @@ func generateDetailedCSV(results []ConcurrencyTestResult) {
 	}
 
 	fmt.Printf("\nDetailed analysis CSV generated: %s\n", filename)
-}
\ No newline at end of file
+}
+
+// jsonEvent is one line of a LOG_FORMAT=jsonl log; result events carry their values as fields
+type jsonEvent struct {
+	EventType  string  `json:"event_type"`
+	Consistent int     `json:"consistent"`
+	Total      int     `json:"total"`
+	Successful int     `json:"successful"`
+	Attempts   int     `json:"attempts"`
+	Lost       int     `json:"lost"`
+	Rate       float64 `json:"rate"`
+	Passed     bool    `json:"passed"`
+}
+
+// jsonResult returns the result a jsonl line records, in the same form the text patterns produce
+func jsonResult(line string) (string, string, bool) {
+	var event jsonEvent
+	if err := json.Unmarshal([]byte(line), &event); err != nil {
+		return "", "", false
+	}
+	switch event.EventType {
+	case "READ_YOUR_WRITES_RESULT":
+		return "ryw_result", fmt.Sprintf("%d|%d|%.1f", event.Consistent, event.Total, event.Rate), true
+	case "NO_LOST_UPDATES_RESULT":
+		return "cas_result", fmt.Sprintf("%d|%d|%.1f", event.Successful, event.Attempts, event.Rate), true
+	case "NO_LOST_UPDATES_FAIL":
+		return "lost_updates_fail", strconv.Itoa(event.Lost), true
+	case "NO_LOST_UPDATES_PASS":
+		return "lost_updates_pass", "", true
+	case "OVERALL_RESULT":
+		return "overall_result", strconv.FormatBool(event.Passed), true
+	}
+	return "", "", false
+}
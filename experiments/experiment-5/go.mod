@@ -2,17 +2,21 @@ module example.com/fyp-atomix
 
 go 1.24.6
 
-require github.com/atomix/go-sdk v0.10.0
+require (
+	example.com/envutil v0.0.0
+	example.com/mapcas v0.0.0
+	example.com/resultsink v0.0.0
+	example.com/valuemap v0.0.0
+	github.com/atomix/runtime/sdk v0.7.2
+)
 
 require (
+	github.com/atomix/go-sdk v0.10.0 // indirect
 	github.com/atomix/runtime/api v0.7.0 // indirect
-	github.com/atomix/runtime/sdk v0.7.2 // indirect
 	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
 	github.com/gogo/protobuf v1.3.2 // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
-	github.com/kr/text v0.2.0 // indirect
 	github.com/mitchellh/go-homedir v1.1.0 // indirect
-	github.com/niemeyer/pretty v0.0.0-20200227124842-a10e7caefd8e // indirect
 	go.uber.org/atomic v1.7.0 // indirect
 	go.uber.org/multierr v1.6.0 // indirect
 	go.uber.org/zap v1.21.0 // indirect
@@ -22,7 +26,13 @@ require (
 	google.golang.org/genproto v0.0.0-20220407144326-9054f6ed7bac // indirect
 	google.golang.org/grpc v1.46.0 // indirect
 	google.golang.org/protobuf v1.28.0 // indirect
-	gopkg.in/check.v1 v1.0.0-20200227125254-8fa46927fb4f // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace example.com/envutil => ../envutil
+
+replace example.com/resultsink => ../resultsink
+
+replace example.com/mapcas => ../mapcas
+
+replace example.com/valuemap => ../valuemap
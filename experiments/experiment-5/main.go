@@ -2,18 +2,25 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
+	stderrors "errors"
 	"fmt"
+	"hash/fnv"
 	"log"
 	"os"
 	"os/signal"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
-	"github.com/atomix/go-sdk/pkg/atomix"
-	"github.com/atomix/go-sdk/pkg/generic"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+
+	"example.com/envutil"
+	"example.com/mapcas"
+	"example.com/resultsink"
+	"example.com/valuemap"
 )
 
 type TestType int
@@ -21,6 +28,8 @@ type TestType int
 const (
 	LinearizabilityTest TestType = iota
 	WriteDurabilityTest
+	ReadYourWritesTest
+	NoLostUpdatesTest
 )
 
 type ConcurrencyTest struct {
@@ -29,29 +38,68 @@ type ConcurrencyTest struct {
 	writeLog            map[string]string
 	writeLogMux         sync.RWMutex
 	consistency         *ConsistencyTracker
-	logFile             *os.File
-	csvFile             *os.File
-	csvWriter           *csv.Writer
+	sink                resultsink.Sink
 	testDuration        time.Duration
 	concurrentClients   int
 	operationsPerClient int
 	contentionKeys      int
 	statisticsFile      string
+	mapName             string
+	valueType           string
+	valueSize           int
+	writeAttempts       int // Put attempts per durability write, including the first
+
+	// Pause between a client's consecutive operations in each test; 0 runs them back-to-back
+	linearizabilityOpInterval time.Duration
+	durabilityOpInterval      time.Duration
 }
 
 type ConsistencyTracker struct {
 	linearizable    bool
 	writeDurability bool
+	readYourWrites  bool
+	noLostUpdates   bool
 	trackerMux      sync.RWMutex
 
 	// Linearizability tracking - client sequences and final value verification
 	clientSequences map[string][]string // clientID -> sequence of values written
-	finalValue      string              // final value read from shared key
+	finalValues     map[string]string   // key -> final value read
+	writeDurations  []time.Duration     // durations of successful sequence writes
 	sequenceMux     sync.RWMutex
 
+	// clientObservations is each client's timeline of values it wrote or read back, in order
+	clientObservations     map[string][]Observation
+	monotonicityViolations int
+
 	// Write durability tracking
 	acknowledgedWrites []AcknowledgedWrite
+	writeWindows       map[string]writeWindow // value -> when its write was issued and acknowledged
+	readBacks          int                    // acknowledged writes read back straight after the ack
+	staleReadBacks     int                    // of those, reads that found the key missing or at an older value
 	durabilityMux      sync.RWMutex
+
+	// Read-your-writes tracking
+	rywConsistentPairs int
+	rywTotalPairs      int
+	rywMux             sync.Mutex
+
+	// No-lost-updates tracking
+	casSuccessful int
+	casAttempts   int
+	casFinalValue int
+	casMux        sync.Mutex
+}
+
+type Observation struct {
+	Key   string
+	Value string
+	Write bool // the client's own acknowledged write rather than a read
+}
+
+// writeWindow is when a durability write was issued and, once acknowledged, when its Put returned
+type writeWindow struct {
+	Start time.Time
+	Acked time.Time // zero until acknowledged
 }
 
 type AcknowledgedWrite struct {
@@ -59,36 +107,139 @@ type AcknowledgedWrite struct {
 	Key       string
 	Value     string
 	Timestamp time.Time
+	Duration  time.Duration // across all attempts
 	Success   bool
+	Attempts  int
+	Category  string // error category of the last failed attempt; empty if the first attempt succeeded
+}
+
+// Latency histogram bucket upper bounds; the last bucket is everything above 100ms
+var latencyBuckets = []struct {
+	label string
+	upper time.Duration
+}{
+	{"<1ms", time.Millisecond},
+	{"1-5ms", 5 * time.Millisecond},
+	{"5-20ms", 20 * time.Millisecond},
+	{"20-100ms", 100 * time.Millisecond},
+	{">100ms", 0},
+}
+
+func latencyHistogram(durations []time.Duration) []int {
+	counts := make([]int, len(latencyBuckets))
+	for _, d := range durations {
+		for i, bucket := range latencyBuckets {
+			if bucket.upper == 0 || d < bucket.upper {
+				counts[i]++
+				break
+			}
+		}
+	}
+	return counts
+}
+
+// formatFinalValues prints the single final value as-is, or key=value pairs when there are several contention keys
+func formatFinalValues(values map[string]string) string {
+	if len(values) == 1 {
+		for _, value := range values {
+			return value
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + values[key]
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatCategories lists failure counts by category, most frequent first
+func formatCategories(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if counts[categories[i]] != counts[categories[j]] {
+			return counts[categories[i]] > counts[categories[j]]
+		}
+		return categories[i] < categories[j]
+	})
+	parts := make([]string, len(categories))
+	for i, category := range categories {
+		parts[i] = fmt.Sprintf("%s %d", category, counts[category])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatHistogram(counts []int) string {
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		parts[i] = fmt.Sprintf("%s: %d", latencyBuckets[i].label, count)
+	}
+	return strings.Join(parts, ", ")
 }
 
 func NewConcurrencyTest() (*ConcurrencyTest, error) {
-	concurrentClients := getEnvInt("CONCURRENT_CLIENTS", 3)
-	operationsPerClient := getEnvInt("OPERATIONS_PER_CLIENT", 5)
-	contentionKeys := getEnvInt("CONTENTION_KEYS", 1) // Single shared key for testing
-	testDuration := getEnvDuration("TEST_DURATION", 5*time.Minute)
-	statisticsFile := getEnv("STATISTICS_FILE", "linearizability-test-results.csv")
-	logFileName := getEnv("LOG_FILE", "linearizability-test-results.log")
-
-	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	concurrentClients := envutil.Int("CONCURRENT_CLIENTS", 3)
+	operationsPerClient := envutil.Int("OPERATIONS_PER_CLIENT", 5)
+	contentionKeys := envutil.Int("CONTENTION_KEYS", 1) // Number of shared keys writes are spread across
+	testDuration := envutil.Duration("TEST_DURATION", 5*time.Minute)
+	statisticsFile := envutil.Get("STATISTICS_FILE", "linearizability-test-results.csv")
+	logFileName := envutil.Get("LOG_FILE", "linearizability-test-results.log")
+	resultSink := envutil.Get("RESULT_SINK", "file")
+	mapName := envutil.Get("MAP_NAME", "concurrency-test-map")
+	valueType := envutil.Get("VALUE_TYPE", "string")
+	if valueType != "string" && valueType != "int64" && valueType != "float64" {
+		return nil, fmt.Errorf("invalid VALUE_TYPE '%s'. Valid options: 'string', 'int64', 'float64'", valueType)
+	}
+	valueSize := envutil.Int("VALUE_SIZE", 0)
+	if valueSize < 0 {
+		return nil, fmt.Errorf("invalid VALUE_SIZE %d. Must not be negative", valueSize)
+	}
+	if valueSize > 0 && valueType != "string" {
+		return nil, fmt.Errorf("VALUE_SIZE requires VALUE_TYPE 'string', got '%s'", valueType)
+	}
+	writeAttempts := envutil.Int("WRITE_ATTEMPTS", 3)
+	if writeAttempts < 1 {
+		return nil, fmt.Errorf("invalid WRITE_ATTEMPTS %d. Must be at least 1", writeAttempts)
+	}
+	// OP_INTERVAL sets the pacing of both tests; the per-test variables override it
+	linearizabilityOpInterval, err := getEnvInterval("LINEARIZABILITY_OP_INTERVAL", "OP_INTERVAL", 10*time.Millisecond)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %v", err)
+		return nil, err
 	}
-
-	csvFile, err := os.Create(statisticsFile)
+	durabilityOpInterval, err := getEnvInterval("DURABILITY_OP_INTERVAL", "OP_INTERVAL", 20*time.Millisecond)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create CSV file: %v", err)
+		return nil, err
+	}
+	logFormat := envutil.Get("LOG_FORMAT", "text")
+	if logFormat != "text" && logFormat != "jsonl" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT '%s'. Valid options: 'text', 'jsonl'", logFormat)
 	}
 
-	csvWriter := csv.NewWriter(csvFile)
-	// Write CSV header
-	csvWriter.Write([]string{"TestType", "ClientID", "Key", "Operation", "Value", "Timestamp", "Success", "Duration", "Details"})
-	csvWriter.Flush()
+	sink, err := resultsink.New(resultSink, resultsink.Options{LogFile: logFileName, LogFormat: logFormat, StatisticsFile: statisticsFile})
+	if err != nil {
+		return nil, err
+	}
 
 	consistency := &ConsistencyTracker{
 		linearizable:    true,
 		writeDurability: true,
+		readYourWrites:  true,
+		noLostUpdates:   true,
 		clientSequences: make(map[string][]string),
+		finalValues:     make(map[string]string),
+		writeWindows:    make(map[string]writeWindow),
+
+		clientObservations: make(map[string][]Observation),
 	}
 
 	return &ConcurrencyTest{
@@ -96,102 +247,145 @@ func NewConcurrencyTest() (*ConcurrencyTest, error) {
 		operationSeq:        0,
 		writeLog:            make(map[string]string),
 		consistency:         consistency,
-		logFile:             logFile,
-		csvFile:             csvFile,
-		csvWriter:           csvWriter,
+		sink:                sink,
 		testDuration:        testDuration,
 		concurrentClients:   concurrentClients,
 		operationsPerClient: operationsPerClient,
 		contentionKeys:      contentionKeys,
 		statisticsFile:      statisticsFile,
+		mapName:             mapName,
+		valueType:           valueType,
+		valueSize:           valueSize,
+		writeAttempts:       writeAttempts,
+
+		linearizabilityOpInterval: linearizabilityOpInterval,
+		durabilityOpInterval:      durabilityOpInterval,
 	}, nil
 }
 
+// logMessage emits "EVENT_TYPE: details" messages to the result sink
 func (ct *ConcurrencyTest) logMessage(message string) {
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	logEntry := fmt.Sprintf("[%s] %s\n", timestamp, message)
-	fmt.Print(logEntry)
-	ct.logFile.WriteString(logEntry)
-	ct.logFile.Sync()
+	ct.emit(resultsink.Event{Timestamp: time.Now(), Message: message})
+}
+
+// logEvent emits an event with structured fields. The text format prints only "EVENT_TYPE: message",
+// so the message must still carry everything the text analyzers parse.
+func (ct *ConcurrencyTest) logEvent(eventType, message string, fields map[string]any) {
+	ct.emit(resultsink.Event{Timestamp: time.Now(), Message: eventType + ": " + message, Fields: fields})
+}
+
+func (ct *ConcurrencyTest) emit(event resultsink.Event) {
+	if err := ct.sink.Emit(event); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to emit result: %v\n", err)
+	}
 }
 
-func (ct *ConcurrencyTest) recordCSV(testType TestType, clientID, key, operation, value string, success bool, duration time.Duration, details string) {
+// recordOperation sends one operation to the result sink
+func (ct *ConcurrencyTest) recordOperation(testType TestType, clientID, key, operation, value string, success bool, duration time.Duration, details string) {
 	testTypeStr := map[TestType]string{
 		LinearizabilityTest: "Linearizability",
 		WriteDurabilityTest: "WriteDurability",
+		ReadYourWritesTest:  "ReadYourWrites",
+		NoLostUpdatesTest:   "NoLostUpdates",
 	}[testType]
 
-	record := []string{
-		testTypeStr,
-		clientID,
-		key,
-		operation,
-		value,
-		time.Now().Format("2006-01-02 15:04:05.000"),
-		strconv.FormatBool(success),
-		duration.String(),
-		details,
+	err := ct.sink.Record(resultsink.Operation{
+		TestType:  testTypeStr,
+		ClientID:  clientID,
+		Key:       key,
+		Operation: operation,
+		Value:     value,
+		Timestamp: time.Now(),
+		Success:   success,
+		Duration:  duration,
+		Details:   details,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to record operation: %v\n", err)
 	}
-
-	ct.csvWriter.Write(record)
-	ct.csvWriter.Flush()
 }
 
 // Linearizability test: Multiple clients write sequences to same key, verify final value is from a LAST write
 func (ct *ConcurrencyTest) linearizabilityTest(ctx context.Context) error {
-	ct.logMessage("LINEARIZABILITY_TEST_START: Testing concurrent sequences with final value verification")
+	ct.logMessage(fmt.Sprintf("LINEARIZABILITY_TEST_START: Testing concurrent sequences with final value verification (%d contention keys)", ct.contentionKeys))
 
-	testMap, err := atomix.Map[string, string]("concurrency-test-map").Codec(generic.Scalar[string]()).Get(ctx)
+	testMap, err := valuemap.Open(ctx, ct.mapName, ct.valueType)
 	if err != nil {
 		return fmt.Errorf("failed to get map instance: %v", err)
 	}
 
-	sharedKey := "shared-linearizability-key"
+	keyPrefix := "shared-linearizability-key"
 	var wg sync.WaitGroup
 
-	// Track expected last values from each client
-	expectedLastValues := make([]string, ct.concurrentClients)
+	// Track expected last values per key from each client: key -> clientID -> last value written
+	expectedLastValues := make(map[string]map[string]string)
+	var expectedMux sync.Mutex
 
 	for i := 0; i < ct.concurrentClients; i++ {
 		wg.Add(1)
 		clientID := fmt.Sprintf("client-%d", i+1)
+		clientNum := i + 1
 
-		go func(clientIndex int, clientID string) {
+		go func(clientID string) {
 			defer wg.Done()
 
 			clientSequence := make([]string, 0, ct.operationsPerClient)
+			lastValues := make(map[string]string)
+			var observations []Observation
 
 			for j := 1; j <= ct.operationsPerClient; j++ {
 				start := time.Now()
 
 				// Each client writes a sequence: client-1-seq-1, client-1-seq-2, client-1-seq-3
-				sequenceValue := fmt.Sprintf("%s-seq-%d", clientID, j)
+				sequenceValue := ct.sequenceValue(clientID, clientNum, j)
 				clientSequence = append(clientSequence, sequenceValue)
+				key := ct.contentionKey(keyPrefix, clientID, j)
+				lastValues[key] = sequenceValue
 
-				_, err := testMap.Put(ctx, sharedKey, sequenceValue)
+				err := testMap.Put(ctx, key, sequenceValue)
 				duration := time.Since(start)
 
 				if err != nil {
-					ct.logMessage(fmt.Sprintf("LINEARIZABILITY_WRITE_ERROR: %s failed to write %s - %v", clientID, sequenceValue, err))
-					ct.recordCSV(LinearizabilityTest, clientID, sharedKey, "write", sequenceValue, false, duration, fmt.Sprintf("Write error: %v", err))
+					ct.logMessage(fmt.Sprintf("LINEARIZABILITY_WRITE_ERROR: %s failed to write %s to %s - %v", clientID, sequenceValue, key, err))
+					ct.recordOperation(LinearizabilityTest, clientID, key, "write", sequenceValue, false, duration, fmt.Sprintf("Write error: %v", err))
 				} else {
-					ct.logMessage(fmt.Sprintf("LINEARIZABILITY_WRITE_SUCCESS: %s wrote %s (duration: %v)", clientID, sequenceValue, duration))
-					ct.recordCSV(LinearizabilityTest, clientID, sharedKey, "write", sequenceValue, true, duration, fmt.Sprintf("Sequence write: %s", sequenceValue))
+					ct.logMessage(fmt.Sprintf("LINEARIZABILITY_WRITE_SUCCESS: %s wrote %s to %s (duration: %v)", clientID, sequenceValue, key, duration))
+					ct.consistency.sequenceMux.Lock()
+					ct.consistency.writeDurations = append(ct.consistency.writeDurations, duration)
+					ct.consistency.sequenceMux.Unlock()
+					ct.recordOperation(LinearizabilityTest, clientID, key, "write", sequenceValue, true, duration, fmt.Sprintf("Sequence write: %s", sequenceValue))
+					observations = append(observations, Observation{Key: key, Value: sequenceValue, Write: true})
+
+					// Read back so the client's timeline also holds what other writers left on the key
+					readStart := time.Now()
+					entry, err := testMap.Get(ctx, key)
+					readDuration := time.Since(readStart)
+					if err != nil {
+						ct.recordOperation(LinearizabilityTest, clientID, key, "read-back", "", false, readDuration, fmt.Sprintf("Read error: %v", err))
+					} else if entry != nil {
+						observations = append(observations, Observation{Key: key, Value: entry.Value})
+						ct.recordOperation(LinearizabilityTest, clientID, key, "read-back", entry.Value, true, readDuration, fmt.Sprintf("Observed: %s", entry.Value))
+					}
 				}
 
-				// Small delay between sequence operations
-				time.Sleep(10 * time.Millisecond)
+				time.Sleep(ct.linearizabilityOpInterval)
 			}
 
-			// Store this client's sequence and last expected value
+			// Store this client's sequence and last expected value per key
 			ct.consistency.sequenceMux.Lock()
 			ct.consistency.clientSequences[clientID] = clientSequence
-			if len(clientSequence) > 0 {
-				expectedLastValues[clientIndex] = clientSequence[len(clientSequence)-1]
-			}
+			ct.consistency.clientObservations[clientID] = observations
 			ct.consistency.sequenceMux.Unlock()
 
-		}(i, clientID)
+			expectedMux.Lock()
+			for key, value := range lastValues {
+				if expectedLastValues[key] == nil {
+					expectedLastValues[key] = make(map[string]string)
+				}
+				expectedLastValues[key][clientID] = value
+			}
+			expectedMux.Unlock()
+		}(clientID)
 	}
 
 	wg.Wait()
@@ -199,59 +393,73 @@ func (ct *ConcurrencyTest) linearizabilityTest(ctx context.Context) error {
 	// Wait a moment for all writes to settle
 	time.Sleep(100 * time.Millisecond)
 
-	// Read the final value from the shared key
-	start := time.Now()
-	entry, err := testMap.Get(ctx, sharedKey)
-	duration := time.Since(start)
-
-	if err != nil {
-		ct.logMessage(fmt.Sprintf("LINEARIZABILITY_FINAL_READ_ERROR: Failed to read final value - %v", err))
-		ct.recordCSV(LinearizabilityTest, "verification", sharedKey, "final-read", "", false, duration, fmt.Sprintf("Read error: %v", err))
-		ct.consistency.trackerMux.Lock()
-		ct.consistency.linearizable = false
-		ct.consistency.trackerMux.Unlock()
-		return nil
-	}
-
-	if entry == nil {
-		ct.logMessage("LINEARIZABILITY_FINAL_READ_NULL: Final value is null")
-		ct.recordCSV(LinearizabilityTest, "verification", sharedKey, "final-read", "", false, duration, "Final value is null")
-		ct.consistency.trackerMux.Lock()
-		ct.consistency.linearizable = false
-		ct.consistency.trackerMux.Unlock()
-		return nil
+	keys := make([]string, 0, len(expectedLastValues))
+	for key := range expectedLastValues {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	finalValue := entry.Value
-	ct.consistency.sequenceMux.Lock()
-	ct.consistency.finalValue = finalValue
-	ct.consistency.sequenceMux.Unlock()
+	// Verify each key's final value is one of the LAST writes made to that key
+	for _, key := range keys {
+		start := time.Now()
+		entry, err := testMap.Get(ctx, key)
+		duration := time.Since(start)
 
-	ct.logMessage(fmt.Sprintf("LINEARIZABILITY_FINAL_VALUE: %s (duration: %v)", finalValue, duration))
-	ct.recordCSV(LinearizabilityTest, "verification", sharedKey, "final-read", finalValue, true, duration, fmt.Sprintf("Final value: %s", finalValue))
+		if err != nil {
+			ct.logMessage(fmt.Sprintf("LINEARIZABILITY_FINAL_READ_ERROR: Failed to read final value of %s - %v", key, err))
+			ct.recordOperation(LinearizabilityTest, "verification", key, "final-read", "", false, duration, fmt.Sprintf("Read error: %v", err))
+			ct.consistency.trackerMux.Lock()
+			ct.consistency.linearizable = false
+			ct.consistency.trackerMux.Unlock()
+			continue
+		}
 
-	// Verify that final value is one of the expected LAST writes
-	isValidLastWrite := false
-	for _, lastValue := range expectedLastValues {
-		if finalValue == lastValue {
-			isValidLastWrite = true
-			break
+		if entry == nil {
+			ct.logMessage(fmt.Sprintf("LINEARIZABILITY_FINAL_READ_NULL: Final value of %s is null", key))
+			ct.recordOperation(LinearizabilityTest, "verification", key, "final-read", "", false, duration, "Final value is null")
+			ct.consistency.trackerMux.Lock()
+			ct.consistency.linearizable = false
+			ct.consistency.trackerMux.Unlock()
+			continue
 		}
-	}
 
-	if isValidLastWrite {
-		ct.logMessage(fmt.Sprintf("LINEARIZABILITY_PASS: Final value '%s' matches a client's LAST write", finalValue))
-		ct.recordCSV(LinearizabilityTest, "verification", sharedKey, "linearizability-check", finalValue, true, 0, fmt.Sprintf("Final value matches LAST write: %s", finalValue))
-	} else {
-		ct.logMessage(fmt.Sprintf("LINEARIZABILITY_FAIL: Final value '%s' does NOT match any client's LAST write", finalValue))
-		ct.logMessage(fmt.Sprintf("LINEARIZABILITY_EXPECTED_VALUES: %v", expectedLastValues))
-		ct.recordCSV(LinearizabilityTest, "verification", sharedKey, "linearizability-check", finalValue, false, 0, fmt.Sprintf("Final value '%s' not in expected last values: %v", finalValue, expectedLastValues))
+		finalValue := entry.Value
+		ct.consistency.sequenceMux.Lock()
+		ct.consistency.finalValues[key] = finalValue
+		ct.consistency.sequenceMux.Unlock()
+
+		ct.logEvent("LINEARIZABILITY_FINAL_VALUE", fmt.Sprintf("%s = %s (duration: %v)", key, finalValue, duration),
+			map[string]any{"key": key, "value": finalValue, "duration_ns": duration.Nanoseconds()})
+		ct.recordOperation(LinearizabilityTest, "verification", key, "final-read", finalValue, true, duration, fmt.Sprintf("Final value: %s", finalValue))
+
+		isValidLastWrite := false
+		lastValues := make([]string, 0, len(expectedLastValues[key]))
+		for _, lastValue := range expectedLastValues[key] {
+			lastValues = append(lastValues, lastValue)
+			if finalValue == lastValue {
+				isValidLastWrite = true
+			}
+		}
+		sort.Strings(lastValues)
 
-		ct.consistency.trackerMux.Lock()
-		ct.consistency.linearizable = false
-		ct.consistency.trackerMux.Unlock()
+		if isValidLastWrite {
+			ct.logEvent("LINEARIZABILITY_PASS", fmt.Sprintf("Final value '%s' of %s matches a client's LAST write", finalValue, key),
+				map[string]any{"key": key, "value": finalValue})
+			ct.recordOperation(LinearizabilityTest, "verification", key, "linearizability-check", finalValue, true, 0, fmt.Sprintf("Final value matches LAST write: %s", finalValue))
+		} else {
+			ct.logEvent("LINEARIZABILITY_FAIL", fmt.Sprintf("Final value '%s' of %s does NOT match any client's LAST write", finalValue, key),
+				map[string]any{"key": key, "value": finalValue, "expected": lastValues})
+			ct.logMessage(fmt.Sprintf("LINEARIZABILITY_EXPECTED_VALUES: %s: %v", key, lastValues))
+			ct.recordOperation(LinearizabilityTest, "verification", key, "linearizability-check", finalValue, false, 0, fmt.Sprintf("Final value '%s' not in expected last values: %v", finalValue, lastValues))
+
+			ct.consistency.trackerMux.Lock()
+			ct.consistency.linearizable = false
+			ct.consistency.trackerMux.Unlock()
+		}
 	}
 
+	ct.checkMonotonicity()
+
 	// Log all client sequences for analysis
 	ct.consistency.sequenceMux.RLock()
 	for clientID, sequence := range ct.consistency.clientSequences {
@@ -262,21 +470,130 @@ func (ct *ConcurrencyTest) linearizabilityTest(ctx context.Context) error {
 	return nil
 }
 
+// checkMonotonicity walks each client's observed timeline and flags any point where, on the same key,
+// the client saw a writer's value older than one it had already seen from that writer. Values from
+// different writers can't be ordered without a global clock, so only per-writer sequences are compared.
+func (ct *ConcurrencyTest) checkMonotonicity() {
+	ct.consistency.sequenceMux.Lock()
+	clientIDs := make([]string, 0, len(ct.consistency.clientObservations))
+	for clientID := range ct.consistency.clientObservations {
+		clientIDs = append(clientIDs, clientID)
+	}
+	sort.Strings(clientIDs)
+
+	violations := 0
+	totalObservations := 0
+	for _, clientID := range clientIDs {
+		// key -> writer -> newest sequence value seen so far
+		newest := make(map[string]map[string]string)
+		for _, obs := range ct.consistency.clientObservations[clientID] {
+			totalObservations++
+			writer, seq, ok := parseSequenceValue(obs.Value)
+			if !ok {
+				continue
+			}
+			if newest[obs.Key] == nil {
+				newest[obs.Key] = make(map[string]string)
+			}
+			previous, seen := newest[obs.Key][writer]
+			if !seen {
+				newest[obs.Key][writer] = obs.Value
+				continue
+			}
+			_, previousSeq, _ := parseSequenceValue(previous)
+			if seq < previousSeq {
+				violations++
+				ct.logMessage(fmt.Sprintf("LINEARIZABILITY_MONOTONICITY_FAIL: %s observed %s on %s after already observing %s", clientID, obs.Value, obs.Key, previous))
+				ct.recordOperation(LinearizabilityTest, clientID, obs.Key, "monotonicity-check", obs.Value, false, 0, fmt.Sprintf("Observed %s after %s", obs.Value, previous))
+				continue
+			}
+			newest[obs.Key][writer] = obs.Value
+		}
+	}
+	ct.consistency.monotonicityViolations = violations
+	ct.consistency.sequenceMux.Unlock()
+
+	if violations > 0 {
+		ct.consistency.trackerMux.Lock()
+		ct.consistency.linearizable = false
+		ct.consistency.trackerMux.Unlock()
+		return
+	}
+	ct.logMessage(fmt.Sprintf("LINEARIZABILITY_MONOTONICITY_PASS: No client observed a value go backwards (%d observations across %d clients)", totalObservations, len(clientIDs)))
+}
+
+// clientValueBase separates the client number from the operation in numeric test values
+const clientValueBase = 1_000_000
+
+// sequenceValue returns the value a linearizability client writes for op: "<clientID>-seq-<op>" for
+// strings, or clientNum*clientValueBase+op for the numeric VALUE_TYPEs (float64 holds it exactly)
+func (ct *ConcurrencyTest) sequenceValue(clientID string, clientNum, op int) string {
+	if ct.valueType != "string" {
+		return strconv.Itoa(clientNum*clientValueBase + op)
+	}
+	return ct.padValue(fmt.Sprintf("%s-seq-%d", clientID, op))
+}
+
+// writeValue returns a unique value for a durability or read-your-writes client's op
+func (ct *ConcurrencyTest) writeValue(clientID string, clientNum, op int) string {
+	if ct.valueType != "string" {
+		return strconv.Itoa(clientNum*clientValueBase + op)
+	}
+	return ct.padValue(fmt.Sprintf("%s-write-%d-%d", clientID, op, time.Now().UnixNano()))
+}
+
+// padValue pads a string value with '.' up to VALUE_SIZE bytes, keeping the generated prefix intact
+func (ct *ConcurrencyTest) padValue(value string) string {
+	if len(value) >= ct.valueSize {
+		return value
+	}
+	return value + strings.Repeat(".", ct.valueSize-len(value))
+}
+
+// parseSequenceValue splits a linearizability value written by sequenceValue into its writer and sequence number
+func parseSequenceValue(value string) (string, int, bool) {
+	value = strings.TrimRight(value, ".")
+	idx := strings.LastIndex(value, "-seq-")
+	if idx < 0 {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", 0, false
+		}
+		return fmt.Sprintf("client-%d", n/clientValueBase), n % clientValueBase, true
+	}
+	seq, err := strconv.Atoi(value[idx+len("-seq-"):])
+	if err != nil {
+		return "", 0, false
+	}
+	return value[:idx], seq, true
+}
+
+// contentionKey spreads a client's operations across CONTENTION_KEYS keys; with a single key it is just the prefix
+func (ct *ConcurrencyTest) contentionKey(prefix, clientID string, op int) string {
+	if ct.contentionKeys <= 1 {
+		return prefix
+	}
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%s-%d", clientID, op)))
+	return fmt.Sprintf("%s-%d", prefix, h.Sum32()%uint32(ct.contentionKeys))
+}
+
 // Write durability test: Multiple clients write to same key concurrently, verify all acknowledged writes persist
 func (ct *ConcurrencyTest) writeDurabilityTest(ctx context.Context) error {
 	ct.logMessage("WRITE_DURABILITY_TEST_START: Testing concurrent writes with durability verification")
 
-	testMap, err := atomix.Map[string, string]("concurrency-test-map").Codec(generic.Scalar[string]()).Get(ctx)
+	testMap, err := valuemap.Open(ctx, ct.mapName, ct.valueType)
 	if err != nil {
 		return fmt.Errorf("failed to get map instance: %v", err)
 	}
 
-	sharedKey := "shared-durability-key"
+	keyPrefix := "shared-durability-key"
 	var wg sync.WaitGroup
 
 	for i := 0; i < ct.concurrentClients; i++ {
 		wg.Add(1)
 		clientID := fmt.Sprintf("durability-client-%d", i+1)
+		clientNum := i + 1
 
 		go func(clientID string) {
 			defer wg.Done()
@@ -284,10 +601,15 @@ func (ct *ConcurrencyTest) writeDurabilityTest(ctx context.Context) error {
 			for j := 1; j <= ct.operationsPerClient; j++ {
 				start := time.Now()
 
-				// Each client writes unique values to the same key
-				writeValue := fmt.Sprintf("%s-write-%d-%d", clientID, j, time.Now().UnixNano())
+				// Each client writes unique values to the shared key(s)
+				writeValue := ct.writeValue(clientID, clientNum, j)
+				sharedKey := ct.contentionKey(keyPrefix, clientID, j)
 
-				_, err := testMap.Put(ctx, sharedKey, writeValue)
+				ct.consistency.durabilityMux.Lock()
+				ct.consistency.writeWindows[writeValue] = writeWindow{Start: start}
+				ct.consistency.durabilityMux.Unlock()
+
+				attempts, category, err := ct.putWithRetry(ctx, testMap, clientID, sharedKey, writeValue)
 				duration := time.Since(start)
 
 				acknowledgedWrite := AcknowledgedWrite{
@@ -295,23 +617,29 @@ func (ct *ConcurrencyTest) writeDurabilityTest(ctx context.Context) error {
 					Key:       sharedKey,
 					Value:     writeValue,
 					Timestamp: time.Now(),
+					Duration:  duration,
 					Success:   err == nil,
+					Attempts:  attempts,
+					Category:  category,
 				}
 
 				ct.consistency.durabilityMux.Lock()
 				ct.consistency.acknowledgedWrites = append(ct.consistency.acknowledgedWrites, acknowledgedWrite)
+				if err == nil {
+					ct.consistency.writeWindows[writeValue] = writeWindow{Start: start, Acked: acknowledgedWrite.Timestamp}
+				}
 				ct.consistency.durabilityMux.Unlock()
 
 				if err != nil {
-					ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_ERROR: %s failed to write %s - %v", clientID, writeValue, err))
-					ct.recordCSV(WriteDurabilityTest, clientID, sharedKey, "write", writeValue, false, duration, fmt.Sprintf("Write error: %v", err))
+					ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_ERROR: %s failed to write %s after %d attempts (category: %s) - %v", clientID, writeValue, attempts, category, err))
+					ct.recordOperation(WriteDurabilityTest, clientID, sharedKey, "write", writeValue, false, duration, fmt.Sprintf("Write error: %v", err))
 				} else {
-					ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_SUCCESS: %s wrote %s (duration: %v)", clientID, writeValue, duration))
-					ct.recordCSV(WriteDurabilityTest, clientID, sharedKey, "write", writeValue, true, duration, fmt.Sprintf("Write acknowledged: %s", writeValue))
+					ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_SUCCESS: %s wrote %s (duration: %v, attempts: %d)", clientID, writeValue, duration, attempts))
+					ct.recordOperation(WriteDurabilityTest, clientID, sharedKey, "write", writeValue, true, duration, fmt.Sprintf("Write acknowledged: %s", writeValue))
+					ct.readBackDurabilityWrite(ctx, testMap, clientID, sharedKey, writeValue, start)
 				}
 
-				// Small delay between writes from same client
-				time.Sleep(20 * time.Millisecond)
+				time.Sleep(ct.durabilityOpInterval)
 			}
 		}(clientID)
 	}
@@ -321,65 +649,78 @@ func (ct *ConcurrencyTest) writeDurabilityTest(ctx context.Context) error {
 	// Wait for all writes to settle
 	time.Sleep(100 * time.Millisecond)
 
-	// Read the final value and verify it matches one of the acknowledged writes
-	start := time.Now()
-	entry, err := testMap.Get(ctx, sharedKey)
-	duration := time.Since(start)
-
-	if err != nil {
-		ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_FINAL_READ_ERROR: Failed to read final value - %v", err))
-		ct.recordCSV(WriteDurabilityTest, "verification", sharedKey, "final-read", "", false, duration, fmt.Sprintf("Read error: %v", err))
-		ct.consistency.trackerMux.Lock()
-		ct.consistency.writeDurability = false
-		ct.consistency.trackerMux.Unlock()
-		return nil
-	}
-
-	if entry == nil {
-		ct.logMessage("WRITE_DURABILITY_FINAL_READ_NULL: Final value is null")
-		ct.recordCSV(WriteDurabilityTest, "verification", sharedKey, "final-read", "", false, duration, "Final value is null")
-		ct.consistency.trackerMux.Lock()
-		ct.consistency.writeDurability = false
-		ct.consistency.trackerMux.Unlock()
-		return nil
-	}
-
-	finalValue := entry.Value
-	ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_FINAL_VALUE: %s (duration: %v)", finalValue, duration))
-	ct.recordCSV(WriteDurabilityTest, "verification", sharedKey, "final-read", finalValue, true, duration, fmt.Sprintf("Final value: %s", finalValue))
-
-	// Verify that the final value matches one of the acknowledged writes
 	ct.consistency.durabilityMux.RLock()
 	totalWrites := len(ct.consistency.acknowledgedWrites)
 	acknowledgedWrites := 0
-	valueMatched := false
-
+	keySet := make(map[string]struct{})
 	for _, write := range ct.consistency.acknowledgedWrites {
+		keySet[write.Key] = struct{}{}
 		if write.Success {
 			acknowledgedWrites++
-			if write.Value == finalValue {
-				valueMatched = true
-				ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_MATCH: Final value matches acknowledged write from %s", write.ClientID))
-			}
 		}
 	}
 	ct.consistency.durabilityMux.RUnlock()
 
-	writeSuccessRate := float64(acknowledgedWrites) / float64(totalWrites) * 100
-	ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_STATS: %d/%d writes acknowledged (%.1f%%)", acknowledgedWrites, totalWrites, writeSuccessRate))
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	if valueMatched {
-		ct.logMessage("WRITE_DURABILITY_PASS: Final value matches an acknowledged write")
-		ct.recordCSV(WriteDurabilityTest, "verification", sharedKey, "durability-check", finalValue, true, 0, fmt.Sprintf("Final value matches acknowledged write: %s", finalValue))
-	} else {
-		ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_FAIL: Final value '%s' does NOT match any acknowledged write", finalValue))
-		ct.recordCSV(WriteDurabilityTest, "verification", sharedKey, "durability-check", finalValue, false, 0, fmt.Sprintf("Final value '%s' not in acknowledged writes", finalValue))
+	// Read each key's final value and verify it matches one of the acknowledged writes to that key
+	for _, sharedKey := range keys {
+		start := time.Now()
+		entry, err := testMap.Get(ctx, sharedKey)
+		duration := time.Since(start)
 
-		ct.consistency.trackerMux.Lock()
-		ct.consistency.writeDurability = false
-		ct.consistency.trackerMux.Unlock()
+		if err != nil {
+			ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_FINAL_READ_ERROR: Failed to read final value of %s - %v", sharedKey, err))
+			ct.recordOperation(WriteDurabilityTest, "verification", sharedKey, "final-read", "", false, duration, fmt.Sprintf("Read error: %v", err))
+			ct.consistency.trackerMux.Lock()
+			ct.consistency.writeDurability = false
+			ct.consistency.trackerMux.Unlock()
+			continue
+		}
+
+		if entry == nil {
+			ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_FINAL_READ_NULL: Final value of %s is null", sharedKey))
+			ct.recordOperation(WriteDurabilityTest, "verification", sharedKey, "final-read", "", false, duration, "Final value is null")
+			ct.consistency.trackerMux.Lock()
+			ct.consistency.writeDurability = false
+			ct.consistency.trackerMux.Unlock()
+			continue
+		}
+
+		finalValue := entry.Value
+		ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_FINAL_VALUE: %s = %s (duration: %v)", sharedKey, finalValue, duration))
+		ct.recordOperation(WriteDurabilityTest, "verification", sharedKey, "final-read", finalValue, true, duration, fmt.Sprintf("Final value: %s", finalValue))
+
+		valueMatched := false
+		ct.consistency.durabilityMux.RLock()
+		for _, write := range ct.consistency.acknowledgedWrites {
+			if write.Success && write.Key == sharedKey && write.Value == finalValue {
+				valueMatched = true
+				ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_MATCH: Final value of %s matches acknowledged write from %s", sharedKey, write.ClientID))
+			}
+		}
+		ct.consistency.durabilityMux.RUnlock()
+
+		if valueMatched {
+			ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_PASS: Final value of %s matches an acknowledged write", sharedKey))
+			ct.recordOperation(WriteDurabilityTest, "verification", sharedKey, "durability-check", finalValue, true, 0, fmt.Sprintf("Final value matches acknowledged write: %s", finalValue))
+		} else {
+			ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_FAIL: Final value '%s' of %s does NOT match any acknowledged write", finalValue, sharedKey))
+			ct.recordOperation(WriteDurabilityTest, "verification", sharedKey, "durability-check", finalValue, false, 0, fmt.Sprintf("Final value '%s' not in acknowledged writes", finalValue))
+
+			ct.consistency.trackerMux.Lock()
+			ct.consistency.writeDurability = false
+			ct.consistency.trackerMux.Unlock()
+		}
 	}
 
+	writeSuccessRate := float64(acknowledgedWrites) / float64(totalWrites) * 100
+	ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_STATS: %d/%d writes acknowledged (%.1f%%)", acknowledgedWrites, totalWrites, writeSuccessRate))
+
 	// Log all acknowledged writes for analysis
 	ct.consistency.durabilityMux.RLock()
 	for i, write := range ct.consistency.acknowledgedWrites {
@@ -392,12 +733,281 @@ func (ct *ConcurrencyTest) writeDurabilityTest(ctx context.Context) error {
 	return nil
 }
 
+// readBackDurabilityWrite reads key straight after clientID's write of value was acknowledged. A value
+// from a write still in flight when this one started may legitimately have overtaken it, but finding
+// the key missing, or holding a value whose write was acknowledged before this one was issued (or that
+// no write in this run produced), means the acknowledged write was lost.
+func (ct *ConcurrencyTest) readBackDurabilityWrite(ctx context.Context, testMap valuemap.Map, clientID, key, value string, start time.Time) {
+	readStart := time.Now()
+	entry, err := testMap.Get(ctx, key)
+	duration := time.Since(readStart)
+	if err != nil && !errors.IsNotFound(err) {
+		ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_READBACK_ERROR: %s failed to read back %s - %v", clientID, key, err))
+		ct.recordOperation(WriteDurabilityTest, clientID, key, "read-back", "", false, duration, fmt.Sprintf("Read error: %v", err))
+		return
+	}
+
+	observed := "<missing>"
+	stale := entry == nil
+	if entry != nil {
+		observed = entry.Value
+	}
+
+	ct.consistency.durabilityMux.Lock()
+	if !stale && observed != value {
+		window, known := ct.consistency.writeWindows[observed]
+		stale = !known || (!window.Acked.IsZero() && window.Acked.Before(start))
+	}
+	ct.consistency.readBacks++
+	if stale {
+		ct.consistency.staleReadBacks++
+	}
+	ct.consistency.durabilityMux.Unlock()
+
+	if stale {
+		ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_READBACK_STALE: %s wrote %s to %s but read back %s, which predates the write", clientID, value, key, observed))
+		ct.recordOperation(WriteDurabilityTest, clientID, key, "read-back", observed, false, duration, fmt.Sprintf("Acknowledged write %s lost", value))
+		ct.consistency.trackerMux.Lock()
+		ct.consistency.writeDurability = false
+		ct.consistency.trackerMux.Unlock()
+		return
+	}
+	ct.recordOperation(WriteDurabilityTest, clientID, key, "read-back", observed, true, duration, fmt.Sprintf("Observed: %s", observed))
+}
+
+// putWithRetry makes up to WRITE_ATTEMPTS Put attempts, retrying only transient (Unavailable and
+// DeadlineExceeded) failures with exponential backoff. It returns the attempts made and the category
+// of the last failure, which is empty if the first attempt succeeded.
+func (ct *ConcurrencyTest) putWithRetry(ctx context.Context, testMap valuemap.Map, clientID, key, value string) (int, string, error) {
+	backoff := 50 * time.Millisecond
+	category := ""
+	for attempt := 1; ; attempt++ {
+		err := testMap.Put(ctx, key, value)
+		if err == nil {
+			return attempt, category, nil
+		}
+		category = valuemap.ErrorCategory(err)
+		if attempt >= ct.writeAttempts || (category != valuemap.CategoryUnavailable && category != valuemap.CategoryDeadlineExceeded) {
+			return attempt, category, err
+		}
+
+		ct.logMessage(fmt.Sprintf("WRITE_DURABILITY_RETRY: %s attempt %d for %s failed (category: %s), retrying in %v - %v", clientID, attempt, key, category, backoff, err))
+		select {
+		case <-ctx.Done():
+			return attempt, category, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// Read-your-writes test: Each client writes unique values to its own key and immediately reads them back
+func (ct *ConcurrencyTest) readYourWritesTest(ctx context.Context) error {
+	ct.logMessage("READ_YOUR_WRITES_TEST_START: Testing that each client immediately observes its own writes")
+
+	testMap, err := valuemap.Open(ctx, ct.mapName, ct.valueType)
+	if err != nil {
+		return fmt.Errorf("failed to get map instance: %v", err)
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < ct.concurrentClients; i++ {
+		wg.Add(1)
+		clientID := fmt.Sprintf("ryw-client-%d", i+1)
+		clientNum := i + 1
+
+		go func(clientID string) {
+			defer wg.Done()
+
+			clientKey := fmt.Sprintf("ryw-key-%s", clientID)
+
+			for j := 1; j <= ct.operationsPerClient; j++ {
+				writeValue := ct.writeValue(clientID, clientNum, j)
+
+				start := time.Now()
+				err := testMap.Put(ctx, clientKey, writeValue)
+				duration := time.Since(start)
+
+				consistent := false
+				if err != nil {
+					ct.logMessage(fmt.Sprintf("READ_YOUR_WRITES_WRITE_ERROR: %s failed to write %s - %v", clientID, writeValue, err))
+					ct.recordOperation(ReadYourWritesTest, clientID, clientKey, "write", writeValue, false, duration, fmt.Sprintf("Write error: %v", err))
+				} else {
+					ct.recordOperation(ReadYourWritesTest, clientID, clientKey, "write", writeValue, true, duration, fmt.Sprintf("Write acknowledged: %s", writeValue))
+
+					start = time.Now()
+					entry, err := testMap.Get(ctx, clientKey)
+					duration = time.Since(start)
+
+					if err != nil {
+						ct.logMessage(fmt.Sprintf("READ_YOUR_WRITES_READ_ERROR: %s failed to read %s - %v", clientID, clientKey, err))
+						ct.recordOperation(ReadYourWritesTest, clientID, clientKey, "read", "", false, duration, fmt.Sprintf("Read error: %v", err))
+					} else if entry.Value != writeValue {
+						ct.logMessage(fmt.Sprintf("READ_YOUR_WRITES_VIOLATION: %s wrote %s but read %s", clientID, writeValue, entry.Value))
+						ct.recordOperation(ReadYourWritesTest, clientID, clientKey, "read", entry.Value, false, duration, fmt.Sprintf("Expected own write: %s", writeValue))
+					} else {
+						consistent = true
+						ct.logMessage(fmt.Sprintf("READ_YOUR_WRITES_SUCCESS: %s read back %s (duration: %v)", clientID, entry.Value, duration))
+						ct.recordOperation(ReadYourWritesTest, clientID, clientKey, "read", entry.Value, true, duration, "Read matches own write")
+					}
+				}
+
+				ct.consistency.rywMux.Lock()
+				ct.consistency.rywTotalPairs++
+				if consistent {
+					ct.consistency.rywConsistentPairs++
+				}
+				ct.consistency.rywMux.Unlock()
+			}
+		}(clientID)
+	}
+
+	wg.Wait()
+
+	ct.consistency.rywMux.Lock()
+	consistentPairs := ct.consistency.rywConsistentPairs
+	totalPairs := ct.consistency.rywTotalPairs
+	ct.consistency.rywMux.Unlock()
+
+	var consistencyRate float64
+	if totalPairs > 0 {
+		consistencyRate = float64(consistentPairs) / float64(totalPairs) * 100
+	}
+	ct.logEvent("READ_YOUR_WRITES_RESULT", fmt.Sprintf("%d/%d total pairs consistent (%.1f%%)", consistentPairs, totalPairs, consistencyRate),
+		map[string]any{"consistent": consistentPairs, "total": totalPairs, "rate": consistencyRate})
+
+	if consistentPairs != totalPairs {
+		ct.consistency.trackerMux.Lock()
+		ct.consistency.readYourWrites = false
+		ct.consistency.trackerMux.Unlock()
+	}
+
+	return nil
+}
+
+// No lost updates test: Clients concurrently increment a shared counter with versioned compare-and-set,
+// verify the final value equals the number of successful CAS operations
+func (ct *ConcurrencyTest) noLostUpdatesTest(ctx context.Context) error {
+	ct.logMessage("NO_LOST_UPDATES_TEST_START: Testing concurrent compare-and-set increments")
+
+	testMap, err := valuemap.Open(ctx, ct.mapName, ct.valueType)
+	if err != nil {
+		return fmt.Errorf("failed to get map instance: %v", err)
+	}
+
+	sharedKey := "shared-cas-key"
+	if err := testMap.Put(ctx, sharedKey, "0"); err != nil {
+		return fmt.Errorf("failed to initialize CAS key: %v", err)
+	}
+
+	const maxRetries = 100
+	var wg sync.WaitGroup
+
+	for i := 0; i < ct.concurrentClients; i++ {
+		wg.Add(1)
+		clientID := fmt.Sprintf("cas-client-%d", i+1)
+
+		go func(clientID string) {
+			defer wg.Done()
+
+			for j := 1; j <= ct.operationsPerClient; j++ {
+				start := time.Now()
+				attempts, err := mapcas.UpdateWithRetry(ctx, testMap, sharedKey, func(old string) (string, error) {
+					current, err := strconv.Atoi(old)
+					if err != nil {
+						return "", fmt.Errorf("read non-integer value '%s'", old)
+					}
+					return strconv.Itoa(current + 1), nil
+				}, maxRetries)
+				duration := time.Since(start)
+				succeeded := err == nil
+				if err != nil && !stderrors.Is(err, mapcas.ErrTooManyConflicts) {
+					ct.logMessage(fmt.Sprintf("NO_LOST_UPDATES_UPDATE_ERROR: %s failed to increment %s - %v", clientID, sharedKey, err))
+				}
+
+				ct.consistency.casMux.Lock()
+				ct.consistency.casAttempts += attempts
+				if succeeded {
+					ct.consistency.casSuccessful++
+				}
+				ct.consistency.casMux.Unlock()
+
+				if succeeded {
+					ct.logMessage(fmt.Sprintf("NO_LOST_UPDATES_CAS_SUCCESS: %s incremented %s after %d attempts (duration: %v)", clientID, sharedKey, attempts, duration))
+					ct.recordOperation(NoLostUpdatesTest, clientID, sharedKey, "cas-increment", "", true, duration, fmt.Sprintf("Attempts: %d", attempts))
+				} else {
+					ct.logMessage(fmt.Sprintf("NO_LOST_UPDATES_CAS_FAILED: %s gave up incrementing %s after %d attempts", clientID, sharedKey, attempts))
+					ct.recordOperation(NoLostUpdatesTest, clientID, sharedKey, "cas-increment", "", false, duration, fmt.Sprintf("Attempts: %d", attempts))
+				}
+			}
+		}(clientID)
+	}
+
+	wg.Wait()
+
+	ct.consistency.casMux.Lock()
+	successful := ct.consistency.casSuccessful
+	attempts := ct.consistency.casAttempts
+	ct.consistency.casMux.Unlock()
+
+	var successRate float64
+	if attempts > 0 {
+		successRate = float64(successful) / float64(attempts) * 100
+	}
+	ct.logEvent("NO_LOST_UPDATES_RESULT", fmt.Sprintf("%d/%d CAS operations successful (%.1f%%)", successful, attempts, successRate),
+		map[string]any{"key": sharedKey, "successful": successful, "attempts": attempts, "rate": successRate})
+
+	start := time.Now()
+	entry, err := testMap.Get(ctx, sharedKey)
+	duration := time.Since(start)
+	if err != nil {
+		ct.logMessage(fmt.Sprintf("NO_LOST_UPDATES_FINAL_READ_ERROR: Failed to read final value - %v", err))
+		ct.recordOperation(NoLostUpdatesTest, "verification", sharedKey, "final-read", "", false, duration, fmt.Sprintf("Read error: %v", err))
+		ct.consistency.trackerMux.Lock()
+		ct.consistency.noLostUpdates = false
+		ct.consistency.trackerMux.Unlock()
+		return nil
+	}
+
+	finalValue, err := strconv.Atoi(entry.Value)
+	if err != nil {
+		ct.logMessage(fmt.Sprintf("NO_LOST_UPDATES_FINAL_READ_ERROR: Final value '%s' is not an integer", entry.Value))
+		ct.recordOperation(NoLostUpdatesTest, "verification", sharedKey, "final-read", entry.Value, false, duration, "Final value is not an integer")
+		ct.consistency.trackerMux.Lock()
+		ct.consistency.noLostUpdates = false
+		ct.consistency.trackerMux.Unlock()
+		return nil
+	}
+
+	ct.consistency.casMux.Lock()
+	ct.consistency.casFinalValue = finalValue
+	ct.consistency.casMux.Unlock()
+
+	ct.recordOperation(NoLostUpdatesTest, "verification", sharedKey, "final-read", entry.Value, true, duration, fmt.Sprintf("Final value: %d, Expected: %d", finalValue, successful))
+
+	if finalValue == successful {
+		ct.logEvent("NO_LOST_UPDATES_PASS", "No lost updates detected",
+			map[string]any{"key": sharedKey, "value": finalValue, "successful": successful, "duration_ns": duration.Nanoseconds()})
+	} else {
+		ct.logEvent("NO_LOST_UPDATES_FAIL", fmt.Sprintf("Lost updates detected! %d operations lost (final value: %d, successful CAS: %d)", successful-finalValue, finalValue, successful),
+			map[string]any{"key": sharedKey, "value": finalValue, "successful": successful, "lost": successful - finalValue, "duration_ns": duration.Nanoseconds()})
+		ct.consistency.trackerMux.Lock()
+		ct.consistency.noLostUpdates = false
+		ct.consistency.trackerMux.Unlock()
+	}
+
+	return nil
+}
+
 func (ct *ConcurrencyTest) runConcurrencyTests(ctx context.Context) error {
 	ct.logMessage("LINEARIZABILITY_TEST_SUITE_START: Starting linearizability and write durability testing")
-	ct.logMessage(fmt.Sprintf("CONFIG: Concurrent clients: %d, Operations per client: %d, Duration: %v",
-		ct.concurrentClients, ct.operationsPerClient, ct.testDuration))
+	ct.logMessage(fmt.Sprintf("CONFIG: Map: %s, Value type: %s, Value size: %s, Concurrent clients: %d, Operations per client: %d, Duration: %v",
+		ct.mapName, ct.valueType, valuemap.SizeLabel(ct.valueSize), ct.concurrentClients, ct.operationsPerClient, ct.testDuration))
+	ct.logMessage(fmt.Sprintf("PACING: Linearizability op interval: %s, Durability op interval: %s",
+		opIntervalLabel(ct.linearizabilityOpInterval), opIntervalLabel(ct.durabilityOpInterval)))
 
-	testMap, err := atomix.Map[string, string]("concurrency-test-map").Codec(generic.Scalar[string]()).Get(ctx)
+	testMap, err := valuemap.Open(ctx, ct.mapName, ct.valueType)
 	if err != nil {
 		return fmt.Errorf("failed to initialize test map: %v", err)
 	}
@@ -405,7 +1015,10 @@ func (ct *ConcurrencyTest) runConcurrencyTests(ctx context.Context) error {
 	// Initial connectivity test
 	initialKey := "linearizability-connectivity-test"
 	initialValue := fmt.Sprintf("initialized-%d", time.Now().Unix())
-	_, err = testMap.Put(ctx, initialKey, initialValue)
+	if ct.valueType != "string" {
+		initialValue = strconv.FormatInt(time.Now().Unix(), 10)
+	}
+	err = testMap.Put(ctx, initialKey, initialValue)
 	if err != nil {
 		return fmt.Errorf("failed initial connectivity test: %v", err)
 	}
@@ -427,6 +1040,8 @@ func (ct *ConcurrencyTest) runConcurrencyTests(ctx context.Context) error {
 	}{
 		{"Linearizability", ct.linearizabilityTest},
 		{"Write Durability", ct.writeDurabilityTest},
+		{"Read Your Writes", ct.readYourWritesTest},
+		{"No Lost Updates", ct.noLostUpdatesTest},
 	}
 
 	for _, test := range tests {
@@ -455,35 +1070,72 @@ func (ct *ConcurrencyTest) generateFinalReport() error {
 	ct.consistency.trackerMux.RLock()
 	linearizable := ct.consistency.linearizable
 	writeDurability := ct.consistency.writeDurability
+	readYourWrites := ct.consistency.readYourWrites
+	noLostUpdates := ct.consistency.noLostUpdates
 	ct.consistency.trackerMux.RUnlock()
 
 	// Get linearizability details
 	ct.consistency.sequenceMux.RLock()
-	finalValue := ct.consistency.finalValue
+	finalValue := formatFinalValues(ct.consistency.finalValues)
 	totalClientSequences := len(ct.consistency.clientSequences)
+	monotonicityViolations := ct.consistency.monotonicityViolations
+	linearizabilityHistogram := latencyHistogram(ct.consistency.writeDurations)
 	ct.consistency.sequenceMux.RUnlock()
 
 	// Get write durability details
 	ct.consistency.durabilityMux.RLock()
 	totalWrites := len(ct.consistency.acknowledgedWrites)
 	acknowledgedWrites := 0
+	retriedWrites := 0
+	failedByCategory := make(map[string]int)
+	readBacks := ct.consistency.readBacks
+	staleReadBacks := ct.consistency.staleReadBacks
+	var durabilityDurations []time.Duration
 	for _, write := range ct.consistency.acknowledgedWrites {
 		if write.Success {
 			acknowledgedWrites++
+			durabilityDurations = append(durabilityDurations, write.Duration)
+			if write.Attempts > 1 {
+				retriedWrites++
+			}
+		} else {
+			failedByCategory[write.Category]++
 		}
 	}
 	ct.consistency.durabilityMux.RUnlock()
+	durabilityHistogram := latencyHistogram(durabilityDurations)
 
-	ct.logMessage(fmt.Sprintf("LINEARIZABILITY: %t (Final value: %s, Client sequences: %d)", linearizable, finalValue, totalClientSequences))
+	// Get read-your-writes details
+	ct.consistency.rywMux.Lock()
+	rywConsistentPairs := ct.consistency.rywConsistentPairs
+	rywTotalPairs := ct.consistency.rywTotalPairs
+	ct.consistency.rywMux.Unlock()
+
+	// Get no-lost-updates details
+	ct.consistency.casMux.Lock()
+	casSuccessful := ct.consistency.casSuccessful
+	casFinalValue := ct.consistency.casFinalValue
+	ct.consistency.casMux.Unlock()
+
+	ct.logMessage(fmt.Sprintf("LINEARIZABILITY: %t (Final value: %s, Client sequences: %d, Monotonicity violations: %d)", linearizable, finalValue, totalClientSequences, monotonicityViolations))
 	ct.logMessage(fmt.Sprintf("WRITE_DURABILITY: %t (Acknowledged writes: %d/%d)", writeDurability, acknowledgedWrites, totalWrites))
+	ct.logMessage(fmt.Sprintf("WRITE_RETRIES: %d acknowledged writes succeeded only after retry, %d failed permanently (%s)",
+		retriedWrites, totalWrites-acknowledgedWrites, formatCategories(failedByCategory)))
+	ct.logMessage(fmt.Sprintf("WRITE_READBACKS: %d/%d acknowledged writes read back stale or missing", staleReadBacks, readBacks))
+
+	ct.logMessage(fmt.Sprintf("READ_YOUR_WRITES: %t (Consistent pairs: %d/%d)", readYourWrites, rywConsistentPairs, rywTotalPairs))
+
+	ct.logMessage(fmt.Sprintf("NO_LOST_UPDATES: %t (Final value: %d, Successful CAS: %d)", noLostUpdates, casFinalValue, casSuccessful))
 
-	allPassed := linearizable && writeDurability
-	ct.logMessage(fmt.Sprintf("OVERALL_RESULT: %t", allPassed))
+	allPassed := linearizable && writeDurability && readYourWrites && noLostUpdates
+	ct.logEvent("OVERALL_RESULT", fmt.Sprintf("%t", allPassed), map[string]any{"passed": allPassed})
 
 	// Detailed statistics
 	writeSuccessRate := float64(acknowledgedWrites) / float64(totalWrites) * 100
 	ct.logMessage(fmt.Sprintf("STATISTICS: Write Success Rate: %.1f%% (%d/%d)",
 		writeSuccessRate, acknowledgedWrites, totalWrites))
+	ct.logMessage(fmt.Sprintf("LATENCY_HISTOGRAM: Linearizability writes - %s", formatHistogram(linearizabilityHistogram)))
+	ct.logMessage(fmt.Sprintf("LATENCY_HISTOGRAM: Durability writes - %s", formatHistogram(durabilityHistogram)))
 
 	// Create summary file
 	summaryFile := "linearizability-summary.txt"
@@ -498,16 +1150,32 @@ func (ct *ConcurrencyTest) generateFinalReport() error {
 	summary.WriteString(fmt.Sprintf("Configuration: %d clients, %d operations per client\n",
 		ct.concurrentClients, ct.operationsPerClient))
 	summary.WriteString("\nTEST OBJECTIVES:\n")
-	summary.WriteString("1. Linearizability: Multiple clients write sequences to same key, final value must be from a LAST write\n")
-	summary.WriteString("2. Write Durability: Multiple clients write concurrently, all acknowledged writes must persist\n")
+	summary.WriteString("1. Linearizability: Multiple clients write sequences to same key, final value must be from a LAST write and no client may observe a writer's value go backwards\n")
+	summary.WriteString("2. Write Durability: Multiple clients write concurrently, all acknowledged writes must persist and be readable straight after their ack\n")
+	summary.WriteString("3. Read Your Writes: Each client must immediately read back its own acknowledged write\n")
+	summary.WriteString("4. No Lost Updates: Concurrent compare-and-set increments must all be reflected in the final value\n")
 	summary.WriteString("\nRESULTS:\n")
 	summary.WriteString(fmt.Sprintf("Linearizability: %s\n", map[bool]string{true: "PASS", false: "FAIL"}[linearizable]))
 	summary.WriteString(fmt.Sprintf("Write Durability: %s\n", map[bool]string{true: "PASS", false: "FAIL"}[writeDurability]))
+	summary.WriteString(fmt.Sprintf("Read Your Writes: %s\n", map[bool]string{true: "PASS", false: "FAIL"}[readYourWrites]))
+	summary.WriteString(fmt.Sprintf("No Lost Updates: %s\n", map[bool]string{true: "PASS", false: "FAIL"}[noLostUpdates]))
 	summary.WriteString(fmt.Sprintf("Overall: %s\n", map[bool]string{true: "PASS", false: "FAIL"}[allPassed]))
 	summary.WriteString("\nDETAILS:\n")
 	summary.WriteString(fmt.Sprintf("Final Value from Linearizability Test: %s\n", finalValue))
 	summary.WriteString(fmt.Sprintf("Client Sequences Processed: %d\n", totalClientSequences))
+	summary.WriteString(fmt.Sprintf("Monotonicity Violations: %d\n", monotonicityViolations))
 	summary.WriteString(fmt.Sprintf("Write Success Rate: %.1f%% (%d/%d writes acknowledged)\n", writeSuccessRate, acknowledgedWrites, totalWrites))
+	summary.WriteString(fmt.Sprintf("Writes Acknowledged After Retry: %d (up to %d attempts per write)\n", retriedWrites, ct.writeAttempts))
+	summary.WriteString(fmt.Sprintf("Writes Failed Permanently: %d (%s)\n", totalWrites-acknowledgedWrites, formatCategories(failedByCategory)))
+	summary.WriteString(fmt.Sprintf("Acknowledged Writes Read Back Stale: %d/%d\n", staleReadBacks, readBacks))
+	summary.WriteString(fmt.Sprintf("Read-Your-Writes Pairs Consistent: %d/%d\n", rywConsistentPairs, rywTotalPairs))
+	summary.WriteString(fmt.Sprintf("No Lost Updates Final Value: %d (Successful CAS: %d)\n", casFinalValue, casSuccessful))
+
+	summary.WriteString("\nWRITE LATENCY HISTOGRAM (successful writes):\n")
+	summary.WriteString(fmt.Sprintf("%-10s %16s %11s\n", "Bucket", "Linearizability", "Durability"))
+	for i, bucket := range latencyBuckets {
+		summary.WriteString(fmt.Sprintf("%-10s %16d %11d\n", bucket.label, linearizabilityHistogram[i], durabilityHistogram[i]))
+	}
 
 	// Add client sequence details
 	ct.consistency.sequenceMux.RLock()
@@ -523,40 +1191,35 @@ func (ct *ConcurrencyTest) generateFinalReport() error {
 }
 
 func (ct *ConcurrencyTest) Close() {
-	if ct.csvWriter != nil {
-		ct.csvWriter.Flush()
-	}
-	if ct.csvFile != nil {
-		ct.csvFile.Close()
-	}
-	if ct.logFile != nil {
-		ct.logFile.Close()
+	if ct.sink != nil {
+		ct.sink.Close()
 	}
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// getEnvInterval reads an operation interval from key, falling back to fallbackKey and then defaultValue.
+// Unlike envutil.Duration it rejects a bad value instead of using the default, and a bare number isn't read as seconds.
+func getEnvInterval(key, fallbackKey string, defaultValue time.Duration) (time.Duration, error) {
+	name := key
+	value := os.Getenv(key)
+	if value == "" {
+		name, value = fallbackKey, os.Getenv(fallbackKey)
 	}
-	return defaultValue
-}
-
-func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+	if value == "" {
+		return defaultValue, nil
 	}
-	return defaultValue
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < 0 {
+		return 0, fmt.Errorf("invalid %s '%s'. Must be a non-negative duration such as '10ms', or 0 for no pause", name, value)
+	}
+	return interval, nil
 }
 
-func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if seconds, err := strconv.Atoi(value); err == nil {
-			return time.Duration(seconds) * time.Second
-		}
+// opIntervalLabel describes an operation interval for the PACING line
+func opIntervalLabel(interval time.Duration) string {
+	if interval == 0 {
+		return "none (maximum contention)"
 	}
-	return defaultValue
+	return interval.String()
 }
 
 func main() {
@@ -0,0 +1,577 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/atomix/go-sdk/pkg/atomix"
+	"github.com/atomix/go-sdk/pkg/generic"
+	"github.com/atomix/go-sdk/pkg/primitive/set"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	"example.com/envutil"
+	"example.com/raftwatch"
+)
+
+// ElementState is the last outcome the test observed for an element. The "unknown"
+// states cover operations that failed mid-failover and may or may not have been applied.
+type ElementState int
+
+const (
+	StateAddAcked ElementState = iota
+	StateAddUnknown
+	StateRemoveAcked
+	StateRemoveUnknown
+)
+
+func (s ElementState) String() string {
+	switch s {
+	case StateAddAcked:
+		return "add-acked"
+	case StateAddUnknown:
+		return "add-unknown"
+	case StateRemoveAcked:
+		return "remove-acked"
+	case StateRemoveUnknown:
+		return "remove-unknown"
+	default:
+		return "invalid"
+	}
+}
+
+type SetFailoverTest struct {
+	addSeq           int64
+	elements         map[string]ElementState
+	elementsMux      sync.RWMutex
+	leaderStatus     string
+	leaderCache      map[int]raftwatch.LeaderInfo
+	leaderMux        sync.RWMutex
+	raftWatcher      *raftwatch.Watcher
+	logFile          *os.File
+	writeInterval    time.Duration
+	testDuration     time.Duration
+	failoverInterval time.Duration
+	setName          string
+
+	// unparseableGroups is how many RaftGroups were last reported as not matching GROUP_NAME_PATTERN, guarded by leaderMux
+	unparseableGroups int
+
+	testSet    set.Set[string]
+	testSetMux sync.Mutex
+}
+
+func NewSetFailoverTest() (*SetFailoverTest, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-cluster config: %v", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %v", err)
+	}
+
+	writeInterval := envutil.Duration("WRITE_INTERVAL", 1*time.Second)
+	testDuration := envutil.Duration("TEST_DURATION", 10*time.Minute)
+	failoverInterval := envutil.Duration("FAILOVER_INTERVAL", 60*time.Second)
+	logFileName := envutil.Get("LOG_FILE", "set-failover-test-results.log")
+	namespace := envutil.Get("NAMESPACE", "default")
+	setName := envutil.Get("SET_NAME", "test-set")
+	storeName := envutil.Get("STORE_NAME", raftwatch.DefaultStoreName)
+
+	// The 5s leaderMonitor ticker already paces the RaftGroup lookups
+	raftWatcher := raftwatch.New(dynamicClient, namespace, storeName, 0)
+	if pattern := os.Getenv("GROUP_NAME_PATTERN"); pattern != "" {
+		if err := raftWatcher.SetGroupPattern(pattern); err != nil {
+			return nil, fmt.Errorf("invalid GROUP_NAME_PATTERN '%s': %v", pattern, err)
+		}
+	}
+	// An unknown store would otherwise just look like one that never elects a leader
+	checkCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := raftWatcher.CheckStore(checkCtx); err != nil {
+		return nil, fmt.Errorf("STORE_NAME '%s': %v", storeName, err)
+	}
+
+	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %v", err)
+	}
+
+	return &SetFailoverTest{
+		elements:         make(map[string]ElementState),
+		leaderCache:      make(map[int]raftwatch.LeaderInfo),
+		raftWatcher:      raftWatcher,
+		logFile:          logFile,
+		writeInterval:    writeInterval,
+		testDuration:     testDuration,
+		failoverInterval: failoverInterval,
+		setName:          setName,
+	}, nil
+}
+
+func (st *SetFailoverTest) logMessage(message string) {
+	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
+	logEntry := fmt.Sprintf("[%s] %s\n", timestamp, message)
+	fmt.Print(logEntry)
+	st.logFile.WriteString(logEntry)
+	st.logFile.Sync()
+}
+
+// getTestSet returns the cached test-set handle, acquiring it if there isn't one
+func (st *SetFailoverTest) getTestSet(ctx context.Context) (set.Set[string], error) {
+	st.testSetMux.Lock()
+	defer st.testSetMux.Unlock()
+
+	if st.testSet != nil {
+		return st.testSet, nil
+	}
+
+	testSet, err := atomix.Set[string](st.setName).Codec(generic.Scalar[string]()).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st.testSet = testSet
+	return testSet, nil
+}
+
+// resetTestSet drops the cached handle after an operation error so the next operation re-acquires it
+func (st *SetFailoverTest) resetTestSet(testSet set.Set[string]) {
+	st.testSetMux.Lock()
+	defer st.testSetMux.Unlock()
+
+	if st.testSet == testSet {
+		st.testSet = nil
+	}
+}
+
+func (st *SetFailoverTest) setState(element string, state ElementState) {
+	st.elementsMux.Lock()
+	st.elements[element] = state
+	st.elementsMux.Unlock()
+}
+
+func (st *SetFailoverTest) continuousAdder(ctx context.Context) {
+	ticker := time.NewTicker(st.writeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st.addNext(ctx)
+		}
+	}
+}
+
+func (st *SetFailoverTest) addNext(ctx context.Context) {
+	testSet, err := st.getTestSet(ctx)
+	if err != nil {
+		st.logMessage(fmt.Sprintf("SET_ADD_ERROR: Failed to get set instance: %v", err))
+		return
+	}
+
+	seq := atomic.AddInt64(&st.addSeq, 1)
+	element := fmt.Sprintf("element-%06d", seq)
+	st.setState(element, StateAddUnknown)
+
+	start := time.Now()
+	added, err := testSet.Add(ctx, element)
+	duration := time.Since(start)
+
+	if err != nil {
+		st.resetTestSet(testSet)
+		st.logMessage(fmt.Sprintf("SET_ADD_FAILED: %s (duration: %v, error: %v)", element, duration, err))
+		return
+	}
+
+	st.setState(element, StateAddAcked)
+	st.logMessage(fmt.Sprintf("SET_ADD_SUCCESS: %s (added: %t, duration: %v)", element, added, duration))
+}
+
+func (st *SetFailoverTest) continuousRemover(ctx context.Context) {
+	ticker := time.NewTicker(2 * st.writeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st.removeNext(ctx)
+		}
+	}
+}
+
+// removeNext removes a random element whose add was acknowledged, so every remove targets
+// an element the set must contain
+func (st *SetFailoverTest) removeNext(ctx context.Context) {
+	st.elementsMux.Lock()
+	var candidates []string
+	for element, state := range st.elements {
+		if state == StateAddAcked {
+			candidates = append(candidates, element)
+		}
+	}
+	if len(candidates) == 0 {
+		st.elementsMux.Unlock()
+		return
+	}
+	element := candidates[rand.Intn(len(candidates))]
+	st.elements[element] = StateRemoveUnknown
+	st.elementsMux.Unlock()
+
+	testSet, err := st.getTestSet(ctx)
+	if err != nil {
+		st.setState(element, StateAddAcked)
+		st.logMessage(fmt.Sprintf("SET_REMOVE_ERROR: Failed to get set instance: %v", err))
+		return
+	}
+
+	start := time.Now()
+	removed, err := testSet.Remove(ctx, element)
+	duration := time.Since(start)
+
+	if err != nil {
+		st.resetTestSet(testSet)
+		st.logMessage(fmt.Sprintf("SET_REMOVE_FAILED: %s (duration: %v, error: %v)", element, duration, err))
+		return
+	}
+
+	st.setState(element, StateRemoveAcked)
+	st.logMessage(fmt.Sprintf("SET_REMOVE_SUCCESS: %s (removed: %t, duration: %v)", element, removed, duration))
+	if !removed {
+		st.logMessage(fmt.Sprintf("SET_REMOVE_MISSING: %s was acknowledged as added but was not in the set", element))
+	}
+}
+
+func (st *SetFailoverTest) updateLeaderInfo(ctx context.Context) (string, error) {
+	leaders, err := st.raftWatcher.Leaders(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	partitions := make([]int, 0, len(leaders))
+	for partNum := range leaders {
+		partitions = append(partitions, partNum)
+	}
+	sort.Ints(partitions)
+
+	var leaderInfo []string
+	for _, partNum := range partitions {
+		leader := leaders[partNum]
+		if leader.MemberName == "" {
+			leaderInfo = append(leaderInfo, fmt.Sprintf("Partition %d: No Leader", partNum))
+			continue
+		}
+		leaderInfo = append(leaderInfo, fmt.Sprintf("Partition %d: %s (term: %d, %s)", partNum, leader.MemberName, leader.Term, leader.State))
+	}
+
+	st.leaderMux.Lock()
+	st.leaderCache = leaders
+	if unparseable := st.raftWatcher.UnparseableGroups(); len(unparseable) != st.unparseableGroups {
+		st.unparseableGroups = len(unparseable)
+		if len(unparseable) > 0 {
+			st.logMessage(fmt.Sprintf("WARNING: %d RaftGroup(s) ignored because their names don't match GROUP_NAME_PATTERN %s: %s", len(unparseable), st.raftWatcher.GroupPattern(), strings.Join(unparseable, ", ")))
+		}
+	}
+	st.leaderMux.Unlock()
+
+	return strings.Join(leaderInfo, ", "), nil
+}
+
+func (st *SetFailoverTest) leaderMonitor(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			newStatus, err := st.updateLeaderInfo(ctx)
+			if err != nil {
+				st.logMessage(fmt.Sprintf("LEADER_ERROR: %v", err))
+				continue
+			}
+
+			st.leaderMux.Lock()
+			if st.leaderStatus != newStatus {
+				st.logMessage(fmt.Sprintf("LEADER_CHANGE: %s", newStatus))
+				st.leaderStatus = newStatus
+			}
+			st.leaderMux.Unlock()
+		}
+	}
+}
+
+// failoverInjector terminates a random partition leader every FAILOVER_INTERVAL while
+// the adder and remover are running
+func (st *SetFailoverTest) failoverInjector(ctx context.Context) {
+	ticker := time.NewTicker(st.failoverInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			st.leaderMux.RLock()
+			var leaders []raftwatch.LeaderInfo
+			for _, leader := range st.leaderCache {
+				if leader.MemberName != "" {
+					leaders = append(leaders, leader)
+				}
+			}
+			st.leaderMux.RUnlock()
+
+			if len(leaders) == 0 {
+				st.logMessage("FAILOVER_SKIPPED: No known partition leaders")
+				continue
+			}
+
+			leader := leaders[rand.Intn(len(leaders))]
+			if err := st.terminateLeaderPod(ctx, leader); err != nil {
+				st.logMessage(fmt.Sprintf("TERMINATION_FAILED: %v", err))
+			}
+		}
+	}
+}
+
+func (st *SetFailoverTest) terminateLeaderPod(ctx context.Context, leader raftwatch.LeaderInfo) error {
+	podName, err := st.raftWatcher.LeaderPod(leader)
+	if err != nil {
+		return err
+	}
+
+	st.logMessage(fmt.Sprintf("FORCED_TERMINATION: Terminating leader pod %s (member %s) for partition %d (term: %d)", podName, leader.MemberName, leader.PartitionID, leader.Term))
+
+	if err := st.raftWatcher.TerminateLeader(ctx, leader); err != nil {
+		return err
+	}
+
+	st.logMessage(fmt.Sprintf("TERMINATION_SUCCESS: Pod %s terminated", podName))
+	return nil
+}
+
+// readElements lists the set contents, retrying until the cluster has recovered or the timeout expires
+func (st *SetFailoverTest) readElements(ctx context.Context, timeout time.Duration) (map[string]bool, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		actual, err := st.listElements(ctx)
+		if err == nil {
+			return actual, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+
+		st.logMessage(fmt.Sprintf("SET_VERIFY_RETRY: Failed to list set elements: %v", err))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func (st *SetFailoverTest) listElements(ctx context.Context) (map[string]bool, error) {
+	testSet, err := st.getTestSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := testSet.Elements(ctx)
+	if err != nil {
+		st.resetTestSet(testSet)
+		return nil, err
+	}
+
+	actual := make(map[string]bool)
+	for {
+		element, err := stream.Next()
+		if err == io.EOF {
+			return actual, nil
+		}
+		if err != nil {
+			st.resetTestSet(testSet)
+			return nil, err
+		}
+		actual[element] = true
+	}
+}
+
+// verifySet checks the recovered set against the acknowledged operations: every acknowledged add
+// that was not later removed must be present (no lost adds), and nothing may be present that was
+// never added or whose removal was acknowledged (no phantoms)
+func (st *SetFailoverTest) verifySet(ctx context.Context) bool {
+	st.logMessage("SET_VERIFY_START: Waiting for the set to become readable")
+
+	actual, err := st.readElements(ctx, 60*time.Second)
+	if err != nil {
+		st.logMessage(fmt.Sprintf("SET_VERIFY_FAIL: Could not read set after recovery: %v", err))
+		return false
+	}
+
+	st.elementsMux.RLock()
+	defer st.elementsMux.RUnlock()
+
+	var lost, phantoms []string
+	counts := make(map[ElementState]int)
+	for element, state := range st.elements {
+		counts[state]++
+		switch state {
+		case StateAddAcked:
+			if !actual[element] {
+				lost = append(lost, element)
+			}
+		case StateRemoveAcked:
+			if actual[element] {
+				phantoms = append(phantoms, element)
+			}
+		}
+	}
+	for element := range actual {
+		if _, ok := st.elements[element]; !ok {
+			phantoms = append(phantoms, element)
+		}
+	}
+	sort.Strings(lost)
+	sort.Strings(phantoms)
+
+	for _, element := range lost {
+		st.logMessage(fmt.Sprintf("SET_VERIFY_LOST_ADD: %s was acknowledged as added but is missing", element))
+	}
+	for _, element := range phantoms {
+		state := "never-added"
+		if s, ok := st.elements[element]; ok {
+			state = s.String()
+		}
+		st.logMessage(fmt.Sprintf("SET_VERIFY_PHANTOM: %s is present but should not be (%s)", element, state))
+	}
+
+	st.logMessage(fmt.Sprintf("SET_VERIFY_RESULT: Set size: %d, Acked adds: %d, Acked removes: %d, Unknown adds: %d, Unknown removes: %d, Lost adds: %d, Phantoms: %d",
+		len(actual), counts[StateAddAcked], counts[StateRemoveAcked], counts[StateAddUnknown], counts[StateRemoveUnknown], len(lost), len(phantoms)))
+
+	if len(lost) > 0 || len(phantoms) > 0 {
+		st.logMessage("SET_VERIFY_FAIL: Set membership invariants violated")
+		return false
+	}
+	st.logMessage("SET_VERIFY_PASS: No lost adds or phantom elements")
+	return true
+}
+
+func (st *SetFailoverTest) runTest(ctx context.Context) error {
+	st.logMessage("STARTING Atomix Set Failover Test")
+	st.logMessage(fmt.Sprintf("CONFIG: Set: %s, Write interval: %v, Failover interval: %v, Test duration: %v", st.setName, st.writeInterval, st.failoverInterval, st.testDuration))
+
+	testSet, err := st.getTestSet(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize test set: %v", err)
+	}
+
+	if err := testSet.Clear(ctx); err != nil {
+		return fmt.Errorf("failed to clear test set: %v", err)
+	}
+
+	initialElement := fmt.Sprintf("connectivity-%d", time.Now().Unix())
+	if _, err := testSet.Add(ctx, initialElement); err != nil {
+		return fmt.Errorf("failed initial connectivity test: %v", err)
+	}
+	contains, err := testSet.Contains(ctx, initialElement)
+	if err != nil {
+		return fmt.Errorf("failed initial read test: %v", err)
+	}
+	if !contains {
+		return fmt.Errorf("initial read consistency test failed: %s not found", initialElement)
+	}
+	if _, err := testSet.Remove(ctx, initialElement); err != nil {
+		return fmt.Errorf("failed initial remove test: %v", err)
+	}
+
+	st.logMessage("CONNECTIVITY: Initial connectivity and consistency verified")
+
+	if _, err := st.updateLeaderInfo(ctx); err != nil {
+		st.logMessage(fmt.Sprintf("LEADER_ERROR: %v", err))
+	}
+
+	var wg sync.WaitGroup
+
+	testCtx, cancel := context.WithTimeout(ctx, st.testDuration)
+	defer cancel()
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		st.continuousAdder(testCtx)
+	}()
+
+	go func() {
+		defer wg.Done()
+		st.continuousRemover(testCtx)
+	}()
+
+	go func() {
+		defer wg.Done()
+		st.leaderMonitor(testCtx)
+	}()
+
+	if st.failoverInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			st.failoverInjector(testCtx)
+		}()
+	}
+
+	wg.Wait()
+
+	passed := st.verifySet(ctx)
+	st.logMessage(fmt.Sprintf("COMPLETED: Test finished. Total adds attempted: %d, Verification passed: %t", atomic.LoadInt64(&st.addSeq), passed))
+
+	return nil
+}
+
+func (st *SetFailoverTest) Close() {
+	if st.logFile != nil {
+		st.logFile.Close()
+	}
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	setTest, err := NewSetFailoverTest()
+	if err != nil {
+		log.Fatalf("Failed to initialize set failover test: %v", err)
+	}
+	defer setTest.Close()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-c
+		setTest.logMessage("INTERRUPT: Received interrupt signal, shutting down gracefully...")
+		cancel()
+	}()
+
+	if err := setTest.runTest(ctx); err != nil {
+		setTest.logMessage(fmt.Sprintf("FATAL: Test failed: %v", err))
+		os.Exit(1)
+	}
+}
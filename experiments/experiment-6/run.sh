@@ -0,0 +1,51 @@
+#!/bin/bash
+
+echo "Starting Atomix Set Failover Test Setup..."
+
+echo "(Re)starting minikube..."
+minikube delete
+minikube start
+
+echo "Installing atomix-runtime..."
+helm install -n kube-system atomix-runtime atomix/atomix-runtime --wait
+echo "Installed atomix-runtime."
+
+echo "Building docker image..."
+docker build -t set-failover-client:local -f Dockerfile ..
+echo "Built docker image, loading into minikube..."
+minikube image load set-failover-client:local
+echo "Docker image loaded."
+
+echo "Applying kubernetes configs..."
+kubectl apply -f consensus-store.yaml
+kubectl apply -f storage-profile.yaml
+
+echo "Waiting for consensus-store to be ready..."
+kubectl wait --for=condition=ready pod -l app.kubernetes.io/name=consensus-store --timeout=300s
+
+echo "Applying deployment (with RBAC permissions for pod termination)..."
+kubectl apply -f deployment.yaml
+
+echo "Waiting for set-failover-experiment deployment to be ready..."
+kubectl wait --for=condition=ready pod -l name=set-failover-experiment --timeout=120s
+
+echo ""
+echo "=== Atomix Set Failover Test Environment Ready ==="
+echo ""
+echo "To monitor the test:"
+echo "  kubectl logs -f deployment/set-failover-experiment"
+echo ""
+echo "To access test logs from inside the pod:"
+echo "  kubectl exec -it deployment/set-failover-experiment -- cat /app/logs/set-failover-test-results.log"
+echo ""
+echo "Test Configuration:"
+echo "  - Add Interval: 1 second (removes every 2 seconds)"
+echo "  - Leader Termination Interval: 60 seconds (FAILOVER_INTERVAL=0 disables)"
+echo "  - Test Duration: 10 minutes"
+echo "  - ConsensusStore Replicas: 3"
+echo ""
+echo "Log lines for analysis:"
+echo "  - SET_ADD_SUCCESS / SET_ADD_FAILED"
+echo "  - SET_REMOVE_SUCCESS / SET_REMOVE_FAILED"
+echo "  - SET_VERIFY_LOST_ADD / SET_VERIFY_PHANTOM / SET_VERIFY_RESULT"
+echo ""
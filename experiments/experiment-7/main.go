@@ -0,0 +1,551 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/atomix/go-sdk/pkg/atomix"
+	"github.com/atomix/go-sdk/pkg/generic"
+	"github.com/atomix/go-sdk/pkg/primitive/election"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"example.com/envutil"
+)
+
+// FailoverSample is one election's failover in one round. Latency is measured from the pod delete
+// to the first term the election Watch reports with a different, non-empty leader.
+type FailoverSample struct {
+	Round     int
+	Election  string
+	OldLeader string
+	NewLeader string
+	OldTerm   uint64
+	NewTerm   uint64
+	Latency   time.Duration
+	TimedOut  bool
+}
+
+type ElectionFailoverTest struct {
+	k8sClient          *kubernetes.Clientset
+	logFile            *os.File
+	electionCount      int
+	rounds             int
+	failoverTimeout    time.Duration
+	recoveryTimeout    time.Duration
+	roundInterval      time.Duration
+	gracePeriod        int64
+	namespace          string
+	controllerSelector string
+	deviceMapName      string
+	devicePrefix       string
+
+	deviceMap _map.Map[string, string]
+	elections map[string]election.Election // keyed by election name
+	samples   []FailoverSample
+}
+
+func NewElectionFailoverTest() (*ElectionFailoverTest, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get in-cluster config: %v", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %v", err)
+	}
+
+	electionCount, err := strconv.Atoi(envutil.Get("ELECTION_COUNT", "5"))
+	if err != nil || electionCount < 1 {
+		return nil, fmt.Errorf("invalid ELECTION_COUNT '%s'. Must be a positive integer", os.Getenv("ELECTION_COUNT"))
+	}
+	rounds, err := strconv.Atoi(envutil.Get("ROUNDS", "10"))
+	if err != nil || rounds < 1 {
+		return nil, fmt.Errorf("invalid ROUNDS '%s'. Must be a positive integer", os.Getenv("ROUNDS"))
+	}
+	gracePeriod, err := strconv.ParseInt(envutil.Get("KILL_GRACE_PERIOD", "0"), 10, 64)
+	if err != nil || gracePeriod < 0 {
+		return nil, fmt.Errorf("invalid KILL_GRACE_PERIOD '%s'. Must be a non-negative number of seconds", os.Getenv("KILL_GRACE_PERIOD"))
+	}
+
+	failoverTimeout := envutil.Duration("FAILOVER_TIMEOUT", 60*time.Second)
+	recoveryTimeout := envutil.Duration("RECOVERY_TIMEOUT", 120*time.Second)
+	roundInterval := envutil.Duration("ROUND_INTERVAL", 15*time.Second)
+	logFileName := envutil.Get("LOG_FILE", "election-failover-test-results.log")
+	namespace := envutil.Get("NAMESPACE", "default")
+	controllerSelector := envutil.Get("CONTROLLER_SELECTOR", "name=prototype")
+	deviceMapName := envutil.Get("DEVICE_MAP_NAME", "device")
+	devicePrefix := envutil.Get("DEVICE_PREFIX", "failover-exp-")
+
+	logFile, err := os.OpenFile(logFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %v", err)
+	}
+
+	return &ElectionFailoverTest{
+		k8sClient:          clientset,
+		logFile:            logFile,
+		electionCount:      electionCount,
+		rounds:             rounds,
+		failoverTimeout:    failoverTimeout,
+		recoveryTimeout:    recoveryTimeout,
+		roundInterval:      roundInterval,
+		gracePeriod:        gracePeriod,
+		namespace:          namespace,
+		controllerSelector: controllerSelector,
+		deviceMapName:      deviceMapName,
+		devicePrefix:       devicePrefix,
+		elections:          make(map[string]election.Election),
+	}, nil
+}
+
+func (et *ElectionFailoverTest) logMessage(message string) {
+	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
+	logEntry := fmt.Sprintf("[%s] %s\n", timestamp, message)
+	fmt.Print(logEntry)
+	et.logFile.WriteString(logEntry)
+	et.logFile.Sync()
+}
+
+func (et *ElectionFailoverTest) deviceID(i int) string {
+	return fmt.Sprintf("%s%03d", et.devicePrefix, i)
+}
+
+// electionName is the election the controllers run for a device
+func electionName(deviceID string) string {
+	return "election-" + deviceID
+}
+
+// setupElections registers ELECTION_COUNT devices so every controller enters an election for each,
+// and opens an observer handle on each election. The observer never enters the elections itself.
+func (et *ElectionFailoverTest) setupElections(ctx context.Context) error {
+	deviceMap, err := atomix.Map[string, string](et.deviceMapName).
+		Codec(generic.Scalar[string]()).
+		Get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get device map %s: %v", et.deviceMapName, err)
+	}
+	et.deviceMap = deviceMap
+
+	for i := 0; i < et.electionCount; i++ {
+		id := et.deviceID(i)
+		if _, err := deviceMap.Put(ctx, id, "election-failover-experiment"); err != nil {
+			return fmt.Errorf("failed to register device %s: %v", id, err)
+		}
+
+		name := electionName(id)
+		e, err := atomix.LeaderElection(name).Get(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get election %s: %v", name, err)
+		}
+		et.elections[name] = e
+	}
+	et.logMessage(fmt.Sprintf("SETUP: Registered %d devices in map %s", et.electionCount, et.deviceMapName))
+	return nil
+}
+
+// removeDevices unregisters the experiment's devices so the controllers stop their elections
+func (et *ElectionFailoverTest) removeDevices(ctx context.Context) {
+	if et.deviceMap == nil {
+		return
+	}
+	for i := 0; i < et.electionCount; i++ {
+		id := et.deviceID(i)
+		if _, err := et.deviceMap.Remove(ctx, id); err != nil {
+			et.logMessage(fmt.Sprintf("CLEANUP_ERROR: Failed to remove device %s: %v", id, err))
+		}
+	}
+	for name, e := range et.elections {
+		if err := e.Close(ctx); err != nil {
+			et.logMessage(fmt.Sprintf("CLEANUP_ERROR: Failed to close election %s: %v", name, err))
+		}
+	}
+	et.logMessage("CLEANUP: Removed experiment devices")
+}
+
+// waitForLeaders polls until every election has a leader, returning the current terms
+func (et *ElectionFailoverTest) waitForLeaders(ctx context.Context, timeout time.Duration) (map[string]*election.Term, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		terms := make(map[string]*election.Term)
+		var missing []string
+		for name, e := range et.elections {
+			term, err := e.GetTerm(ctx)
+			if err != nil || term.Leader == "" {
+				missing = append(missing, name)
+				continue
+			}
+			terms[name] = term
+		}
+		if len(missing) == 0 {
+			return terms, nil
+		}
+		if time.Now().After(deadline) {
+			sort.Strings(missing)
+			return nil, fmt.Errorf("no leader after %v for: %s", timeout, strings.Join(missing, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// controllerPods lists the controller pods, keyed by name
+func (et *ElectionFailoverTest) controllerPods(ctx context.Context) (map[string]corev1.Pod, error) {
+	pods, err := et.k8sClient.CoreV1().Pods(et.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: et.controllerSelector,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list controller pods: %v", err)
+	}
+	byName := make(map[string]corev1.Pod, len(pods.Items))
+	for _, pod := range pods.Items {
+		byName[pod.Name] = pod
+	}
+	return byName, nil
+}
+
+// podForCandidate finds the controller pod behind a candidate ID. Controllers default to
+// <hostname>-<pod UID>, falling back to the bare hostname when POD_UID is unset.
+func podForCandidate(pods map[string]corev1.Pod, candidate string) (corev1.Pod, bool) {
+	for _, pod := range pods {
+		if pod.UID != "" && strings.HasSuffix(candidate, "-"+string(pod.UID)) {
+			return pod, true
+		}
+	}
+	pod, ok := pods[candidate]
+	return pod, ok
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// waitForControllers waits until want controller pods are ready, so each round starts from a full cluster
+func (et *ElectionFailoverTest) waitForControllers(ctx context.Context, want int) error {
+	deadline := time.Now().Add(et.recoveryTimeout)
+	for {
+		pods, err := et.controllerPods(ctx)
+		ready := 0
+		if err == nil {
+			for _, pod := range pods {
+				if isPodReady(pod) {
+					ready++
+				}
+			}
+			if ready >= want {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("only %d of %d controller pods ready after %v", ready, want, et.recoveryTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// pickLeader returns the candidate leading the most elections, ties broken by name
+func pickLeader(terms map[string]*election.Term) string {
+	counts := make(map[string]int)
+	for _, term := range terms {
+		counts[term.Leader]++
+	}
+	var leader string
+	for candidate, n := range counts {
+		if n > counts[leader] || (n == counts[leader] && candidate < leader) {
+			leader = candidate
+		}
+	}
+	return leader
+}
+
+func (et *ElectionFailoverTest) terminateLeaderPod(ctx context.Context, pod corev1.Pod, leader string) error {
+	et.logMessage(fmt.Sprintf("FORCED_TERMINATION: Terminating controller pod %s (candidate: %s, grace period: %ds)", pod.Name, leader, et.gracePeriod))
+
+	gracePeriod := et.gracePeriod
+	err := et.k8sClient.CoreV1().Pods(et.namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriod,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete pod %s: %v", pod.Name, err)
+	}
+
+	et.logMessage(fmt.Sprintf("TERMINATION_SUCCESS: Pod %s terminated", pod.Name))
+	return nil
+}
+
+// awaitNewLeader reads the election's Watch until it reports a term led by someone other than
+// oldLeader, returning that term
+func awaitNewLeader(ctx context.Context, stream election.TermStream, oldLeader string) (*election.Term, error) {
+	for {
+		term, err := stream.Next()
+		if err != nil {
+			return nil, err
+		}
+		if term.Leader != "" && term.Leader != oldLeader {
+			return term, nil
+		}
+	}
+}
+
+// runRound kills the controller leading the most elections and times every election it led
+func (et *ElectionFailoverTest) runRound(ctx context.Context, round int) error {
+	terms, err := et.waitForLeaders(ctx, et.failoverTimeout)
+	if err != nil {
+		return err
+	}
+
+	leader := pickLeader(terms)
+	pods, err := et.controllerPods(ctx)
+	if err != nil {
+		return err
+	}
+	pod, ok := podForCandidate(pods, leader)
+	if !ok {
+		return fmt.Errorf("no controller pod matches leader candidate %s", leader)
+	}
+
+	var led []string
+	for name, term := range terms {
+		if term.Leader == leader {
+			led = append(led, name)
+		}
+	}
+	sort.Strings(led)
+	et.logMessage(fmt.Sprintf("ROUND_START: Round %d, leader %s (pod %s) leads %d of %d elections", round, leader, pod.Name, len(led), len(terms)))
+
+	// Watches are opened before the kill so no term change can slip between the delete and the watch
+	watchCtx, cancel := context.WithTimeout(ctx, et.failoverTimeout)
+	defer cancel()
+	streams := make(map[string]election.TermStream, len(led))
+	for _, name := range led {
+		stream, err := et.elections[name].Watch(watchCtx)
+		if err != nil {
+			return fmt.Errorf("failed to watch election %s: %v", name, err)
+		}
+		streams[name] = stream
+	}
+
+	killed := time.Now()
+	if err := et.terminateLeaderPod(ctx, pod, leader); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, name := range led {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sample := FailoverSample{Round: round, Election: name, OldLeader: leader, OldTerm: terms[name].ID}
+
+			term, err := awaitNewLeader(watchCtx, streams[name], leader)
+			if err != nil {
+				sample.TimedOut = true
+				sample.Latency = time.Since(killed)
+				et.logMessage(fmt.Sprintf("FAILOVER_TIMEOUT: Round %d, %s still had no new leader after %v: %v", round, name, sample.Latency, err))
+			} else {
+				sample.Latency = time.Since(killed)
+				sample.NewLeader = term.Leader
+				sample.NewTerm = term.ID
+				et.logMessage(fmt.Sprintf("FAILOVER_OBSERVED: Round %d, %s term %d -> %d, leader %s -> %s, latency: %v",
+					round, name, sample.OldTerm, sample.NewTerm, sample.OldLeader, sample.NewLeader, sample.Latency))
+			}
+
+			mu.Lock()
+			et.samples = append(et.samples, sample)
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+
+	var roundLatencies []time.Duration
+	timeouts := 0
+	for _, s := range et.samples {
+		if s.Round != round {
+			continue
+		}
+		if s.TimedOut {
+			timeouts++
+			continue
+		}
+		roundLatencies = append(roundLatencies, s.Latency)
+	}
+	et.logMessage(fmt.Sprintf("ROUND_RESULT: Round %d, %s", round, summarize(roundLatencies, timeouts)))
+	return nil
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func summarize(latencies []time.Duration, timeouts int) string {
+	if len(latencies) == 0 {
+		return fmt.Sprintf("Samples: 0, Timeouts: %d", timeouts)
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, l := range sorted {
+		total += l
+	}
+	return fmt.Sprintf("Samples: %d, Timeouts: %d, Min: %v, Mean: %v, P50: %v, P90: %v, P99: %v, Max: %v",
+		len(sorted), timeouts, sorted[0], total/time.Duration(len(sorted)),
+		percentile(sorted, 50), percentile(sorted, 90), percentile(sorted, 99), sorted[len(sorted)-1])
+}
+
+// logDistribution reports the failover latencies over all rounds, plus a coarse histogram
+func (et *ElectionFailoverTest) logDistribution() {
+	var latencies []time.Duration
+	timeouts := 0
+	for _, s := range et.samples {
+		if s.TimedOut {
+			timeouts++
+			continue
+		}
+		latencies = append(latencies, s.Latency)
+	}
+	et.logMessage(fmt.Sprintf("FAILOVER_DISTRIBUTION: %s", summarize(latencies, timeouts)))
+
+	buckets := []time.Duration{time.Second, 2 * time.Second, 5 * time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second}
+	counts := make([]int, len(buckets)+1)
+	for _, l := range latencies {
+		i := sort.Search(len(buckets), func(i int) bool { return l <= buckets[i] })
+		counts[i]++
+	}
+	for i, n := range counts {
+		label := fmt.Sprintf("> %v", buckets[len(buckets)-1])
+		if i < len(buckets) {
+			label = fmt.Sprintf("<= %v", buckets[i])
+		}
+		et.logMessage(fmt.Sprintf("FAILOVER_HISTOGRAM: %s: %d", label, n))
+	}
+}
+
+func (et *ElectionFailoverTest) runTest(ctx context.Context) error {
+	et.logMessage("STARTING Atomix Election Failover Test")
+	et.logMessage(fmt.Sprintf("CONFIG: Elections: %d, Rounds: %d, Failover timeout: %v, Recovery timeout: %v, Round interval: %v, Kill grace period: %ds, Controllers: %s",
+		et.electionCount, et.rounds, et.failoverTimeout, et.recoveryTimeout, et.roundInterval, et.gracePeriod, et.controllerSelector))
+
+	pods, err := et.controllerPods(ctx)
+	if err != nil {
+		return err
+	}
+	controllers := 0
+	for _, pod := range pods {
+		if isPodReady(pod) {
+			controllers++
+		}
+	}
+	if controllers < 2 {
+		return fmt.Errorf("need at least 2 ready controller pods matching %s, found %d", et.controllerSelector, controllers)
+	}
+
+	if err := et.setupElections(ctx); err != nil {
+		return err
+	}
+	defer et.removeDevices(context.Background())
+
+	if _, err := et.waitForLeaders(ctx, et.failoverTimeout); err != nil {
+		return fmt.Errorf("elections did not elect leaders: %v", err)
+	}
+	et.logMessage(fmt.Sprintf("SETUP: All %d elections have a leader across %d controllers", et.electionCount, controllers))
+
+	for round := 1; round <= et.rounds; round++ {
+		if err := et.runRound(ctx, round); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			et.logMessage(fmt.Sprintf("ROUND_ERROR: Round %d: %v", round, err))
+		}
+
+		if round == et.rounds {
+			break
+		}
+		if err := et.waitForControllers(ctx, controllers); err != nil {
+			et.logMessage(fmt.Sprintf("RECOVERY_ERROR: %v", err))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(et.roundInterval):
+		}
+	}
+
+	et.logDistribution()
+	et.logMessage(fmt.Sprintf("COMPLETED: Test finished. Rounds: %d, Samples: %d", et.rounds, len(et.samples)))
+	return nil
+}
+
+func (et *ElectionFailoverTest) Close() {
+	if et.logFile != nil {
+		et.logFile.Close()
+	}
+}
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	electionTest, err := NewElectionFailoverTest()
+	if err != nil {
+		log.Fatalf("Failed to initialize election failover test: %v", err)
+	}
+	defer electionTest.Close()
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-c
+		electionTest.logMessage("INTERRUPT: Received interrupt signal, shutting down gracefully...")
+		cancel()
+	}()
+
+	if err := electionTest.runTest(ctx); err != nil {
+		electionTest.logMessage(fmt.Sprintf("FATAL: Test failed: %v", err))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,46 @@
+#!/bin/bash
+
+echo "Starting Atomix Election Failover Test Setup..."
+
+# The experiment measures the prototype controllers' elections, so they must already be deployed
+# (see prototype/deploy) with at least 2 replicas.
+if ! kubectl get deployment prototype > /dev/null 2>&1; then
+  echo "The prototype controller deployment was not found. Deploy it first."
+  exit 1
+fi
+
+echo "Building docker image..."
+docker build -t election-failover-client:local -f Dockerfile ..
+echo "Built docker image, loading into minikube..."
+minikube image load election-failover-client:local
+echo "Docker image loaded."
+
+echo "Waiting for controller pods to be ready..."
+kubectl wait --for=condition=ready pod -l name=prototype --timeout=120s
+
+echo "Applying deployment (with RBAC permissions for pod termination)..."
+kubectl apply -f deployment.yaml
+
+echo "Waiting for election-failover-experiment deployment to be ready..."
+kubectl wait --for=condition=ready pod -l name=election-failover-experiment --timeout=120s
+
+echo ""
+echo "=== Atomix Election Failover Test Environment Ready ==="
+echo ""
+echo "To monitor the test:"
+echo "  kubectl logs -f deployment/election-failover-experiment"
+echo ""
+echo "To access test logs from inside the pod:"
+echo "  kubectl exec -it deployment/election-failover-experiment -- cat /app/logs/election-failover-test-results.log"
+echo ""
+echo "Test Configuration:"
+echo "  - Elections: 5 (one per registered device)"
+echo "  - Rounds: 10 (leading controller pod killed each round)"
+echo "  - Failover Timeout: 60 seconds"
+echo "  - Kill Grace Period: 0 seconds"
+echo ""
+echo "Log lines for analysis:"
+echo "  - FAILOVER_OBSERVED / FAILOVER_TIMEOUT"
+echo "  - ROUND_RESULT"
+echo "  - FAILOVER_DISTRIBUTION / FAILOVER_HISTOGRAM"
+echo ""
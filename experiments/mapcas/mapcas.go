@@ -0,0 +1,58 @@
+// Package mapcas provides optimistic compare-and-set updates on versioned maps.
+package mapcas
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/atomix/go-sdk/pkg/primitive"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+)
+
+// ErrTooManyConflicts is returned when every attempt lost its race to another writer
+var ErrTooManyConflicts = stderrors.New("update still conflicting")
+
+// Entry is a value and the version it was read at
+type Entry struct {
+	Value   string
+	Version primitive.Version
+}
+
+// Map is a map with versioned writes. Update must fail with an Atomix Conflict error when the entry
+// is no longer at version, as an Atomix map Update with IfVersion does.
+type Map interface {
+	Get(ctx context.Context, key string) (*Entry, error)
+	Update(ctx context.Context, key, value string, version primitive.Version) error
+}
+
+// UpdateWithRetry reads key, applies fn to its value and writes the result back with a versioned
+// Update, so the write only lands if nobody changed the entry in between. On a version conflict it
+// re-reads and tries again, up to maxRetries attempts, and returns how many attempts it made.
+// Errors from reading, from fn or from a non-conflicting Update are returned as they are. The key
+// must exist; a missing one is returned as the Get error.
+func UpdateWithRetry(ctx context.Context, m Map, key string, fn func(old string) (string, error), maxRetries int) (int, error) {
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		entry, err := m.Get(ctx, key)
+		if err == nil && entry == nil {
+			err = errors.NewNotFound("key %s not found", key)
+		}
+		if err != nil {
+			return attempt, err
+		}
+
+		value, err := fn(entry.Value)
+		if err != nil {
+			return attempt, err
+		}
+
+		err = m.Update(ctx, key, value, entry.Version)
+		if err == nil {
+			return attempt, nil
+		}
+		if !errors.IsConflict(err) {
+			return attempt, err
+		}
+	}
+	return maxRetries, fmt.Errorf("%w: %s after %d attempts", ErrTooManyConflicts, key, maxRetries)
+}
@@ -0,0 +1,144 @@
+package mapcas
+
+import (
+	"context"
+	stderrors "errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/atomix/go-sdk/pkg/primitive"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+)
+
+// memMap is a versioned in-memory Map; Update conflicts like an Atomix map Update with IfVersion
+type memMap struct {
+	mu      sync.Mutex
+	entries map[string]*Entry
+	// beforeUpdate, if set, runs before every Update, outside the lock, to let a test interleave writers
+	beforeUpdate func()
+}
+
+func newMemMap() *memMap {
+	return &memMap{entries: make(map[string]*Entry)}
+}
+
+func (m *memMap) put(key, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	version := primitive.Version(1)
+	if entry, ok := m.entries[key]; ok {
+		version = entry.Version + 1
+	}
+	m.entries[key] = &Entry{Value: value, Version: version}
+}
+
+func (m *memMap) Get(_ context.Context, key string) (*Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, errors.NewNotFound("key %s not found", key)
+	}
+	copied := *entry
+	return &copied, nil
+}
+
+func (m *memMap) Update(_ context.Context, key, value string, version primitive.Version) error {
+	if m.beforeUpdate != nil {
+		m.beforeUpdate()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok {
+		return errors.NewNotFound("key %s not found", key)
+	}
+	if entry.Version != version {
+		return errors.NewConflict("key %s is at version %d, not %d", key, entry.Version, version)
+	}
+	m.entries[key] = &Entry{Value: value, Version: version + 1}
+	return nil
+}
+
+func increment(old string) (string, error) {
+	n, err := strconv.Atoi(old)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(n + 1), nil
+}
+
+// TestConcurrentUpdatersLoseNothing runs concurrent increments of one counter and checks that every
+// successful update is reflected in the final value
+func TestConcurrentUpdatersLoseNothing(t *testing.T) {
+	const updaters, updatesEach = 8, 50
+
+	m := newMemMap()
+	m.put("counter", "0")
+	// Yield between the read and the write, so updaters regularly overwrite each other's version
+	m.beforeUpdate = func() { runtime.Gosched() }
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	attempts := 0
+	for i := 0; i < updaters; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < updatesEach; j++ {
+				n, err := UpdateWithRetry(context.Background(), m, "counter", increment, 1000)
+				if err != nil {
+					t.Errorf("UpdateWithRetry: %v", err)
+					return
+				}
+				mu.Lock()
+				attempts += n
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	entry, err := m.Get(context.Background(), "counter")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if want := strconv.Itoa(updaters * updatesEach); entry.Value != want {
+		t.Errorf("counter = %s after %d updates, want %s", entry.Value, updaters*updatesEach, want)
+	}
+	t.Logf("%d updates took %d attempts", updaters*updatesEach, attempts)
+}
+
+func TestUpdateWithRetryGivesUp(t *testing.T) {
+	m := newMemMap()
+	m.put("counter", "0")
+	// Another writer always gets in between the read and the write
+	m.beforeUpdate = func() { m.put("counter", "100") }
+
+	attempts, err := UpdateWithRetry(context.Background(), m, "counter", increment, 3)
+	if !stderrors.Is(err, ErrTooManyConflicts) {
+		t.Fatalf("err = %v, want ErrTooManyConflicts", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestUpdateWithRetryErrors(t *testing.T) {
+	m := newMemMap()
+	m.put("counter", "not a number")
+
+	if _, err := UpdateWithRetry(context.Background(), m, "missing", increment, 3); !errors.IsNotFound(err) {
+		t.Errorf("missing key: err = %v, want NotFound", err)
+	}
+
+	attempts, err := UpdateWithRetry(context.Background(), m, "counter", increment, 3)
+	if err == nil || attempts != 1 {
+		t.Errorf("fn error: attempts = %d, err = %v, want 1 attempt and fn's error", attempts, err)
+	}
+	if entry, _ := m.Get(context.Background(), "counter"); entry.Value != "not a number" || entry.Version != 1 {
+		t.Errorf("failed update changed the entry: %+v", entry)
+	}
+}
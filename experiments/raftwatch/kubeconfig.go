@@ -0,0 +1,34 @@
+package raftwatch
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// KubeConfig uses the in-cluster config inside a pod and otherwise falls back to KUBECONFIG or
+// ~/.kube/config, so the experiments can also be run from a workstation against a remote cluster.
+// It returns a description of the source that was used.
+func KubeConfig() (*rest.Config, string, error) {
+	config, err := rest.InClusterConfig()
+	if err == nil {
+		return config, "in-cluster config", nil
+	}
+
+	path := os.Getenv("KUBECONFIG")
+	if path == "" {
+		home, homeErr := os.UserHomeDir()
+		if homeErr != nil {
+			return nil, "", fmt.Errorf("not running in-cluster (%v) and no KUBECONFIG set", err)
+		}
+		path = filepath.Join(home, ".kube", "config")
+	}
+	config, kubeconfigErr := clientcmd.BuildConfigFromFlags("", path)
+	if kubeconfigErr != nil {
+		return nil, "", fmt.Errorf("not running in-cluster (%v) and failed to load kubeconfig %s: %v", err, path, kubeconfigErr)
+	}
+	return config, "kubeconfig " + path, nil
+}
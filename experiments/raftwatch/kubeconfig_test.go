@@ -0,0 +1,53 @@
+package raftwatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://10.0.0.1:6443
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+current-context: test
+users:
+- name: test
+  user:
+    token: secret
+`
+
+func TestKubeConfig(t *testing.T) {
+	// Make sure the in-cluster config isn't picked up
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", path)
+
+	config, source, err := KubeConfig()
+	if err != nil {
+		t.Fatalf("KubeConfig: %v", err)
+	}
+	if config.Host != "https://10.0.0.1:6443" {
+		t.Errorf("Host = %q, want the kubeconfig's server", config.Host)
+	}
+	if want := "kubeconfig " + path; source != want {
+		t.Errorf("source = %q, want %q", source, want)
+	}
+
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	if _, _, err := KubeConfig(); err == nil {
+		t.Error("KubeConfig succeeded with a missing kubeconfig")
+	}
+}
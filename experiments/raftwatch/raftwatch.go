@@ -0,0 +1,267 @@
+// Package raftwatch discovers consensus store partition leaders from the RaftGroup resources
+// and terminates them, so the failover experiments share one view of the RaftGroup schema.
+// KubeConfig finds the cluster they run against.
+package raftwatch
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var RaftGroupGVR = schema.GroupVersionResource{
+	Group:    "consensus.atomix.io",
+	Version:  "v1beta1",
+	Resource: "raftgroups",
+}
+
+var podGVR = schema.GroupVersionResource{
+	Version:  "v1",
+	Resource: "pods",
+}
+
+// DefaultStoreName is the consensus store the experiments' manifests deploy
+const DefaultStoreName = "consensus-store"
+
+// StoreSelector is the label selector matching the RaftGroups of the named store
+func StoreSelector(storeName string) string {
+	return "atomix.io/store=" + storeName
+}
+
+// DefaultGroupPattern matches the <store>-<partition> RaftGroup names the store controller creates
+func DefaultGroupPattern(storeName string) string {
+	return `^` + regexp.QuoteMeta(storeName) + `-(\d+)$`
+}
+
+type LeaderInfo struct {
+	PartitionID int
+	GroupName   string
+	MemberName  string // leader RaftMember, <store>-<partition>-<member>; empty when the group reports no leader
+	Member      int    // 1-based member index from the leader name, -1 if it has none
+	Term        int64
+	State       string
+}
+
+type Watcher struct {
+	client     dynamic.Interface
+	namespace  string
+	storeName  string
+	minRefresh time.Duration
+	podPattern *regexp.Regexp // pod names TerminateLeader will delete
+
+	mu           sync.Mutex
+	groupPattern *regexp.Regexp // first capture group is the partition number
+	cached       map[int]LeaderInfo
+	unparseable  []string // groups in the last listing whose name groupPattern didn't match
+	lastRefresh  time.Time
+}
+
+// New returns a Watcher for the named store's RaftGroups that lists them at most once per minRefresh;
+// Leaders calls within that window reuse the previous result. A minRefresh of 0 lists on every call.
+func New(client dynamic.Interface, namespace, storeName string, minRefresh time.Duration) *Watcher {
+	return &Watcher{
+		client:     client,
+		namespace:  namespace,
+		storeName:  storeName,
+		minRefresh: minRefresh,
+		podPattern: regexp.MustCompile(`^` + regexp.QuoteMeta(storeName) + `-\d+$`),
+
+		groupPattern: regexp.MustCompile(DefaultGroupPattern(storeName)),
+	}
+}
+
+func (w *Watcher) StoreName() string {
+	return w.storeName
+}
+
+// SetGroupPattern replaces the regex that extracts the partition number from RaftGroup names.
+// Its first capture group must match the number.
+func (w *Watcher) SetGroupPattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	if re.NumSubexp() < 1 {
+		return fmt.Errorf("pattern %q has no capture group for the partition number", pattern)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.groupPattern = re
+	w.cached = nil
+	return nil
+}
+
+func (w *Watcher) GroupPattern() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.groupPattern.String()
+}
+
+// UnparseableGroups returns the RaftGroups from the last listing that were skipped because
+// the group pattern couldn't extract a partition number from their name
+func (w *Watcher) UnparseableGroups() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.unparseable...)
+}
+
+// CheckStore returns an error if no RaftGroups match the store's label selector, which otherwise
+// looks like a store that never has a leader
+func (w *Watcher) CheckStore(ctx context.Context) error {
+	raftGroups, err := w.client.Resource(RaftGroupGVR).Namespace(w.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: StoreSelector(w.storeName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list RaftGroups: %v", err)
+	}
+	if len(raftGroups.Items) == 0 {
+		return fmt.Errorf("no RaftGroups match label selector %s in namespace %s", StoreSelector(w.storeName), w.namespace)
+	}
+	return nil
+}
+
+// Leaders returns the leaders keyed by partition, from the cache if it is younger than minRefresh
+func (w *Watcher) Leaders(ctx context.Context) (map[int]LeaderInfo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cached != nil && time.Since(w.lastRefresh) < w.minRefresh {
+		return copyLeaders(w.cached), nil
+	}
+	return w.refreshLocked(ctx)
+}
+
+// Refresh lists the RaftGroups regardless of the cache, for callers that need the current leaders
+func (w *Watcher) Refresh(ctx context.Context) (map[int]LeaderInfo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.refreshLocked(ctx)
+}
+
+func copyLeaders(leaders map[int]LeaderInfo) map[int]LeaderInfo {
+	copied := make(map[int]LeaderInfo, len(leaders))
+	for partNum, leader := range leaders {
+		copied[partNum] = leader
+	}
+	return copied
+}
+
+// refreshLocked lists the store's RaftGroups and caches their leaders keyed by partition.
+// Groups whose name doesn't match the group pattern are skipped and recorded for
+// UnparseableGroups; groups without a status yet are skipped silently.
+func (w *Watcher) refreshLocked(ctx context.Context) (map[int]LeaderInfo, error) {
+	raftGroups, err := w.client.Resource(RaftGroupGVR).Namespace(w.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: StoreSelector(w.storeName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list RaftGroups: %v", err)
+	}
+
+	leaders := make(map[int]LeaderInfo)
+	var unparseable []string
+	for _, item := range raftGroups.Items {
+		groupName := item.GetName()
+		partNum, ok := w.partitionLocked(groupName)
+		if !ok {
+			unparseable = append(unparseable, groupName)
+			continue
+		}
+
+		status, found, err := unstructured.NestedMap(item.Object, "status")
+		if err != nil || !found {
+			continue
+		}
+
+		term, found, err := unstructured.NestedInt64(status, "term")
+		if err != nil || !found {
+			term = 0
+		}
+
+		state, found, err := unstructured.NestedString(status, "state")
+		if err != nil || !found {
+			state = "unknown"
+		}
+
+		info := LeaderInfo{
+			PartitionID: partNum,
+			GroupName:   groupName,
+			Member:      -1,
+			Term:        term,
+			State:       state,
+		}
+
+		leaderName, found, err := unstructured.NestedString(status, "leader", "name")
+		if err == nil && found {
+			info.MemberName = leaderName
+			// Members are named <store>-<partition>-<member>; the member index is always last
+			if i := strings.LastIndex(leaderName, "-"); i >= 0 {
+				if member, err := strconv.Atoi(leaderName[i+1:]); err == nil {
+					info.Member = member
+				}
+			}
+		}
+
+		leaders[partNum] = info
+	}
+
+	w.cached = leaders
+	w.unparseable = unparseable
+	w.lastRefresh = time.Now()
+	return copyLeaders(leaders), nil
+}
+
+func (w *Watcher) partitionLocked(groupName string) (int, bool) {
+	matches := w.groupPattern.FindStringSubmatch(groupName)
+	if matches == nil {
+		return 0, false
+	}
+	partNum, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+	return partNum, true
+}
+
+// LeaderPod returns the name of the pod running the leader. Member N of every partition runs in
+// the store's StatefulSet pod <store>-<N-1>.
+func (w *Watcher) LeaderPod(leader LeaderInfo) (string, error) {
+	if leader.MemberName == "" {
+		return "", fmt.Errorf("no leader for partition %d", leader.PartitionID)
+	}
+	if leader.Member < 1 {
+		return "", fmt.Errorf("leader %q of partition %d has no member index", leader.MemberName, leader.PartitionID)
+	}
+
+	podName := fmt.Sprintf("%s-%d", w.storeName, leader.Member-1)
+	if !w.podPattern.MatchString(podName) {
+		return "", fmt.Errorf("leader pod name %q does not match expected %s-<index> pattern", podName, w.storeName)
+	}
+	return podName, nil
+}
+
+// TerminateLeader force-deletes the leader's pod
+func (w *Watcher) TerminateLeader(ctx context.Context, leader LeaderInfo) error {
+	podName, err := w.LeaderPod(leader)
+	if err != nil {
+		return err
+	}
+
+	err = w.client.Resource(podGVR).Namespace(w.namespace).Delete(ctx, podName, metav1.DeleteOptions{
+		GracePeriodSeconds: new(int64),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete pod %s: %v", podName, err)
+	}
+	return nil
+}
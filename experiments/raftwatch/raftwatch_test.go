@@ -0,0 +1,311 @@
+package raftwatch
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "default"
+
+func newPod(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": testNamespace,
+		},
+	}}
+}
+
+// raftStatus builds a RaftGroup status; an empty leader or state leaves that field unset
+func raftStatus(leader string, term int64, state string) map[string]interface{} {
+	status := map[string]interface{}{"term": term}
+	if leader != "" {
+		status["leader"] = map[string]interface{}{"name": leader}
+	}
+	if state != "" {
+		status["state"] = state
+	}
+	return status
+}
+
+// newRaftGroup returns a RaftGroup labeled for store; a nil status leaves it unset, like a group
+// the store controller hasn't reconciled yet
+func newRaftGroup(store, name string, status map[string]interface{}) *unstructured.Unstructured {
+	group := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": RaftGroupGVR.GroupVersion().String(),
+		"kind":       "RaftGroup",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": testNamespace,
+			"labels":    map[string]interface{}{"atomix.io/store": store},
+		},
+	}}
+	if status != nil {
+		group.Object["status"] = status
+	}
+	return group
+}
+
+func newFakeClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		RaftGroupGVR: "RaftGroupList",
+		podGVR:       "PodList",
+	}, objects...)
+}
+
+func podExists(t *testing.T, client *dynamicfake.FakeDynamicClient, name string) bool {
+	t.Helper()
+	pods, err := client.Resource(podGVR).Namespace(testNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("listing pods: %v", err)
+	}
+	for _, pod := range pods.Items {
+		if pod.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// raftGroupLists counts the RaftGroup listings the client has served
+func raftGroupLists(client *dynamicfake.FakeDynamicClient) int {
+	lists := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "list" && action.GetResource() == RaftGroupGVR {
+			lists++
+		}
+	}
+	return lists
+}
+
+func TestLeaders(t *testing.T) {
+	client := newFakeClient(
+		newRaftGroup(DefaultStoreName, "consensus-store-1", raftStatus("consensus-store-1-2", 5, "Ready")),
+		newRaftGroup(DefaultStoreName, "consensus-store-2", raftStatus("", 3, "Candidate")),
+		newRaftGroup(DefaultStoreName, "consensus-store-3", raftStatus("consensus-store-3-1", 0, "")),
+		newRaftGroup(DefaultStoreName, "consensus-store-4", nil),
+		newRaftGroup(DefaultStoreName, "consensus-store-raft", raftStatus("consensus-store-raft-1", 1, "Ready")),
+		newRaftGroup("other-store", "other-store-1", raftStatus("other-store-1-1", 2, "Ready")),
+	)
+	w := New(client, testNamespace, DefaultStoreName, 0)
+
+	leaders, err := w.Leaders(context.Background())
+	if err != nil {
+		t.Fatalf("Leaders: %v", err)
+	}
+
+	want := map[int]LeaderInfo{
+		1: {PartitionID: 1, GroupName: "consensus-store-1", MemberName: "consensus-store-1-2", Member: 2, Term: 5, State: "Ready"},
+		// Leaderless partitions are still reported so callers can show them as such
+		2: {PartitionID: 2, GroupName: "consensus-store-2", Member: -1, Term: 3, State: "Candidate"},
+		3: {PartitionID: 3, GroupName: "consensus-store-3", MemberName: "consensus-store-3-1", Member: 1, State: "unknown"},
+	}
+	if !reflect.DeepEqual(leaders, want) {
+		t.Errorf("Leaders =\n%+v\nwant\n%+v", leaders, want)
+	}
+
+	if got := w.UnparseableGroups(); !reflect.DeepEqual(got, []string{"consensus-store-raft"}) {
+		t.Errorf("UnparseableGroups = %v, want [consensus-store-raft]", got)
+	}
+}
+
+func TestLeadersCache(t *testing.T) {
+	client := newFakeClient(newRaftGroup(DefaultStoreName, "consensus-store-1", raftStatus("consensus-store-1-1", 1, "Ready")))
+	ctx := context.Background()
+
+	cached := New(client, testNamespace, DefaultStoreName, time.Hour)
+	for i := 0; i < 3; i++ {
+		if _, err := cached.Leaders(ctx); err != nil {
+			t.Fatalf("Leaders: %v", err)
+		}
+	}
+	if lists := raftGroupLists(client); lists != 1 {
+		t.Errorf("%d RaftGroup listings within the refresh interval, want 1", lists)
+	}
+
+	// Callers changing the returned map must not change the cache
+	leaders, _ := cached.Leaders(ctx)
+	delete(leaders, 1)
+	if leaders, _ := cached.Leaders(ctx); len(leaders) != 1 {
+		t.Errorf("cache changed through a returned map: %+v", leaders)
+	}
+
+	if _, err := cached.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if lists := raftGroupLists(client); lists != 2 {
+		t.Errorf("Refresh didn't bypass the cache: %d listings, want 2", lists)
+	}
+
+	client.ClearActions()
+	uncached := New(client, testNamespace, DefaultStoreName, 0)
+	for i := 0; i < 3; i++ {
+		if _, err := uncached.Leaders(ctx); err != nil {
+			t.Fatalf("Leaders: %v", err)
+		}
+	}
+	if lists := raftGroupLists(client); lists != 3 {
+		t.Errorf("%d RaftGroup listings with no refresh interval, want 3", lists)
+	}
+}
+
+// TestLeadersRateLimit polls Leaders far faster than the refresh interval, as leaderMonitor and
+// waitForLeaderElection do, and checks the API is listed at most once per interval
+func TestLeadersRateLimit(t *testing.T) {
+	client := newFakeClient(newRaftGroup(DefaultStoreName, "consensus-store-1", raftStatus("consensus-store-1-1", 1, "Ready")))
+	const interval = 50 * time.Millisecond
+	w := New(client, testNamespace, DefaultStoreName, interval)
+
+	start := time.Now()
+	for time.Since(start) < 10*interval {
+		if _, err := w.Leaders(context.Background()); err != nil {
+			t.Fatalf("Leaders: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// One listing per elapsed interval, plus the first
+	maxLists := int(time.Since(start)/interval) + 1
+	if lists := raftGroupLists(client); lists < 2 || lists > maxLists {
+		t.Errorf("%d RaftGroup listings over %v with a %v refresh interval, want between 2 and %d", lists, time.Since(start).Round(time.Millisecond), interval, maxLists)
+	}
+}
+
+func TestSetGroupPattern(t *testing.T) {
+	client := newFakeClient(
+		newRaftGroup(DefaultStoreName, "raft-group-7", raftStatus("consensus-store-7-3", 2, "Ready")),
+		newRaftGroup(DefaultStoreName, "consensus-store-1", raftStatus("consensus-store-1-1", 1, "Ready")),
+	)
+	w := New(client, testNamespace, DefaultStoreName, time.Hour)
+	ctx := context.Background()
+
+	// Prime the cache with the default pattern; setting a new one has to invalidate it
+	if leaders, err := w.Leaders(ctx); err != nil || len(leaders) != 1 {
+		t.Fatalf("Leaders with the default pattern = %+v, %v", leaders, err)
+	}
+
+	for _, pattern := range []string{`^raft-group-(\d+`, `^raft-group-\d+$`} {
+		if err := w.SetGroupPattern(pattern); err == nil {
+			t.Errorf("SetGroupPattern(%q) succeeded, want an error", pattern)
+		}
+	}
+	if got, want := w.GroupPattern(), DefaultGroupPattern(DefaultStoreName); got != want {
+		t.Errorf("rejected patterns replaced the group pattern: %q, want %q", got, want)
+	}
+
+	if err := w.SetGroupPattern(`^raft-group-(\d+)$`); err != nil {
+		t.Fatalf("SetGroupPattern: %v", err)
+	}
+	leaders, err := w.Leaders(ctx)
+	if err != nil {
+		t.Fatalf("Leaders: %v", err)
+	}
+	if leader, ok := leaders[7]; len(leaders) != 1 || !ok || leader.Member != 3 {
+		t.Errorf("Leaders with a custom pattern = %+v, want only partition 7 led by member 3", leaders)
+	}
+	if got := w.UnparseableGroups(); !reflect.DeepEqual(got, []string{"consensus-store-1"}) {
+		t.Errorf("UnparseableGroups = %v, want [consensus-store-1]", got)
+	}
+}
+
+func TestCheckStore(t *testing.T) {
+	client := newFakeClient(newRaftGroup("other-store", "other-store-1", raftStatus("other-store-1-1", 1, "Ready")))
+	ctx := context.Background()
+
+	if err := New(client, testNamespace, DefaultStoreName, 0).CheckStore(ctx); err == nil {
+		t.Error("CheckStore succeeded for a store with no RaftGroups")
+	}
+	if err := New(client, "elsewhere", "other-store", 0).CheckStore(ctx); err == nil {
+		t.Error("CheckStore succeeded for a store with no RaftGroups in the namespace")
+	}
+	if err := New(client, testNamespace, "other-store", 0).CheckStore(ctx); err != nil {
+		t.Errorf("CheckStore: %v", err)
+	}
+
+	client.PrependReactor("list", "raftgroups", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, context.DeadlineExceeded
+	})
+	if err := New(client, testNamespace, "other-store", 0).CheckStore(ctx); err == nil {
+		t.Error("CheckStore succeeded when listing RaftGroups failed")
+	}
+}
+
+// TestTerminateLeader checks that the leader's RaftMember name is mapped to the pod running it,
+// and that neighbouring pods are left alone
+func TestTerminateLeader(t *testing.T) {
+	pods := []string{"consensus-store-0", "consensus-store-1", "consensus-store-2"}
+	tests := []struct {
+		name       string
+		leaderName string
+		wantPod    string // deleted pod; empty when TerminateLeader must fail
+	}{
+		{name: "member one runs in pod zero", leaderName: "consensus-store-1-1", wantPod: "consensus-store-0"},
+		{name: "last member", leaderName: "consensus-store-1-3", wantPod: "consensus-store-2"},
+		{name: "extra name segments", leaderName: "consensus-store-raft-0-2", wantPod: "consensus-store-1"},
+		{name: "no leader"},
+		{name: "no member index", leaderName: "consensus-store-leader"},
+		{name: "member zero", leaderName: "consensus-store-1-0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeClient(newRaftGroup(DefaultStoreName, "consensus-store-1", raftStatus(tt.leaderName, 3, "Ready")),
+				newPod(pods[0]), newPod(pods[1]), newPod(pods[2]))
+			w := New(client, testNamespace, DefaultStoreName, 0)
+
+			leaders, err := w.Leaders(context.Background())
+			if err != nil {
+				t.Fatalf("Leaders: %v", err)
+			}
+			leader, ok := leaders[1]
+			if !ok {
+				t.Fatalf("Leaders returned no partition 1: %+v", leaders)
+			}
+
+			err = w.TerminateLeader(context.Background(), leader)
+			if tt.wantPod == "" {
+				if err == nil {
+					t.Fatalf("TerminateLeader(%+v) succeeded, want an error", leader)
+				}
+				for _, pod := range pods {
+					if !podExists(t, client, pod) {
+						t.Errorf("pod %s was deleted", pod)
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("TerminateLeader(%+v): %v", leader, err)
+			}
+			if pod, _ := w.LeaderPod(leader); pod != tt.wantPod {
+				t.Errorf("LeaderPod = %q, want %q", pod, tt.wantPod)
+			}
+			for _, pod := range pods {
+				if exists := podExists(t, client, pod); exists == (pod == tt.wantPod) {
+					t.Errorf("pod %s exists = %t after terminating %s", pod, exists, tt.wantPod)
+				}
+			}
+		})
+	}
+}
+
+func TestTerminateLeaderMissingPod(t *testing.T) {
+	w := New(newFakeClient(), testNamespace, DefaultStoreName, 0)
+
+	err := w.TerminateLeader(context.Background(), LeaderInfo{PartitionID: 1, MemberName: "consensus-store-1-1", Member: 1})
+	if err == nil {
+		t.Fatal("TerminateLeader succeeded without a pod to delete")
+	}
+}
@@ -0,0 +1,3 @@
+module example.com/resultsink
+
+go 1.24.6
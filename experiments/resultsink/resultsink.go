@@ -0,0 +1,209 @@
+// Package resultsink sends an experiment's results to the destination named by RESULT_SINK: a log
+// file (with an optional statistics CSV) or stdout as JSON lines for a log collector to stream.
+package resultsink
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Event is one "EVENT_TYPE: details" message emitted by an experiment. Fields carry the details as
+// values for the jsonl format; the text format prints only the message, so it must still hold
+// everything the text analyzers parse.
+type Event struct {
+	Timestamp time.Time
+	Message   string
+	Fields    map[string]any
+}
+
+// Operation is a single Atomix operation, one row of the statistics CSV
+type Operation struct {
+	TestType  string
+	ClientID  string
+	Key       string
+	Operation string
+	Value     string
+	Timestamp time.Time
+	Success   bool
+	Duration  time.Duration
+	Details   string
+}
+
+// Sink is where an experiment's results go. Sinks must be safe for concurrent use.
+// Another destination (an HTTP collector, Kafka) only needs to implement this and be added to New.
+type Sink interface {
+	Emit(event Event) error
+	Record(op Operation) error
+	Close() error
+}
+
+// Options configures the file sink
+type Options struct {
+	LogFile   string
+	LogFormat string // "text" or "jsonl"
+	// StatisticsFile is the CSV operations are written to; if empty, the file sink drops them
+	StatisticsFile string
+}
+
+// New selects the sink named by RESULT_SINK
+func New(kind string, opts Options) (Sink, error) {
+	switch kind {
+	case "file":
+		return newFileSink(opts)
+	case "stdout-jsonl":
+		return &stdoutJSONLSink{out: os.Stdout}, nil
+	}
+	return nil, fmt.Errorf("invalid RESULT_SINK '%s'. Valid options: 'file', 'stdout-jsonl'", kind)
+}
+
+// fileSink echoes events to stdout and appends them to the log file, as text or jsonl per the log
+// format, and writes operations to the statistics CSV
+type fileSink struct {
+	out       io.Writer
+	logFile   *os.File
+	logFormat string
+	csvFile   *os.File
+	csvWriter *csv.Writer
+	csvMux    sync.Mutex
+}
+
+func newFileSink(opts Options) (*fileSink, error) {
+	logFile, err := os.OpenFile(opts.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %v", err)
+	}
+	s := &fileSink{out: os.Stdout, logFile: logFile, logFormat: opts.LogFormat}
+	if opts.StatisticsFile == "" {
+		return s, nil
+	}
+
+	csvFile, err := os.Create(opts.StatisticsFile)
+	if err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to create CSV file: %v", err)
+	}
+
+	s.csvFile = csvFile
+	s.csvWriter = csv.NewWriter(csvFile)
+	// Write CSV header
+	s.csvWriter.Write([]string{"TestType", "ClientID", "Key", "Operation", "Value", "Timestamp", "Success", "Duration", "Details"})
+	s.csvWriter.Flush()
+	return s, nil
+}
+
+func (s *fileSink) Emit(event Event) error {
+	var logEntry string
+	if s.logFormat == "jsonl" {
+		logEntry = jsonlEntry(event)
+	} else {
+		logEntry = fmt.Sprintf("[%s] %s\n", event.Timestamp.Format("2006-01-02 15:04:05.000"), event.Message)
+	}
+	fmt.Fprint(s.out, logEntry)
+	if _, err := s.logFile.WriteString(logEntry); err != nil {
+		return err
+	}
+	return s.logFile.Sync()
+}
+
+func (s *fileSink) Record(op Operation) error {
+	if s.csvWriter == nil {
+		return nil
+	}
+	s.csvMux.Lock()
+	defer s.csvMux.Unlock()
+	s.csvWriter.Write([]string{
+		op.TestType,
+		op.ClientID,
+		op.Key,
+		op.Operation,
+		op.Value,
+		op.Timestamp.Format("2006-01-02 15:04:05.000"),
+		strconv.FormatBool(op.Success),
+		op.Duration.String(),
+		op.Details,
+	})
+	s.csvWriter.Flush()
+	return s.csvWriter.Error()
+}
+
+func (s *fileSink) Close() error {
+	if s.csvWriter != nil {
+		s.csvMux.Lock()
+		s.csvWriter.Flush()
+		s.csvMux.Unlock()
+		s.csvFile.Close()
+	}
+	return s.logFile.Close()
+}
+
+// stdoutJSONLSink writes events and operations to stdout only, as one JSON object per line,
+// for a log collector to stream. Operations have event_type OPERATION.
+type stdoutJSONLSink struct {
+	out io.Writer
+	mu  sync.Mutex // keeps concurrent lines from interleaving
+}
+
+func (s *stdoutJSONLSink) Emit(event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprint(s.out, jsonlEntry(event))
+	return err
+}
+
+func (s *stdoutJSONLSink) Record(op Operation) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"timestamp":   op.Timestamp.Format(time.RFC3339Nano),
+		"event_type":  "OPERATION",
+		"test_type":   op.TestType,
+		"client_id":   op.ClientID,
+		"key":         op.Key,
+		"operation":   op.Operation,
+		"value":       op.Value,
+		"success":     op.Success,
+		"duration_ms": float64(op.Duration.Microseconds()) / 1000,
+		"details":     op.Details,
+	})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintln(s.out, string(data))
+	return err
+}
+
+func (s *stdoutJSONLSink) Close() error {
+	return nil
+}
+
+// eventTypeRegex splits "EVENT_TYPE: details" log messages for the jsonl format
+var eventTypeRegex = regexp.MustCompile(`(?s)^([A-Z][A-Z0-9_]*): (.*)$`)
+
+// jsonlEntry renders the event as a JSON object with the event type split out and its fields
+// alongside
+func jsonlEntry(event Event) string {
+	eventType, details := "MESSAGE", event.Message
+	if matches := eventTypeRegex.FindStringSubmatch(event.Message); matches != nil {
+		eventType, details = matches[1], matches[2]
+	}
+	entry := make(map[string]any, len(event.Fields)+3)
+	for name, value := range event.Fields {
+		entry[name] = value
+	}
+	entry["timestamp"] = event.Timestamp.Format(time.RFC3339Nano)
+	entry["event_type"] = eventType
+	entry["message"] = details
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"event_type": "LOG_ERROR", "message": err.Error()})
+	}
+	return string(data) + "\n"
+}
@@ -0,0 +1,174 @@
+package resultsink
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testTime = time.Date(2026, 3, 1, 12, 30, 45, 123000000, time.UTC)
+
+func decodeLine(t *testing.T, line string) map[string]interface{} {
+	t.Helper()
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("line %q isn't JSON: %v", line, err)
+	}
+	return entry
+}
+
+func TestJSONLEntry(t *testing.T) {
+	tests := []struct {
+		message     string
+		wantType    string
+		wantMessage string
+	}{
+		{message: "WRITE_SUCCESS: key-1 written", wantType: "WRITE_SUCCESS", wantMessage: "key-1 written"},
+		{message: "PHASE2: multi\nline", wantType: "PHASE2", wantMessage: "multi\nline"},
+		{message: "no event type here", wantType: "MESSAGE", wantMessage: "no event type here"},
+		{message: "lower_case: not an event type", wantType: "MESSAGE", wantMessage: "lower_case: not an event type"},
+	}
+
+	for _, tt := range tests {
+		line := jsonlEntry(Event{Timestamp: testTime, Message: tt.message})
+		if !strings.HasSuffix(line, "\n") || strings.Count(line, "\n") != 1 {
+			t.Errorf("jsonlEntry(%q) = %q, want exactly one line", tt.message, line)
+		}
+		entry := decodeLine(t, line)
+		if entry["event_type"] != tt.wantType || entry["message"] != tt.wantMessage {
+			t.Errorf("jsonlEntry(%q) = %v, want event_type %q and message %q", tt.message, entry, tt.wantType, tt.wantMessage)
+		}
+		if entry["timestamp"] != testTime.Format(time.RFC3339Nano) {
+			t.Errorf("timestamp = %v", entry["timestamp"])
+		}
+	}
+}
+
+func TestJSONLEntryFields(t *testing.T) {
+	entry := decodeLine(t, jsonlEntry(Event{
+		Timestamp: testTime,
+		Message:   "LEADER_RECOVERY: test-000001 (duration: 1.5s)",
+		Fields: map[string]any{
+			"test_id":     "test-000001",
+			"duration_ns": int64(1500 * time.Millisecond),
+			// Fields can't replace the entry's own keys
+			"event_type": "OVERRIDDEN",
+		},
+	}))
+
+	if entry["test_id"] != "test-000001" || entry["duration_ns"] != float64(1500*time.Millisecond) {
+		t.Errorf("fields missing from %v", entry)
+	}
+	if entry["event_type"] != "LEADER_RECOVERY" || entry["message"] != "test-000001 (duration: 1.5s)" {
+		t.Errorf("fields replaced the event type or message: %v", entry)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	for _, format := range []string{"text", "jsonl"} {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			opts := Options{
+				LogFile:        filepath.Join(dir, "results.log"),
+				LogFormat:      format,
+				StatisticsFile: filepath.Join(dir, "stats.csv"),
+			}
+			sink, err := newFileSink(opts)
+			if err != nil {
+				t.Fatalf("newFileSink: %v", err)
+			}
+			var echoed bytes.Buffer
+			sink.out = &echoed
+
+			if err := sink.Emit(Event{Timestamp: testTime, Message: "TEST_START: go"}); err != nil {
+				t.Fatalf("Emit: %v", err)
+			}
+			op := Operation{TestType: "NoLostUpdates", ClientID: "client-1", Key: "k", Operation: "put", Value: "v",
+				Timestamp: testTime, Success: true, Duration: 1500 * time.Microsecond, Details: "Attempts: 1"}
+			if err := sink.Record(op); err != nil {
+				t.Fatalf("Record: %v", err)
+			}
+			if err := sink.Close(); err != nil {
+				t.Fatalf("Close: %v", err)
+			}
+
+			logged, err := os.ReadFile(opts.LogFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(logged) != echoed.String() {
+				t.Errorf("log file %q differs from the stdout echo %q", logged, echoed.String())
+			}
+			if format == "text" {
+				if want := "[2026-03-01 12:30:45.123] TEST_START: go\n"; string(logged) != want {
+					t.Errorf("log file = %q, want %q", logged, want)
+				}
+			} else if entry := decodeLine(t, string(logged)); entry["event_type"] != "TEST_START" {
+				t.Errorf("jsonl log entry = %v", entry)
+			}
+
+			f, err := os.Open(opts.StatisticsFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			rows, err := csv.NewReader(f).ReadAll()
+			if err != nil {
+				t.Fatalf("reading CSV: %v", err)
+			}
+			want := []string{"NoLostUpdates", "client-1", "k", "put", "v", "2026-03-01 12:30:45.123", "true", "1.5ms", "Attempts: 1"}
+			if len(rows) != 2 || rows[0][0] != "TestType" || strings.Join(rows[1], ",") != strings.Join(want, ",") {
+				t.Errorf("CSV rows = %v, want the header and %v", rows, want)
+			}
+		})
+	}
+}
+
+func TestFileSinkWithoutStatistics(t *testing.T) {
+	sink, err := New("file", Options{LogFile: filepath.Join(t.TempDir(), "results.log")})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	sink.(*fileSink).out = &bytes.Buffer{}
+	if err := sink.Record(Operation{ClientID: "client-1"}); err != nil {
+		t.Errorf("Record without a statistics file: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestStdoutJSONLSink(t *testing.T) {
+	var out bytes.Buffer
+	sink := &stdoutJSONLSink{out: &out}
+
+	if err := sink.Emit(Event{Timestamp: testTime, Message: "TEST_START: go"}); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+	if err := sink.Record(Operation{TestType: "Linearizability", ClientID: "client-1", Timestamp: testTime, Success: true, Duration: 2 * time.Millisecond}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), out.String())
+	}
+	if entry := decodeLine(t, lines[0]); entry["event_type"] != "TEST_START" || entry["message"] != "go" {
+		t.Errorf("event line = %v", entry)
+	}
+	op := decodeLine(t, lines[1])
+	if op["event_type"] != "OPERATION" || op["client_id"] != "client-1" || op["success"] != true || op["duration_ms"] != 2.0 {
+		t.Errorf("operation line = %v", op)
+	}
+}
+
+func TestNewRejectsUnknownSink(t *testing.T) {
+	if _, err := New("kafka", Options{}); err == nil {
+		t.Error("New(kafka) succeeded, want an error")
+	}
+}
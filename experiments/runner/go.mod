@@ -0,0 +1,7 @@
+module example.com/runner
+
+go 1.24.6
+
+require sigs.k8s.io/yaml v1.2.0
+
+require gopkg.in/yaml.v2 v2.4.0 // indirect
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+type Runner struct {
+	scenario     *Scenario
+	root         string // experiments directory containing each experiment's deployment.yaml
+	pollInterval time.Duration
+}
+
+func (r *Runner) Run(ctx context.Context) error {
+	log.Printf("SCENARIO_START: %s (%d phases, output: %s)", r.scenario.Name, len(r.scenario.Phases), r.scenario.Output)
+	for i, phase := range r.scenario.Phases {
+		if phase.Delay > 0 {
+			log.Printf("PHASE_DELAY: Waiting %v before phase '%s'", time.Duration(phase.Delay), phase.Name)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(phase.Delay)):
+			}
+		}
+
+		log.Printf("PHASE_START: %d/%d '%s' (%s)", i+1, len(r.scenario.Phases), phase.Name, phase.Experiment)
+		start := time.Now()
+		if err := r.runPhase(ctx, phase); err != nil {
+			log.Printf("PHASE_FAILED: '%s' after %v: %v", phase.Name, time.Since(start).Round(time.Second), err)
+			return fmt.Errorf("phase '%s': %w", phase.Name, err)
+		}
+		log.Printf("PHASE_COMPLETE: '%s' (duration: %v)", phase.Name, time.Since(start).Round(time.Second))
+	}
+	log.Printf("SCENARIO_COMPLETE: %s", r.scenario.Name)
+	return nil
+}
+
+func (r *Runner) runPhase(ctx context.Context, phase Phase) error {
+	exp := experiments[phase.Experiment]
+	outDir := filepath.Join(r.scenario.Output, phase.Name)
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		return err
+	}
+	if err := writePhaseParams(outDir, phase); err != nil {
+		return err
+	}
+
+	manifest, err := r.renderManifest(exp, phase.Params)
+	if err != nil {
+		return err
+	}
+	if _, err := r.kubectlInput(ctx, manifest, "apply", "-f", "-"); err != nil {
+		return fmt.Errorf("failed to apply %s deployment: %w", phase.Experiment, err)
+	}
+	// The deployment is removed after every phase so the next one starts from a fresh pod and log volume
+	defer func() {
+		deleteCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		if _, err := r.kubectl(deleteCtx, "delete", "deployment", exp.deployment, "--wait=true"); err != nil {
+			log.Printf("CLEANUP_FAILED: Failed to delete deployment %s: %v", exp.deployment, err)
+		}
+	}()
+
+	if _, err := r.kubectl(ctx, "rollout", "status", "deployment/"+exp.deployment, "--timeout=5m"); err != nil {
+		return fmt.Errorf("deployment %s did not become ready: %w", exp.deployment, err)
+	}
+
+	timeout := time.Duration(phase.Timeout)
+	if timeout == 0 {
+		timeout = defaultPhaseTimeout
+	}
+	waitErr := r.waitForCompletion(ctx, exp, timeout)
+
+	// Copy whatever results exist even if the experiment failed; they are what explains the failure
+	if err := r.collectResults(ctx, exp, outDir); err != nil {
+		log.Printf("COLLECT_FAILED: '%s': %v", phase.Name, err)
+		if waitErr == nil {
+			return err
+		}
+	}
+	return waitErr
+}
+
+// renderManifest applies the phase's params to the experiment's deployment.yaml. Params replace the
+// value of a matching env var on the experiment container, or are appended if it isn't set there.
+func (r *Runner) renderManifest(exp experiment, params map[string]string) ([]byte, error) {
+	manifestPath := filepath.Join(r.root, exp.dir, "deployment.yaml")
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	for _, doc := range strings.Split(string(data), "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("%s: %v", manifestPath, err)
+		}
+		if obj["kind"] == "Deployment" {
+			if err := setContainerEnv(obj, params); err != nil {
+				return nil, fmt.Errorf("%s: %v", manifestPath, err)
+			}
+		}
+		rendered, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+		out.WriteString("---\n")
+		out.Write(rendered)
+	}
+	return out.Bytes(), nil
+}
+
+func setContainerEnv(deployment map[string]interface{}, params map[string]string) error {
+	spec, _ := deployment["spec"].(map[string]interface{})
+	template, _ := spec["template"].(map[string]interface{})
+	podSpec, _ := template["spec"].(map[string]interface{})
+	containers, _ := podSpec["containers"].([]interface{})
+	if len(containers) == 0 {
+		return fmt.Errorf("deployment has no containers")
+	}
+	container, ok := containers[0].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("deployment container is malformed")
+	}
+
+	env, _ := container["env"].([]interface{})
+	remaining := make(map[string]string, len(params))
+	for name, value := range params {
+		remaining[name] = value
+	}
+	for _, e := range env {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := entry["name"].(string)
+		if value, ok := remaining[name]; ok {
+			entry["value"] = value
+			delete(entry, "valueFrom")
+			delete(remaining, name)
+		}
+	}
+
+	names := make([]string, 0, len(remaining))
+	for name := range remaining {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		env = append(env, map[string]interface{}{"name": name, "value": remaining[name]})
+	}
+	container["env"] = env
+	return nil
+}
+
+// waitForCompletion polls the experiment's logs until it reports completion or a fatal error. The
+// previous container's logs are checked too, since an experiment that exits is restarted by its deployment.
+func (r *Runner) waitForCompletion(ctx context.Context, exp experiment, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		for _, previous := range []bool{false, true} {
+			args := []string{"logs", "deployment/" + exp.deployment}
+			if previous {
+				args = append(args, "--previous")
+			}
+			logs, err := r.kubectl(ctx, args...)
+			if err != nil {
+				// No previous container, or the pod is between restarts
+				continue
+			}
+			for _, line := range strings.Split(logs, "\n") {
+				if hasMarker(line, exp.failMarker) {
+					return fmt.Errorf("experiment reported failure: %s", strings.TrimSpace(line))
+				}
+				if hasMarker(line, exp.doneMarker) {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("experiment did not finish within %v", timeout)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// hasMarker reports whether a log line is a marker event, in either the text format
+// ("MARKER: details") or the jsonl format ({"event_type":"MARKER",...}) selected by LOG_FORMAT
+func hasMarker(line, marker string) bool {
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		var entry struct {
+			EventType string `json:"event_type"`
+		}
+		return json.Unmarshal([]byte(line), &entry) == nil && entry.EventType == marker
+	}
+	return strings.Contains(line, marker+":")
+}
+
+func (r *Runner) collectResults(ctx context.Context, exp experiment, outDir string) error {
+	pod, err := r.kubectl(ctx, "get", "pods", "-l", "name="+exp.deployment, "-o", "jsonpath={.items[0].metadata.name}")
+	if err != nil {
+		return fmt.Errorf("failed to find %s pod: %w", exp.deployment, err)
+	}
+	pod = strings.TrimSpace(pod)
+
+	for _, file := range exp.resultFiles {
+		dest := filepath.Join(outDir, path.Base(file))
+		if _, err := r.kubectl(ctx, "cp", pod+":"+file, dest); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", file, err)
+		}
+		log.Printf("RESULT_COLLECTED: %s -> %s", file, dest)
+	}
+	return nil
+}
+
+// writePhaseParams records the phase definition next to its results so a run can be reproduced
+func writePhaseParams(outDir string, phase Phase) error {
+	data, err := json.MarshalIndent(phase, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(outDir, "phase.json"), data, 0644)
+}
+
+func (r *Runner) kubectl(ctx context.Context, args ...string) (string, error) {
+	return r.kubectlInput(ctx, nil, args...)
+}
+
+func (r *Runner) kubectlInput(ctx context.Context, input []byte, args ...string) (string, error) {
+	args = append([]string{"-n", r.scenario.Namespace}, args...)
+	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	if input != nil {
+		cmd.Stdin = bytes.NewReader(input)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("kubectl %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func main() {
+	root := flag.String("root", "..", "experiments directory containing experiment-N/deployment.yaml")
+	pollInterval := flag.Duration("poll-interval", 10*time.Second, "how often to check experiment logs for completion")
+	validateOnly := flag.Bool("validate", false, "validate the scenario file and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <scenario.yaml|scenario.json>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	scenario, err := LoadScenario(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("Invalid scenario: %v", err)
+	}
+	if scenario.Namespace == "" {
+		scenario.Namespace = "default"
+	}
+	if *validateOnly {
+		fmt.Printf("Scenario '%s' is valid (%d phases)\n", scenario.Name, len(scenario.Phases))
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	runner := &Runner{scenario: scenario, root: *root, pollInterval: *pollInterval}
+	if err := runner.Run(ctx); err != nil {
+		log.Fatalf("SCENARIO_FAILED: %v", err)
+	}
+}
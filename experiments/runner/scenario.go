@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Scenario is a campaign of experiment runs, executed phase by phase in order
+type Scenario struct {
+	Name      string  `json:"name"`
+	Output    string  `json:"output"`              // directory results are copied into, one subdirectory per phase
+	Namespace string  `json:"namespace,omitempty"` // defaults to "default"
+	Phases    []Phase `json:"phases"`
+}
+
+type Phase struct {
+	Name       string   `json:"name"`
+	Experiment string   `json:"experiment"`        // experiment-3, experiment-4 or experiment-5
+	Params     Params   `json:"params,omitempty"`  // environment variables for the experiment, e.g. WRITE_INTERVAL
+	Delay      Duration `json:"delay,omitempty"`   // wait before starting the phase
+	Timeout    Duration `json:"timeout,omitempty"` // how long to wait for the experiment to finish
+}
+
+// Params holds environment variable values; numbers and booleans are accepted so YAML needn't quote them
+type Params map[string]string
+
+func (p *Params) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*p = make(Params, len(raw))
+	for name, value := range raw {
+		switch v := value.(type) {
+		case string:
+			(*p)[name] = v
+		case float64, bool:
+			(*p)[name] = fmt.Sprint(v)
+		default:
+			return fmt.Errorf("param '%s' must be a string, number or boolean", name)
+		}
+	}
+	return nil
+}
+
+const defaultPhaseTimeout = 30 * time.Minute
+
+// Duration accepts Go duration strings ("500ms", "10m") in scenario files
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"30s\": %s", string(b))
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration '%s': %v", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// experiment describes how an experiment is deployed and how to tell when it has finished
+type experiment struct {
+	dir         string
+	deployment  string
+	doneMarker  string   // event type of the log line reporting completion
+	failMarker  string   // event type of the log line reporting a fatal error
+	resultFiles []string // paths inside the pod copied to the phase's output directory
+	params      []string // environment variables a scenario may set
+}
+
+var experiments = map[string]experiment{
+	"experiment-3": {
+		dir:         "experiment-3",
+		deployment:  "atomix-experiment",
+		doneMarker:  "COMPLETED",
+		failMarker:  "FATAL",
+		resultFiles: []string{"/app/logs/failover-test-results.log"},
+		params: []string{"WRITE_INTERVAL", "READ_INTERVAL", "TEST_DURATION", "AUTO_FAILOVER_INTERVAL", "LEADER_REFRESH_INTERVAL",
+			"WRITE_CONCURRENCY", "WRITE_JITTER", "TEST_PRIMITIVE", "VALUE_TYPE", "MAP_NAME", "LOG_FORMAT",
+			"TEST_MODE", "BENCHMARK_CLIENTS", "BENCHMARK_KEYS", "BENCHMARK_READ_RATIO", "VALUE_SIZE", "STORE_NAME", "GROUP_NAME_PATTERN", "SLOW_WRITE_THRESHOLD",
+			"MAP_SIZE_INTERVAL", "MAX_KEYS", "MAX_MAP_ENTRIES"},
+	},
+	"experiment-4": {
+		dir:         "experiment-4",
+		deployment:  "enhanced-failover-experiment",
+		doneMarker:  "EXPERIMENT_COMPLETE",
+		failMarker:  "FATAL",
+		resultFiles: []string{"/app/logs/enhanced-failover-test-results.log"},
+		params: []string{"TEST_MODE", "PARTITION_COUNT", "LEADER_REFRESH_INTERVAL", "STABILIZATION_WINDOW", "STABILIZATION_SAMPLES",
+			"MAP_NAME", "LOG_FORMAT", "VALUE_SIZE", "MULTI_PARTITION_TARGETS", "MULTI_PARTITION_ROUNDS", "MULTI_PARTITION_KEYS", "STORE_NAME", "GROUP_NAME_PATTERN",
+			"VERIFICATION_READS", "VERIFICATION_QUORUM", "VERIFICATION_READ_GAP"},
+	},
+	"experiment-5": {
+		dir:         "experiment-5",
+		deployment:  "atomix-concurrency-experiment",
+		doneMarker:  "SUMMARY_FILE_CREATED",
+		failMarker:  "FATAL",
+		resultFiles: []string{"/app/logs/concurrency-test-results.log", "/app/logs/concurrency-test-results.csv"},
+		params: []string{"CONCURRENT_CLIENTS", "OPERATIONS_PER_CLIENT", "CONTENTION_KEYS", "TEST_DURATION", "VALUE_TYPE",
+			"MAP_NAME", "LOG_FORMAT", "VALUE_SIZE", "WRITE_ATTEMPTS",
+			"OP_INTERVAL", "LINEARIZABILITY_OP_INTERVAL", "DURABILITY_OP_INTERVAL"},
+	},
+}
+
+// LoadScenario reads a YAML or JSON scenario file, rejecting unknown fields
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// YAML is a superset of JSON, but converting keeps the strict decoding below in one place
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return nil, fmt.Errorf("%s: invalid YAML: %v", path, err)
+		}
+	}
+
+	var scenario Scenario
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&scenario); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &scenario, nil
+}
+
+func (s *Scenario) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if s.Output == "" {
+		return fmt.Errorf("output is required")
+	}
+	if len(s.Phases) == 0 {
+		return fmt.Errorf("at least one phase is required")
+	}
+
+	seen := make(map[string]bool)
+	for i, phase := range s.Phases {
+		if phase.Name == "" {
+			return fmt.Errorf("phase %d: name is required", i+1)
+		}
+		if seen[phase.Name] {
+			return fmt.Errorf("phase %d: duplicate name '%s'", i+1, phase.Name)
+		}
+		seen[phase.Name] = true
+
+		exp, ok := experiments[phase.Experiment]
+		if !ok {
+			return fmt.Errorf("phase '%s': unknown experiment '%s'. Valid options: %s", phase.Name, phase.Experiment, strings.Join(experimentNames(), ", "))
+		}
+		for param := range phase.Params {
+			if !contains(exp.params, param) {
+				return fmt.Errorf("phase '%s': unknown parameter '%s' for %s. Valid options: %s", phase.Name, param, phase.Experiment, strings.Join(exp.params, ", "))
+			}
+		}
+		if phase.Delay < 0 || phase.Timeout < 0 {
+			return fmt.Errorf("phase '%s': delay and timeout must not be negative", phase.Name)
+		}
+	}
+	return nil
+}
+
+func experimentNames() []string {
+	names := make([]string, 0, len(experiments))
+	for name := range experiments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
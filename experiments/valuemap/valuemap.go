@@ -0,0 +1,164 @@
+// Package valuemap opens the experiments' test map with the codec picked by VALUE_TYPE, and
+// classifies the errors its operations return.
+package valuemap
+
+import (
+	"context"
+	"encoding/binary"
+	stderrors "errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"example.com/mapcas"
+	"github.com/atomix/go-sdk/pkg/atomix"
+	"github.com/atomix/go-sdk/pkg/generic"
+	"github.com/atomix/go-sdk/pkg/primitive"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+)
+
+// Map is the test map as seen through VALUE_TYPE. Tests keep working with string values, which
+// are converted to the configured type before they reach the codec, so the same test logic exercises
+// the string, int64 and float64 codecs.
+type Map interface {
+	Put(ctx context.Context, key, value string) error
+	Get(ctx context.Context, key string) (*Entry, error)
+	Update(ctx context.Context, key, value string, version primitive.Version) error
+	Len(ctx context.Context) (int, error)
+	Remove(ctx context.Context, key string) error
+}
+
+// Entry is shared with mapcas, so a Map can be updated with mapcas.UpdateWithRetry
+type Entry = mapcas.Entry
+
+type typedMap[V any] struct {
+	m      _map.Map[string, V]
+	parse  func(string) (V, error)
+	format func(V) string
+}
+
+// Open gets the map called name with the codec for valueType: "int64", "float64" or anything else
+// for strings
+func Open(ctx context.Context, name, valueType string) (Map, error) {
+	switch valueType {
+	case "int64":
+		return openTypedMap(ctx, name, generic.Scalar[int64](), parseInt64, formatInt64)
+	case "float64":
+		return openTypedMap[float64](ctx, name, float64Codec{}, parseFloat64, formatFloat64)
+	default:
+		return openTypedMap(ctx, name, generic.Scalar[string](),
+			func(s string) (string, error) { return s, nil },
+			func(v string) string { return v })
+	}
+}
+
+func openTypedMap[V any](ctx context.Context, name string, codec generic.Codec[V], parse func(string) (V, error), format func(V) string) (Map, error) {
+	m, err := atomix.Map[string, V](name).Codec(codec).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &typedMap[V]{m: m, parse: parse, format: format}, nil
+}
+
+func parseInt64(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) }
+
+func formatInt64(v int64) string { return strconv.FormatInt(v, 10) }
+
+func parseFloat64(s string) (float64, error) { return strconv.ParseFloat(s, 64) }
+
+func formatFloat64(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+func (t *typedMap[V]) Put(ctx context.Context, key, value string) error {
+	v, err := t.parse(value)
+	if err != nil {
+		return fmt.Errorf("value '%s' does not match VALUE_TYPE: %v", value, err)
+	}
+	_, err = t.m.Put(ctx, key, v)
+	return err
+}
+
+func (t *typedMap[V]) Get(ctx context.Context, key string) (*Entry, error) {
+	entry, err := t.m.Get(ctx, key)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return &Entry{Value: t.format(entry.Value), Version: entry.Version}, nil
+}
+
+// Update writes value only if the entry is still at version
+func (t *typedMap[V]) Update(ctx context.Context, key, value string, version primitive.Version) error {
+	v, err := t.parse(value)
+	if err != nil {
+		return fmt.Errorf("value '%s' does not match VALUE_TYPE: %v", value, err)
+	}
+	_, err = t.m.Update(ctx, key, v, _map.IfVersion(version))
+	return err
+}
+
+func (t *typedMap[V]) Len(ctx context.Context) (int, error) {
+	return t.m.Len(ctx)
+}
+
+func (t *typedMap[V]) Remove(ctx context.Context, key string) error {
+	_, err := t.m.Remove(ctx, key)
+	return err
+}
+
+// float64Codec encodes float64 values as their 8-byte IEEE 754 representation; generic.Scalar only covers strings and integers
+type float64Codec struct{}
+
+func (float64Codec) Encode(v float64) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, math.Float64bits(v)), nil
+}
+
+func (float64Codec) Decode(b []byte) (float64, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid float64 encoding: %d bytes", len(b))
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+}
+
+// Failure categories logged alongside failed operations, so failover unavailability can be told
+// apart from timeouts and missing keys
+const (
+	CategoryUnavailable      = "Unavailable"
+	CategoryDeadlineExceeded = "DeadlineExceeded"
+	CategoryNotFound         = "NotFound"
+	CategoryConflict         = "Conflict"
+	CategoryUnknown          = "Unknown"
+)
+
+// ErrorCategory classifies an Atomix error, or a raw gRPC status or context error, into one of the
+// failure categories above
+func ErrorCategory(err error) string {
+	var typed *errors.TypedError
+	switch {
+	case stderrors.As(err, &typed):
+		err = typed
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return CategoryDeadlineExceeded
+	default:
+		err = errors.FromProto(err)
+	}
+	switch {
+	case errors.IsUnavailable(err):
+		return CategoryUnavailable
+	case errors.IsTimeout(err):
+		return CategoryDeadlineExceeded
+	case errors.IsNotFound(err):
+		return CategoryNotFound
+	case errors.IsConflict(err), errors.IsAlreadyExists(err):
+		return CategoryConflict
+	default:
+		return CategoryUnknown
+	}
+}
+
+// SizeLabel describes VALUE_SIZE for the CONFIG line
+func SizeLabel(size int) string {
+	if size == 0 {
+		return "unpadded"
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
@@ -0,0 +1,96 @@
+package valuemap
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/atomix/runtime/sdk/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFloat64Codec(t *testing.T) {
+	var codec float64Codec
+	for _, v := range []float64{0, 1.5, -273.15, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(-1)} {
+		b, err := codec.Encode(v)
+		if err != nil {
+			t.Fatalf("Encode(%v): %v", v, err)
+		}
+		if len(b) != 8 {
+			t.Errorf("Encode(%v) = %d bytes, want 8", v, len(b))
+		}
+		got, err := codec.Decode(b)
+		if err != nil || got != v {
+			t.Errorf("Decode(Encode(%v)) = %v, %v", v, got, err)
+		}
+	}
+
+	for _, b := range [][]byte{nil, make([]byte, 4), make([]byte, 9)} {
+		if _, err := codec.Decode(b); err == nil {
+			t.Errorf("Decode of %d bytes succeeded, want an error", len(b))
+		}
+	}
+}
+
+// TestValueFormats checks that values survive the string conversion tests rely on, so a value read
+// back compares equal to the one written
+func TestValueFormats(t *testing.T) {
+	for _, s := range []string{"0", "42", "-7", "9223372036854775807"} {
+		v, err := parseInt64(s)
+		if err != nil || formatInt64(v) != s {
+			t.Errorf("int64 %q came back as %q, %v", s, formatInt64(v), err)
+		}
+	}
+	for _, s := range []string{"0", "1.5", "-0.25", "12.000001", "1234567.891"} {
+		v, err := parseFloat64(s)
+		if err != nil || formatFloat64(v) != s {
+			t.Errorf("float64 %q came back as %q, %v", s, formatFloat64(v), err)
+		}
+	}
+	if _, err := parseInt64("value-000001"); err == nil {
+		t.Error("parseInt64 accepted a string value")
+	}
+	if _, err := parseFloat64("value-000001"); err == nil {
+		t.Error("parseFloat64 accepted a string value")
+	}
+}
+
+func TestErrorCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "unavailable", err: errors.NewUnavailable("no leader"), want: CategoryUnavailable},
+		{name: "timeout", err: errors.NewTimeout("timed out"), want: CategoryDeadlineExceeded},
+		{name: "not found", err: errors.NewNotFound("missing"), want: CategoryNotFound},
+		{name: "conflict", err: errors.NewConflict("version changed"), want: CategoryConflict},
+		{name: "already exists", err: errors.NewAlreadyExists("exists"), want: CategoryConflict},
+		{name: "wrapped", err: fmt.Errorf("put: %w", errors.NewUnavailable("no leader")), want: CategoryUnavailable},
+		{name: "context deadline", err: context.DeadlineExceeded, want: CategoryDeadlineExceeded},
+		{name: "wrapped context deadline", err: fmt.Errorf("get: %w", context.DeadlineExceeded), want: CategoryDeadlineExceeded},
+		{name: "grpc unavailable", err: status.Error(codes.Unavailable, "connection refused"), want: CategoryUnavailable},
+		{name: "grpc deadline", err: status.Error(codes.DeadlineExceeded, "deadline"), want: CategoryDeadlineExceeded},
+		{name: "other atomix error", err: errors.NewInternal("boom"), want: CategoryUnknown},
+		{name: "plain error", err: fmt.Errorf("boom"), want: CategoryUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCategory(tt.err); got != tt.want {
+				t.Errorf("ErrorCategory(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSizeLabel(t *testing.T) {
+	if got := SizeLabel(0); got != "unpadded" {
+		t.Errorf("SizeLabel(0) = %q, want unpadded", got)
+	}
+	if got := SizeLabel(1024); got != "1024 bytes" {
+		t.Errorf("SizeLabel(1024) = %q, want 1024 bytes", got)
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	s := &Server{authToken: "secret"}
+	handler := s.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		want          int
+	}{
+		{name: "missing token", method: http.MethodPost, want: http.StatusUnauthorized},
+		{name: "wrong token", method: http.MethodPost, authorization: "Bearer guess", want: http.StatusUnauthorized},
+		{name: "token without bearer scheme", method: http.MethodDelete, authorization: "secret", want: http.StatusUnauthorized},
+		{name: "valid token", method: http.MethodPut, authorization: "Bearer secret", want: http.StatusNoContent},
+		{name: "read without token", method: http.MethodGet, want: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/devices", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want != http.StatusUnauthorized {
+				return
+			}
+			if got := rec.Header().Get("WWW-Authenticate"); got == "" {
+				t.Error("401 without a WWW-Authenticate header")
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decoding error body: %v", err)
+			}
+			if resp.Status != http.StatusUnauthorized || resp.Error == "" {
+				t.Errorf("error body = %+v", resp)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareWithoutToken(t *testing.T) {
+	s := &Server{}
+	handler := s.authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/devices", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("status = %d with auth disabled, want %d", rec.Code, http.StatusNoContent)
+	}
+}
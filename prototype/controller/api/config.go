@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"prototype/controller/optimeout"
+
+	"github.com/atomix/go-sdk/pkg/atomix"
+	"github.com/atomix/go-sdk/pkg/generic"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+)
+
+type ConfigResponse struct {
+	Entries map[string]string `json:"entries"`
+	Count   int               `json:"count"`
+}
+
+// GetConfigHandler lists the config map, where each device's leader records
+// "leader <candidate ID>" under its election name. ?key= returns a single entry.
+func (s *Server) GetConfigHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := optimeout.WithTimeout(s.ctx)
+	defer cancel()
+
+	configMap, err := atomix.Map[string, string]("config").
+		Codec(generic.Scalar[string]()).
+		Get(ctx)
+	if err != nil {
+		log.Printf("[Config] Failed to get config map: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "Failed to get config map")
+		return
+	}
+
+	entries := make(map[string]string)
+	if key := r.URL.Query().Get("key"); key != "" {
+		entry, err := configMap.Get(ctx, key)
+		if err == nil && entry == nil {
+			err = errors.NewNotFound("config key %s not found", key)
+		}
+		if err != nil {
+			if errors.IsNotFound(err) {
+				writeJSONError(w, http.StatusNotFound, "config key not found")
+				return
+			}
+			writeJSONError(w, http.StatusInternalServerError, "Failed to read config entry")
+			return
+		}
+		entries[entry.Key] = entry.Value
+	} else {
+		stream, err := configMap.List(ctx)
+		if err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Failed to read config map")
+			return
+		}
+		for {
+			entry, err := stream.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("[Config] Error listing config map: %v", err)
+				writeJSONError(w, http.StatusInternalServerError, "Failed to read config map")
+				return
+			}
+			entries[entry.Key] = entry.Value
+		}
+	}
+
+	resp := ConfigResponse{
+		Entries: entries,
+		Count:   len(entries),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
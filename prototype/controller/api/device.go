@@ -1,18 +1,33 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"prototype/controller/device"
+	"prototype/controller/optimeout"
+	"strconv"
 	"time"
 
 	"github.com/atomix/go-sdk/pkg/atomix"
 	"github.com/atomix/go-sdk/pkg/generic"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+	"github.com/gorilla/mux"
 )
 
 type AddDeviceRequest struct {
 	DeviceID string `json:"device_id"`
+	Driver   string `json:"driver,omitempty"`  // "fake" (default) or "http"
+	Address  string `json:"address,omitempty"` // device endpoint, required for the http driver
+}
+
+type DeviceConfigResponse struct {
+	DeviceID string            `json:"device_id"`
+	Config   map[string]string `json:"config"`
+	Version  int64             `json:"version"`
 }
 
 type DeviceResponse struct {
@@ -21,20 +36,27 @@ type DeviceResponse struct {
 	LastUpdatedAt time.Time         `json:"last_updated_at"`
 }
 
-func (s *Server) ListDevicesHandler(w http.ResponseWriter, r *http.Request) {
-	ctx := s.ctx
-
-	driverMap, err := atomix.Map[string, string]("device").
+func getDeviceMap(ctx context.Context) (_map.Map[string, string], error) {
+	return atomix.Map[string, string]("device").
 		Codec(generic.Scalar[string]()).
 		Get(ctx)
+}
+
+func (s *Server) ListDevicesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := optimeout.WithTimeout(s.ctx)
+	defer cancel()
+
+	driverMap, err := s.deviceMap(ctx)
 	if err != nil {
-		log.Fatalf("[Devices] Failed to get device map: %v", err)
+		log.Printf("[Devices] Failed to get device map: %v", err)
+		writeJSONError(w, http.StatusInternalServerError, "Failed to get device map")
+		return
 	}
 
 	var devices map[string]string = make(map[string]string)
 	stream, err := driverMap.List(ctx)
 	if err != nil {
-		http.Error(w, "Failed to read device list", http.StatusInternalServerError)
+		writeJSONError(w, http.StatusInternalServerError, "Failed to read device list")
 		return
 	}
 	for {
@@ -60,19 +82,173 @@ func (s *Server) AddDeviceHandler(w http.ResponseWriter, r *http.Request) {
 	// Parse JSON body
 	var req AddDeviceRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
 	if req.DeviceID == "" {
-		http.Error(w, "device_id is required", http.StatusBadRequest)
+		writeJSONError(w, http.StatusBadRequest, "device_id is required")
 		return
 	}
 
-	// Create new Device
-	newDev := device.NewDevice(req.DeviceID, &device.FakeDriver{ID: req.DeviceID})
-	newDev.ApplyConfig(s.ctx, make(map[string]string))
+	force := false
+	if v := r.URL.Query().Get("force"); v != "" {
+		var err error
+		if force, err = strconv.ParseBool(v); err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid force parameter: "+v)
+			return
+		}
+	}
+
+	ctx, cancel := optimeout.WithTimeout(s.ctx)
+	defer cancel()
+	driverMap, err := s.deviceMap(ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Failed to get device map")
+		return
+	}
+
+	// Re-adding a device would reset its config to empty, so only do it when asked to
+	if !force {
+		entry, err := driverMap.Get(ctx, req.DeviceID)
+		if err == nil && entry == nil {
+			err = errors.NewNotFound("device %s not found", req.DeviceID)
+		}
+		if err == nil {
+			writeJSONError(w, http.StatusConflict, "device already exists (use ?force=true to re-add it)")
+			return
+		}
+		if !errors.IsNotFound(err) {
+			writeJSONError(w, http.StatusInternalServerError, "Failed to read device")
+			return
+		}
+	}
+
+	info := device.DriverInfo{Type: req.Driver, Address: req.Address}
+	if info.Type == "" {
+		info.Type = "fake"
+	}
+	driver, err := device.NewDriver(req.DeviceID, info)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	newDev := device.NewDevice(req.DeviceID, driver)
+	config := make(map[string]string)
+
+	// The driver is registered before the device map entry appears, so the Monitor starts the
+	// device with it
+	switch info.Type {
+	case "fake":
+		if err := s.registerDriver(ctx, req.DeviceID, info); err != nil {
+			log.Printf("[Devices] Failed to register driver for %s: %v", req.DeviceID, err)
+			writeJSONError(w, http.StatusInternalServerError, "Failed to add device")
+			return
+		}
+		// The fake driver adds the device by writing its config to the device map
+		if err := newDev.ApplyConfig(s.ctx, config); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Failed to add device")
+			return
+		}
+	case "http":
+		if err := newDev.ApplyConfig(s.ctx, config); err != nil {
+			log.Printf("[Devices] Failed to reach device %s at %s: %v", req.DeviceID, req.Address, err)
+			writeJSONError(w, http.StatusBadGateway, "Failed to push initial config to device")
+			return
+		}
+		if err := s.registerDriver(ctx, req.DeviceID, info); err != nil {
+			log.Printf("[Devices] Failed to register driver for %s: %v", req.DeviceID, err)
+			writeJSONError(w, http.StatusInternalServerError, "Failed to add device")
+			return
+		}
+		// The HTTP driver doesn't touch Atomix, so add the device with its initial config here
+		if _, err := driverMap.Put(ctx, req.DeviceID, fmt.Sprintf("%+v", config)); err != nil {
+			writeJSONError(w, http.StatusInternalServerError, "Failed to add device")
+			return
+		}
+	}
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("Device added successfully"))
 }
+
+func (s *Server) DeleteDeviceHandler(w http.ResponseWriter, r *http.Request) {
+	deviceID := mux.Vars(r)["device_id"]
+
+	ctx, cancel := optimeout.WithTimeout(s.ctx)
+	defer cancel()
+
+	driverMap, err := s.deviceMap(ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Failed to get device map")
+		return
+	}
+
+	if _, err := driverMap.Remove(ctx, deviceID); err != nil {
+		if errors.IsNotFound(err) {
+			writeJSONError(w, http.StatusNotFound, "device not found")
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, "Failed to remove device")
+		return
+	}
+	// A leftover driver is harmless, since re-adding the device registers it again
+	if err := device.Unregister(ctx, deviceID); err != nil {
+		log.Printf("[Devices] Failed to unregister driver for %s: %v", deviceID, err)
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Device removed successfully"))
+}
+
+func (s *Server) PushDeviceConfigHandler(w http.ResponseWriter, r *http.Request) {
+	deviceID := mux.Vars(r)["device_id"]
+
+	var config map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	ctx, cancel := optimeout.WithTimeout(s.ctx)
+	defer cancel()
+
+	driverMap, err := s.deviceMap(ctx)
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Failed to get device map")
+		return
+	}
+
+	entry, err := driverMap.Get(ctx, deviceID)
+	if err == nil && entry == nil {
+		err = errors.NewNotFound("device %s not found", deviceID)
+	}
+	if err != nil {
+		if errors.IsNotFound(err) {
+			writeJSONError(w, http.StatusNotFound, "device not found")
+			return
+		}
+		writeJSONError(w, http.StatusInternalServerError, "Failed to read device")
+		return
+	}
+
+	dev, err := device.Load(ctx, deviceID)
+	if err != nil {
+		log.Printf("[Devices] Failed to load driver for %s: %v", deviceID, err)
+		writeJSONError(w, http.StatusInternalServerError, "Failed to read device")
+		return
+	}
+	if err := dev.ApplyConfig(s.ctx, config); err != nil {
+		writeJSONError(w, http.StatusInternalServerError, "Failed to push device config")
+		return
+	}
+
+	resp := DeviceConfigResponse{
+		DeviceID: deviceID,
+		Config:   config,
+		Version:  time.Now().UnixNano(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"prototype/controller/device"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/atomix/go-sdk/pkg/primitive"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+	"github.com/gorilla/mux"
+)
+
+// memDeviceMap stands in for the Atomix device map. Only Get and Put are implemented; the embedded
+// nil Map panics if a handler calls anything else. With nilMissing set, Get reports a missing key as
+// a nil entry and no error rather than NotFound.
+type memDeviceMap struct {
+	_map.Map[string, string]
+	entries    map[string]string
+	nilMissing bool
+}
+
+func (m *memDeviceMap) Get(_ context.Context, key string, _ ..._map.GetOption) (*_map.Entry[string, string], error) {
+	value, ok := m.entries[key]
+	if !ok && m.nilMissing {
+		return nil, nil
+	}
+	if !ok {
+		return nil, errors.NewNotFound("key %s not found", key)
+	}
+	return &_map.Entry[string, string]{Key: key, Versioned: primitive.Versioned[string]{Value: value}}, nil
+}
+
+func (m *memDeviceMap) Put(_ context.Context, key string, value string, _ ..._map.PutOption) (*_map.Entry[string, string], error) {
+	m.entries[key] = value
+	return &_map.Entry[string, string]{Key: key, Versioned: primitive.Versioned[string]{Value: value}}, nil
+}
+
+// These requests are rejected before AddDeviceHandler touches Atomix, so no runtime is needed
+func TestAddDeviceHandlerBadRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		body    string
+		wantErr string
+	}{
+		{name: "empty device_id", target: "/devices", body: `{"device_id": ""}`, wantErr: "device_id is required"},
+		{name: "missing device_id", target: "/devices", body: `{}`, wantErr: "device_id is required"},
+		{name: "malformed body", target: "/devices", body: `{"device_id":`, wantErr: "invalid request body"},
+		{name: "bad force", target: "/devices?force=maybe", body: `{"device_id": "device-1"}`, wantErr: "invalid force parameter: maybe"},
+	}
+
+	s := &Server{ctx: context.Background()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			s.AddDeviceHandler(rec, httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body)))
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if got := rec.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decoding error body: %v", err)
+			}
+			if want := (ErrorResponse{Error: tt.wantErr, Status: http.StatusBadRequest}); resp != want {
+				t.Errorf("error body = %+v, want %+v", resp, want)
+			}
+		})
+	}
+}
+
+// TestAddDeviceHandlerExisting posts the same device twice and checks the second post leaves the
+// device's stored config alone unless it is forced
+func TestAddDeviceHandlerExisting(t *testing.T) {
+	var pushes atomic.Int32
+	deviceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pushes.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer deviceServer.Close()
+
+	devices := &memDeviceMap{entries: make(map[string]string)}
+	drivers := make(map[string]device.DriverInfo)
+	s := &Server{
+		ctx:       context.Background(),
+		deviceMap: func(context.Context) (_map.Map[string, string], error) { return devices, nil },
+		registerDriver: func(_ context.Context, id string, info device.DriverInfo) error {
+			drivers[id] = info
+			return nil
+		},
+	}
+	post := func(target string) int {
+		body := `{"device_id": "device-1", "driver": "http", "address": "` + deviceServer.URL + `"}`
+		rec := httptest.NewRecorder()
+		s.AddDeviceHandler(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+		return rec.Code
+	}
+
+	if code := post("/devices"); code != http.StatusOK {
+		t.Fatalf("first add = %d, want %d", code, http.StatusOK)
+	}
+	if got, want := drivers["device-1"], (device.DriverInfo{Type: "http", Address: deviceServer.URL}); got != want {
+		t.Errorf("registered driver = %+v, want %+v", got, want)
+	}
+
+	// As if a config had been pushed since
+	const pushed = "map[flow:deny all]"
+	devices.entries["device-1"] = pushed
+
+	if code := post("/devices"); code != http.StatusConflict {
+		t.Errorf("second add = %d, want %d", code, http.StatusConflict)
+	}
+	if got := devices.entries["device-1"]; got != pushed {
+		t.Errorf("second add reset the stored config to %q", got)
+	}
+	if n := pushes.Load(); n != 1 {
+		t.Errorf("device received %d config pushes, want only the first add's", n)
+	}
+
+	if code := post("/devices?force=true"); code != http.StatusOK {
+		t.Errorf("forced add = %d, want %d", code, http.StatusOK)
+	}
+	if got := devices.entries["device-1"]; got != "map[]" {
+		t.Errorf("forced add left the stored config at %q, want it reset", got)
+	}
+}
+
+// TestDeviceHandlersNilEntry checks that a nil entry with no error from the device map is treated as
+// a missing device, not as one that exists
+func TestDeviceHandlersNilEntry(t *testing.T) {
+	deviceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer deviceServer.Close()
+
+	devices := &memDeviceMap{entries: make(map[string]string), nilMissing: true}
+	s := &Server{
+		ctx:            context.Background(),
+		deviceMap:      func(context.Context) (_map.Map[string, string], error) { return devices, nil },
+		registerDriver: func(context.Context, string, device.DriverInfo) error { return nil },
+	}
+
+	rec := httptest.NewRecorder()
+	s.PushDeviceConfigHandler(rec, mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/devices/device-1/config", strings.NewReader(`{"flow": "deny all"}`)),
+		map[string]string{"device_id": "device-1"}))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("push to a missing device = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	rec = httptest.NewRecorder()
+	s.AddDeviceHandler(rec, httptest.NewRequest(http.MethodPost, "/devices", strings.NewReader(`{"device_id": "device-1", "driver": "http", "address": "`+deviceServer.URL+`"}`)))
+	if rec.Code != http.StatusOK {
+		t.Errorf("add of a missing device = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if _, ok := devices.entries["device-1"]; !ok {
+		t.Error("add didn't store the device")
+	}
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"prototype/controller/leadership"
+
+	"github.com/gorilla/mux"
+)
+
+type Election struct {
+	DeviceID   string         `json:"device_id"`
+	Leader     string         `json:"leader"`
+	Term       uint64         `json:"term"`
+	Candidates []string       `json:"candidates"`
+	Wins       map[string]int `json:"wins,omitempty"`
+}
+
+type ElectionsResponse struct {
+	Elections []Election `json:"elections"`
+	Count     int        `json:"count"`
+}
+
+type StepDownResponse struct {
+	DeviceID       string `json:"device_id"`
+	PreviousLeader string `json:"previous_leader"`
+	Leader         string `json:"leader"`
+}
+
+// StepDownHandler makes this controller give up leadership of a device and reports who took over
+func (s *Server) StepDownHandler(w http.ResponseWriter, r *http.Request) {
+	deviceID := mux.Vars(r)["device_id"]
+
+	previous, _ := s.electionManager.GetLeader(deviceID)
+	leader, err := s.electionManager.StepDown(deviceID)
+	switch {
+	case errors.Is(err, leadership.ErrNoElection):
+		writeJSONError(w, http.StatusNotFound, "no active election for device")
+		return
+	case errors.Is(err, leadership.ErrNotLeader):
+		writeJSONError(w, http.StatusConflict, "this controller is not the device's leader")
+		return
+	case errors.Is(err, leadership.ErrStepDownTimeout):
+		writeJSONError(w, http.StatusGatewayTimeout, "timed out waiting for a new leader")
+		return
+	case err != nil:
+		log.Printf("[Leadership] Failed to step down from %s: %v", deviceID, err)
+		writeJSONError(w, http.StatusInternalServerError, "Failed to step down")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(StepDownResponse{DeviceID: deviceID, PreviousLeader: previous, Leader: leader})
+}
+
+func (s *Server) GetElectionsHandler(w http.ResponseWriter, r *http.Request) {
+	elections := make([]Election, 0)
+	for _, info := range s.electionManager.Elections() {
+		elections = append(elections, Election{
+			DeviceID:   info.DeviceID,
+			Leader:     info.Leader,
+			Term:       info.Term,
+			Candidates: info.Candidates,
+			Wins:       info.Wins,
+		})
+	}
+
+	resp := ElectionsResponse{
+		Elections: elections,
+		Count:     len(elections),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
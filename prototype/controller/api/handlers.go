@@ -1,6 +1,8 @@
 package api
 
 import (
+	"prototype/controller/metrics"
+
 	"github.com/gorilla/mux"
 )
 
@@ -9,11 +11,28 @@ func (s *Server) NewRouter() *mux.Router {
 
 	// Health check
 	r.HandleFunc("/health", s.HealthHandler).Methods("GET")
+	r.HandleFunc("/ready", s.ReadinessHandler).Methods("GET")
 	// Membership
 	r.HandleFunc("/members", s.GetMembersHandler).Methods("GET")
+	r.HandleFunc("/members/watch", s.WatchMembersHandler).Methods("GET")
 	// Devices
 	r.HandleFunc("/devices", s.ListDevicesHandler).Methods("GET")
 	r.HandleFunc("/devices", s.AddDeviceHandler).Methods("POST")
+	r.HandleFunc("/devices/{device_id}", s.DeleteDeviceHandler).Methods("DELETE")
+	r.HandleFunc("/devices/{device_id}/config", s.PushDeviceConfigHandler).Methods("PUT")
+	r.HandleFunc("/devices/{device_id}/stepdown", s.StepDownHandler).Methods("POST")
+	// Elections
+	r.HandleFunc("/elections", s.GetElectionsHandler).Methods("GET")
+	// Config
+	r.HandleFunc("/config", s.GetConfigHandler).Methods("GET")
+	// Stats
+	r.HandleFunc("/stats", s.StatsHandler).Methods("GET")
+	r.HandleFunc("/events", s.EventsHandler).Methods("GET")
+	// Metrics
+	r.Handle("/metrics", metrics.Handler()).Methods("GET")
+
+	r.Use(metricsMiddleware)
+	r.Use(s.authMiddleware)
 
 	return r
 }
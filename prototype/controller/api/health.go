@@ -1,8 +1,103 @@
 package api
 
-import "net/http"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
 
+	"github.com/atomix/go-sdk/pkg/atomix"
+	"github.com/atomix/go-sdk/pkg/generic"
+)
+
+const healthCheckTimeout = 2 * time.Second
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// HealthHandler is the liveness check: it answers 200 as long as the process can serve requests
 func (s *Server) HealthHandler(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(200)
-	w.Write([]byte("OK"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(HealthResponse{Status: "ok"})
+}
+
+type ReadinessResponse struct {
+	Status           string  `json:"status"`
+	Atomix           bool    `json:"atomix"`
+	MembershipSynced bool    `json:"membership_synced"`
+	LatencyMs        float64 `json:"latency_ms"` // Atomix round trip
+	Error            string  `json:"error,omitempty"`
+}
+
+// ReadinessHandler returns 200 while Atomix can be reached and once the membership informer has
+// synced, and 503 otherwise. Atomix is checked on every probe, so a controller that loses it stops
+// taking traffic. Elections aren't waited for: a controller with no devices has none, and it has to
+// be ready to receive the POST /devices that would start one.
+func (s *Server) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	resp := ReadinessResponse{
+		MembershipSynced: isClosed(s.membershipManager.Ready()),
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checkAtomix(ctx)
+	resp.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		resp.Error = err.Error()
+	} else {
+		resp.Atomix = true
+	}
+
+	resp.Status = "ready"
+	status := http.StatusOK
+	if !resp.Atomix || !resp.MembershipSynced {
+		resp.Status = "not ready"
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// checkAtomix does a Put+Get round trip on this host's key in the health-check map.
+func checkAtomix(ctx context.Context) error {
+	healthMap, err := atomix.Map[string, string]("health-check").
+		Codec(generic.Scalar[string]()).
+		Get(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get health-check map: %w", err)
+	}
+
+	key, _ := os.Hostname()
+	value := time.Now().Format(time.RFC3339Nano)
+	if _, err := healthMap.Put(ctx, key, value); err != nil {
+		return fmt.Errorf("failed to write health-check key: %w", err)
+	}
+
+	entry, err := healthMap.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read health-check key: %w", err)
+	}
+	if entry == nil {
+		return fmt.Errorf("health-check key missing after write")
+	}
+	if entry.Value != value {
+		return fmt.Errorf("health-check read returned %q, expected %q", entry.Value, value)
+	}
+	return nil
 }
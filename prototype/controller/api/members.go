@@ -2,14 +2,25 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
+	"log"
 	"net/http"
 	"time"
+
+	"prototype/controller/leadership"
 )
 
 type MembersResponse struct {
 	Members       []string  `json:"members"`
 	Count         int       `json:"count"`
 	LastUpdatedAt time.Time `json:"last_updated_at"`
+
+	// Devices is how many device elections this controller is in. Leading counts the elections each
+	// member leads (0 for none); OtherLeaders counts those led by candidates that aren't current
+	// members, e.g. a departed pod that hasn't been evicted yet
+	Devices      int            `json:"devices"`
+	Leading      map[string]int `json:"leading"`
+	OtherLeaders map[string]int `json:"other_leaders,omitempty"`
 }
 
 func (s *Server) GetMembersHandler(w http.ResponseWriter, r *http.Request) {
@@ -17,16 +28,71 @@ func (s *Server) GetMembersHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Collect all members
 	var members []string
-	for member := range membershipManager.Active {
+	uids := membershipManager.Members()
+	for member := range uids {
 		members = append(members, member)
 	}
 
+	distribution := s.electionManager.LeadershipDistribution()
+	leaders := make([]string, 0, len(distribution))
+	for leader := range distribution {
+		leaders = append(leaders, leader)
+	}
+	leading := make(map[string]int, len(members))
+	for _, member := range members {
+		candidateID := leadership.MemberCandidate(leaders, member, uids[member])
+		leading[member] = distribution[candidateID]
+		delete(distribution, candidateID)
+	}
+
 	resp := MembersResponse{
 		Members:       members,
 		Count:         len(members),
-		LastUpdatedAt: time.Now(), // in a real setup, you might get this from Watch()
+		LastUpdatedAt: membershipManager.LastUpdatedAt(),
+		Devices:       s.electionManager.ActiveCount(),
+		Leading:       leading,
+		OtherLeaders:  distribution,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+// WatchMembersHandler streams membership changes as Server-Sent Events until the client disconnects
+func (s *Server) WatchMembersHandler(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Printf("[Membership] Membership watch not supported: %v", err)
+		return
+	}
+
+	sub := s.membershipManager.Subscribe()
+	defer s.membershipManager.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("[Membership] Failed to encode membership event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -2,22 +2,79 @@ package api
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
+	"prototype/controller/device"
+	"prototype/controller/leadership"
 	"prototype/controller/membership"
+	"time"
+
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
 )
 
 type Server struct {
 	ctx               context.Context
 	membershipManager *membership.MembershipManager
+	electionManager   *leadership.ElectionManager
+	authToken         string
+
+	// The device handlers reach Atomix through these, so tests can swap in an in-memory map
+	deviceMap      func(ctx context.Context) (_map.Map[string, string], error)
+	registerDriver func(ctx context.Context, id string, info device.DriverInfo) error
 }
 
-func StartServer(ctx context.Context, membershipManager *membership.MembershipManager, port string) {
-	s := &Server{ctx: ctx, membershipManager: membershipManager}
-	r := s.NewRouter()
+// ServerOptions holds the API's optional transport and auth settings; the zero value serves plain HTTP without auth.
+type ServerOptions struct {
+	TLSCertFile string
+	TLSKeyFile  string
+	// AuthToken, if set, is required as a bearer token on mutating requests
+	AuthToken string
+}
+
+// StartServer serves the API until ctx is canceled, then gives in-flight requests up to shutdownTimeout to finish.
+func StartServer(ctx context.Context, membershipManager *membership.MembershipManager, electionManager *leadership.ElectionManager, port string, shutdownTimeout time.Duration, opts ServerOptions) error {
+	s := &Server{
+		ctx:               ctx,
+		membershipManager: membershipManager,
+		electionManager:   electionManager,
+		authToken:         opts.AuthToken,
+		deviceMap:         getDeviceMap,
+		registerDriver:    device.Register,
+	}
+	srv := &http.Server{
+		Addr:    port,
+		Handler: s.NewRouter(),
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if opts.TLSCertFile != "" {
+			log.Printf("Starting HTTPS server on port %s", port)
+			errCh <- srv.ListenAndServeTLS(opts.TLSCertFile, opts.TLSKeyFile)
+			return
+		}
+		log.Printf("Starting HTTP server on port %s", port)
+		errCh <- srv.ListenAndServe()
+	}()
 
-	log.Printf("Starting HTTP server on port %s", port)
-	if err := http.ListenAndServe(port, r); err != nil {
-		log.Fatalf("Failed to start HTTP server: %v", err)
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down HTTP server (grace period %v)", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
 	}
+	return nil
 }
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// processStart approximates when the controller started; the api package is initialised at startup
+var processStart = time.Now()
+
+type StatsResponse struct {
+	ActiveElections         int            `json:"active_elections"`
+	LeadingElections        int            `json:"leading_elections"`
+	LeadershipDistribution  map[string]int `json:"leadership_distribution"`
+	Members                 int            `json:"members"`
+	MembershipLastUpdatedAt time.Time      `json:"membership_last_updated_at"`
+	StartedAt               time.Time      `json:"started_at"`
+	TermGaps                int            `json:"term_gaps"`
+	UptimeSeconds           float64        `json:"uptime_seconds"`
+}
+
+// StatsHandler returns a snapshot of the controller's internal state for debugging
+func (s *Server) StatsHandler(w http.ResponseWriter, r *http.Request) {
+	resp := StatsResponse{
+		ActiveElections:         s.electionManager.ActiveCount(),
+		LeadingElections:        s.electionManager.LeaderCount(),
+		LeadershipDistribution:  s.electionManager.LeadershipDistribution(),
+		Members:                 s.membershipManager.Count(),
+		MembershipLastUpdatedAt: s.membershipManager.LastUpdatedAt(),
+		StartedAt:               processStart,
+		TermGaps:                s.electionManager.TermGaps(),
+		UptimeSeconds:           time.Since(processStart).Seconds(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
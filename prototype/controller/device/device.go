@@ -3,6 +3,7 @@ package device
 import (
 	"context"
 	"log"
+	"prototype/controller/metrics"
 	"time"
 )
 
@@ -18,25 +19,59 @@ func NewDevice(id string, driver Driver) *Device {
 	}
 }
 
-func (d *Device) ApplyConfig(ctx context.Context, config map[string]string) {
+func (d *Device) ApplyConfig(ctx context.Context, config map[string]string) error {
 	log.Printf("[%s] Applying config: %+v", d.ID, config)
-	d.Driver.PushConfig(ctx, config)
+	err := d.Driver.PushConfig(ctx, config)
+	metrics.ConfigPushed(err)
+	return err
 }
 
+const maxPollBackoff = time.Minute
+
+// PollStatus fetches the device status every interval until ctx is canceled.
+// Consecutive fetch errors double the wait (capped at maxPollBackoff) so an
+// unreachable device isn't polled at the full rate.
 func (d *Device) PollStatus(ctx context.Context, interval time.Duration) {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	reachable := true
+	failures := 0
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		default:
-			status, err := d.Driver.FetchStatus(ctx)
-			if err != nil {
-				// log.Printf("[%s] Error fetching status: %v", d.ID, err)
-				time.Sleep(interval)
-				continue
+		case <-timer.C:
+		}
+
+		status, err := d.Driver.FetchStatus(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+			if reachable {
+				log.Printf("[%s] Device unreachable: %v", d.ID, err)
+				reachable = false
 			}
-			log.Printf("[%s] Status: %+v", d.ID, status)
-			time.Sleep(interval)
+			timer.Reset(pollBackoff(interval, failures))
+			continue
 		}
+
+		if !reachable {
+			log.Printf("[%s] Device reachable again after %d failed polls", d.ID, failures)
+			reachable = true
+		}
+		failures = 0
+		log.Printf("[%s] Status: %+v", d.ID, status)
+		timer.Reset(interval)
+	}
+}
+
+func pollBackoff(interval time.Duration, failures int) time.Duration {
+	backoff := interval
+	for i := 1; i < failures && backoff < maxPollBackoff; i++ {
+		backoff *= 2
 	}
+	return min(backoff, maxPollBackoff)
 }
@@ -3,37 +3,67 @@ package device
 import (
 	"context"
 	"fmt"
+	"prototype/controller/optimeout"
 	"time"
 
 	"github.com/atomix/go-sdk/pkg/atomix"
 	"github.com/atomix/go-sdk/pkg/generic"
+	"github.com/atomix/runtime/sdk/pkg/errors"
 )
 
 type FakeDriver struct {
 	ID string
 }
 
-func (f *FakeDriver) PushConfig(ctx context.Context, config map[string]string) error {
-	driverMap, err := atomix.Map[string, string]("device").
+// getDeviceStore returns the device map, which the FakeDriver stands in for a device with
+func getDeviceStore(ctx context.Context) (driverStore, error) {
+	deviceMap, err := atomix.Map[string, string]("device").
 		Codec(generic.Scalar[string]()).
 		Get(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get device map: %w", err)
+		return nil, fmt.Errorf("failed to get device map: %w", err)
 	}
+	return deviceMap, nil
+}
+
+func (f *FakeDriver) PushConfig(ctx context.Context, config map[string]string) error {
+	ctx, cancel := optimeout.WithTimeout(ctx)
+	defer cancel()
+
+	driverMap, err := getDeviceStore(ctx)
+	if err != nil {
+		return err
+	}
+	return f.pushConfig(ctx, driverMap, config)
+}
 
-	driverMap.Put(ctx, f.ID, fmt.Sprintf("%+v", config))
+func (f *FakeDriver) pushConfig(ctx context.Context, driverMap driverStore, config map[string]string) error {
+	if _, err := driverMap.Put(ctx, f.ID, fmt.Sprintf("%+v", config)); err != nil {
+		return fmt.Errorf("failed to push config: %w", err)
+	}
 	return nil
 }
 
 func (f *FakeDriver) FetchStatus(ctx context.Context) (map[string]string, error) {
-	driverMap, err := atomix.Map[string, string]("device").
-		Codec(generic.Scalar[string]()).
-		Get(ctx)
+	ctx, cancel := optimeout.WithTimeout(ctx)
+	defer cancel()
+
+	driverMap, err := getDeviceStore(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get device map: %w", err)
+		return nil, err
 	}
+	return f.fetchStatus(ctx, driverMap)
+}
 
+func (f *FakeDriver) fetchStatus(ctx context.Context, driverMap driverStore) (map[string]string, error) {
 	entry, err := driverMap.Get(ctx, f.ID)
+	if err == nil && entry == nil {
+		err = errors.NewNotFound("device %s not found", f.ID)
+	}
+	if errors.IsNotFound(err) {
+		// Returned unwrapped, since errors.IsNotFound doesn't look through wrapping
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get device status: %w", err)
 	}
@@ -0,0 +1,54 @@
+package device
+
+import (
+	"context"
+	"testing"
+
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+)
+
+// nilEntryStore answers every Get with neither an entry nor an error
+type nilEntryStore struct {
+	*memDriverStore
+}
+
+func (nilEntryStore) Get(context.Context, string, ..._map.GetOption) (*_map.Entry[string, string], error) {
+	return nil, nil
+}
+
+// TestFakeDriverFetchStatusMissing checks that polling a device before its key exists returns a
+// NotFound error instead of panicking on the missing entry
+func TestFakeDriverFetchStatusMissing(t *testing.T) {
+	ctx := context.Background()
+	driver := &FakeDriver{ID: "device-1"}
+
+	for name, store := range map[string]driverStore{
+		"not found error": newMemDriverStore(),
+		"nil entry":       nilEntryStore{newMemDriverStore()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			status, err := driver.fetchStatus(ctx, store)
+			if !errors.IsNotFound(err) {
+				t.Errorf("fetchStatus = %v, %v, want a NotFound error", status, err)
+			}
+		})
+	}
+}
+
+func TestFakeDriverFetchStatus(t *testing.T) {
+	ctx := context.Background()
+	store := newMemDriverStore()
+	driver := &FakeDriver{ID: "device-1"}
+
+	if err := driver.pushConfig(ctx, store, map[string]string{"flow": "allow all"}); err != nil {
+		t.Fatalf("pushConfig: %v", err)
+	}
+	status, err := driver.fetchStatus(ctx, store)
+	if err != nil {
+		t.Fatalf("fetchStatus: %v", err)
+	}
+	if got, want := status["config"], "map[flow:allow all]"; got != want {
+		t.Errorf("status config = %q, want %q", got, want)
+	}
+}
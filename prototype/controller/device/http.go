@@ -0,0 +1,110 @@
+package device
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	httpDriverTimeout    = 5 * time.Second
+	httpDriverMaxRetries = 3
+	httpDriverBackoff    = 200 * time.Millisecond
+)
+
+// HTTPDriver talks to a device exposing POST /config and GET /status at Address.
+type HTTPDriver struct {
+	ID      string
+	Address string
+	client  *http.Client
+}
+
+func NewHTTPDriver(id, address string) *HTTPDriver {
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	return &HTTPDriver{
+		ID:      id,
+		Address: strings.TrimSuffix(address, "/"),
+		client:  &http.Client{Timeout: httpDriverTimeout},
+	}
+}
+
+func (h *HTTPDriver) PushConfig(ctx context.Context, config map[string]string) error {
+	body, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	_, err = h.do(ctx, http.MethodPost, "/config", body)
+	if err != nil {
+		return fmt.Errorf("failed to push config to %s: %w", h.ID, err)
+	}
+	return nil
+}
+
+func (h *HTTPDriver) FetchStatus(ctx context.Context) (map[string]string, error) {
+	body, err := h.do(ctx, http.MethodGet, "/status", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status from %s: %w", h.ID, err)
+	}
+
+	var status map[string]string
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("failed to decode status from %s: %w", h.ID, err)
+	}
+	return status, nil
+}
+
+// do sends the request, retrying with exponential backoff on transport errors and 5xx responses.
+func (h *HTTPDriver) do(ctx context.Context, method, path string, body []byte) ([]byte, error) {
+	var lastErr error
+	backoff := httpDriverBackoff
+
+	for attempt := 0; attempt <= httpDriverMaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, h.Address+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := h.client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("%s %s returned %s", method, path, resp.Status)
+			continue
+		}
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("%s %s returned %s", method, path, resp.Status)
+		}
+		return respBody, nil
+	}
+
+	return nil, lastErr
+}
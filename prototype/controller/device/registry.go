@@ -2,10 +2,17 @@ package device
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"log"
+	"prototype/controller/optimeout"
+	"time"
 
 	"github.com/atomix/go-sdk/pkg/atomix"
 	"github.com/atomix/go-sdk/pkg/generic"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/atomix/runtime/sdk/pkg/errors"
 )
 
 /*
@@ -17,31 +24,282 @@ as well as a controller function to register them to the device list.
 That is TODO.
 */
 
-func Monitor(ctx context.Context, start func(string, *Device), stop func(string)) {
+// watchRetryInterval is the first wait before re-creating a broken device map watch; it doubles per
+// consecutive failure up to maxPollBackoff.
+const watchRetryInterval = time.Second
+
+// Monitor starts every device in the device map and keeps them in sync with it until ctx is canceled.
+// Each device gets its own context, canceled when the device is removed, so whatever start launches
+// for it is torn down even if stop fails. A broken watch is re-created with backoff, re-listing the map
+// to pick up changes missed in between.
+func Monitor(ctx context.Context, start func(context.Context, string, *Device), stop func(string) error) {
+	getCtx, cancel := optimeout.WithTimeout(ctx)
 	driverMap, err := atomix.Map[string, string]("device").
 		Codec(generic.Scalar[string]()).
-		Get(ctx)
+		Get(getCtx)
+	cancel()
 	if err != nil {
 		log.Fatalf("[Devices] Failed to get device map: %v", err)
 	}
 
-	stream, err := driverMap.Watch(ctx)
+	m := &monitor{
+		ctx:       ctx,
+		driverMap: driverMap,
+		start:     start,
+		stop:      stop,
+		started:   make(map[string]context.CancelFunc),
+	}
+
+	failures := 0
+	for {
+		err := m.watch(failures > 0, func() { failures = 0 })
+		if ctx.Err() != nil {
+			log.Printf("[Devices] Context canceled, stopping device monitor")
+			return
+		}
+		failures++
+		backoff := pollBackoff(watchRetryInterval, failures)
+		log.Printf("[Devices] Device watch failed (%d consecutive), re-watching in %v: %v", failures, backoff, err)
+		select {
+		case <-ctx.Done():
+			log.Printf("[Devices] Context canceled, stopping device monitor")
+			return
+		case <-time.After(backoff):
+		}
+	}
+}
+
+type monitor struct {
+	ctx       context.Context
+	driverMap _map.Map[string, string]
+	start     func(context.Context, string, *Device)
+	stop      func(string) error
+	// A device may show up in both the List and an Insert event; only start it once
+	started map[string]context.CancelFunc
+}
+
+// watch lists the device map and then processes its events until the stream fails. On a re-watch,
+// devices that disappeared while the stream was down are stopped. onEvent is called for every event
+// received, so the caller can tell a working stream from one that fails straight away.
+func (m *monitor) watch(rewatch bool, onEvent func()) error {
+	// Events only returns once the first event arrives, so open it concurrently with the List
+	var stream _map.EventStream[string, string]
+	streamErr := make(chan error, 1)
+	go func() {
+		var err error
+		stream, err = m.driverMap.Events(m.ctx)
+		streamErr <- err
+	}()
+
+	listCtx, cancel := optimeout.WithTimeout(m.ctx)
+	listed, err := m.list(listCtx)
+	cancel()
 	if err != nil {
-		log.Printf("[Devices] Failed to watch device map: %v", err)
-		return
+		log.Printf("[Devices] Failed to list existing devices: %v", err)
+	} else if rewatch {
+		for id := range m.started {
+			if _, ok := listed[id]; !ok {
+				log.Printf("[Devices] Device removed while watch was down: %s", id)
+				m.stopDevice(id)
+			}
+		}
+	}
+
+	select {
+	case <-m.ctx.Done():
+		return m.ctx.Err()
+	case err := <-streamErr:
+		if err != nil {
+			return err
+		}
 	}
 
 	for {
-		entry, err := stream.Next()
+		event, err := stream.Next()
 		if err != nil {
-			log.Printf("[Devices] Error in device stream: %v", err)
-			return
+			return err
 		}
-		log.Printf("[Devices] Device event: %s", entry.String())
-		dev := &Device{
-			ID:     entry.Key,
-			Driver: &FakeDriver{ID: entry.Key},
+		onEvent()
+
+		switch e := event.(type) {
+		case *_map.Inserted[string, string]:
+			log.Printf("[Devices] Device added: %s", e.Entry.Key)
+			m.startDevice(e.Entry.Key)
+		case *_map.Updated[string, string]:
+			// Config pushes update the entry; the device's election is already running
+			if _, ok := m.started[e.NewEntry.Key]; ok {
+				log.Printf("[Devices] Device updated: %s", e.NewEntry.Key)
+				continue
+			}
+			log.Printf("[Devices] Device updated before it was started: %s", e.NewEntry.Key)
+			m.startDevice(e.NewEntry.Key)
+		case *_map.Removed[string, string]:
+			log.Printf("[Devices] Device removed: %s", e.Entry.Key)
+			m.stopDevice(e.Entry.Key)
+		}
+	}
+}
+
+// list starts every device in the map and returns the set of listed IDs
+func (m *monitor) list(ctx context.Context) (map[string]struct{}, error) {
+	entries, err := m.driverMap.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]struct{})
+	for {
+		entry, err := entries.Next()
+		if err == io.EOF {
+			return listed, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		listed[entry.Key] = struct{}{}
+		if _, ok := m.started[entry.Key]; !ok {
+			log.Printf("[Devices] Restoring existing device: %s", entry.Key)
+			m.startDevice(entry.Key)
 		}
-		start(entry.Key, dev)
 	}
 }
+
+// startDevice builds the device with its registered driver. If the driver can't be read the device
+// isn't started, so the next update or re-list retries rather than running it with the wrong driver.
+func (m *monitor) startDevice(id string) {
+	if _, ok := m.started[id]; ok {
+		return
+	}
+	loadCtx, cancel := optimeout.WithTimeout(m.ctx)
+	dev, err := Load(loadCtx, id)
+	cancel()
+	if err != nil {
+		log.Printf("[Devices] Failed to load driver for %s, not starting it: %v", id, err)
+		return
+	}
+	devCtx, cancel := context.WithCancel(m.ctx)
+	m.started[id] = cancel
+	m.start(devCtx, id, dev)
+}
+
+func (m *monitor) stopDevice(id string) {
+	if cancel, ok := m.started[id]; ok {
+		cancel()
+		delete(m.started, id)
+	}
+	if err := m.stop(id); err != nil {
+		log.Printf("[Devices] Failed to stop election for %s: %v", id, err)
+	}
+}
+
+// driverMapName is the Atomix map holding each device's DriverInfo. It is kept apart from the device
+// map, whose value is the device's config, so pushing a config can't overwrite how the device is reached.
+const driverMapName = "device-driver"
+
+// DriverInfo is how a device is reached. Devices without one, such as those the experiments put
+// straight into the device map, use the FakeDriver.
+type DriverInfo struct {
+	Type    string `json:"type"`              // "fake" or "http"
+	Address string `json:"address,omitempty"` // device endpoint, required for the http driver
+}
+
+// NewDriver returns the driver described by info
+func NewDriver(id string, info DriverInfo) (Driver, error) {
+	switch info.Type {
+	case "", "fake":
+		return &FakeDriver{ID: id}, nil
+	case "http":
+		if info.Address == "" {
+			return nil, fmt.Errorf("address is required for the http driver")
+		}
+		return NewHTTPDriver(id, info.Address), nil
+	default:
+		return nil, fmt.Errorf("unknown driver: %s", info.Type)
+	}
+}
+
+// driverStore is the part of an Atomix map Register, Load and Unregister use on the driver map, and
+// the FakeDriver on the device map
+type driverStore interface {
+	Put(ctx context.Context, key string, value string, opts ..._map.PutOption) (*_map.Entry[string, string], error)
+	Get(ctx context.Context, key string, opts ..._map.GetOption) (*_map.Entry[string, string], error)
+	Remove(ctx context.Context, key string, opts ..._map.RemoveOption) (*_map.Entry[string, string], error)
+}
+
+func getDriverStore(ctx context.Context) (driverStore, error) {
+	driverMap, err := atomix.Map[string, string](driverMapName).
+		Codec(generic.Scalar[string]()).
+		Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver map: %w", err)
+	}
+	return driverMap, nil
+}
+
+// Register records the device's driver. Call it before adding the device to the device map, so the
+// Monitor finds the driver when the device shows up.
+func Register(ctx context.Context, id string, info DriverInfo) error {
+	store, err := getDriverStore(ctx)
+	if err != nil {
+		return err
+	}
+	return register(ctx, store, id, info)
+}
+
+func register(ctx context.Context, store driverStore, id string, info DriverInfo) error {
+	if _, err := NewDriver(id, info); err != nil {
+		return err
+	}
+	value, err := json.Marshal(info)
+	if err != nil {
+		return fmt.Errorf("failed to encode driver: %w", err)
+	}
+	if _, err := store.Put(ctx, id, string(value)); err != nil {
+		return fmt.Errorf("failed to register driver: %w", err)
+	}
+	return nil
+}
+
+// Load returns the device with its registered driver, or with the FakeDriver if none is registered
+func Load(ctx context.Context, id string) (*Device, error) {
+	store, err := getDriverStore(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return load(ctx, store, id)
+}
+
+func load(ctx context.Context, store driverStore, id string) (*Device, error) {
+	var info DriverInfo
+	entry, err := store.Get(ctx, id)
+	switch {
+	case errors.IsNotFound(err) || (err == nil && entry == nil):
+	case err != nil:
+		return nil, fmt.Errorf("failed to read driver: %w", err)
+	default:
+		if err := json.Unmarshal([]byte(entry.Value), &info); err != nil {
+			return nil, fmt.Errorf("failed to decode driver %q: %w", entry.Value, err)
+		}
+	}
+
+	driver, err := NewDriver(id, info)
+	if err != nil {
+		return nil, err
+	}
+	return NewDevice(id, driver), nil
+}
+
+// Unregister forgets the device's driver; a device without one is not an error
+func Unregister(ctx context.Context, id string) error {
+	store, err := getDriverStore(ctx)
+	if err != nil {
+		return err
+	}
+	return unregister(ctx, store, id)
+}
+
+func unregister(ctx context.Context, store driverStore, id string) error {
+	if _, err := store.Remove(ctx, id); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to unregister driver: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package device
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/atomix/go-sdk/pkg/primitive"
+	_map "github.com/atomix/go-sdk/pkg/primitive/map"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+)
+
+// memDriverStore stands in for the Atomix driver map
+type memDriverStore struct {
+	entries map[string]string
+}
+
+func newMemDriverStore() *memDriverStore {
+	return &memDriverStore{entries: make(map[string]string)}
+}
+
+func (s *memDriverStore) Put(_ context.Context, key string, value string, _ ..._map.PutOption) (*_map.Entry[string, string], error) {
+	s.entries[key] = value
+	return &_map.Entry[string, string]{Key: key, Versioned: primitive.Versioned[string]{Value: value}}, nil
+}
+
+func (s *memDriverStore) Get(_ context.Context, key string, _ ..._map.GetOption) (*_map.Entry[string, string], error) {
+	value, ok := s.entries[key]
+	if !ok {
+		return nil, errors.NewNotFound("key %s not found", key)
+	}
+	return &_map.Entry[string, string]{Key: key, Versioned: primitive.Versioned[string]{Value: value}}, nil
+}
+
+func (s *memDriverStore) Remove(_ context.Context, key string, _ ..._map.RemoveOption) (*_map.Entry[string, string], error) {
+	value, ok := s.entries[key]
+	if !ok {
+		return nil, errors.NewNotFound("key %s not found", key)
+	}
+	delete(s.entries, key)
+	return &_map.Entry[string, string]{Key: key, Versioned: primitive.Versioned[string]{Value: value}}, nil
+}
+
+// TestHTTPDeviceAddressSurvivesApplyConfig checks that pushing a config to an http device reaches the
+// device and leaves its registered address alone, so the next load still reaches the same device
+func TestHTTPDeviceAddressSurvivesApplyConfig(t *testing.T) {
+	var mu sync.Mutex
+	var pushed []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/config" {
+			http.NotFound(w, r)
+			return
+		}
+		var config map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		pushed = append(pushed, config)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	store := newMemDriverStore()
+	if err := register(ctx, store, "device-1", DriverInfo{Type: "http", Address: srv.URL}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	configs := []map[string]string{
+		{},
+		{"flow": "allow all", "version": "1"},
+		{"flow": "deny all", "version": "2"},
+	}
+	for _, config := range configs {
+		dev, err := load(ctx, store, "device-1")
+		if err != nil {
+			t.Fatalf("load: %v", err)
+		}
+		driver, ok := dev.Driver.(*HTTPDriver)
+		if !ok {
+			t.Fatalf("loaded driver is %T, want *HTTPDriver", dev.Driver)
+		}
+		if driver.Address != srv.URL {
+			t.Fatalf("loaded address = %q, want %q", driver.Address, srv.URL)
+		}
+		if err := dev.ApplyConfig(ctx, config); err != nil {
+			t.Fatalf("ApplyConfig(%v): %v", config, err)
+		}
+	}
+
+	if !reflect.DeepEqual(pushed, configs) {
+		t.Errorf("device received %v, want %v", pushed, configs)
+	}
+	var info DriverInfo
+	if err := json.Unmarshal([]byte(store.entries["device-1"]), &info); err != nil {
+		t.Fatalf("decoding stored driver %q: %v", store.entries["device-1"], err)
+	}
+	if want := (DriverInfo{Type: "http", Address: srv.URL}); info != want {
+		t.Errorf("stored driver = %+v after ApplyConfig, want %+v", info, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	store := newMemDriverStore()
+	if err := register(ctx, store, "fake-1", DriverInfo{Type: "fake"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	store.entries["corrupt"] = "10.0.0.1:8080"
+
+	tests := []struct {
+		id      string
+		want    Driver
+		wantErr bool
+	}{
+		{id: "fake-1", want: &FakeDriver{ID: "fake-1"}},
+		// Devices put straight into the device map have no registered driver
+		{id: "unregistered", want: &FakeDriver{ID: "unregistered"}},
+		{id: "corrupt", wantErr: true},
+	}
+	for _, tt := range tests {
+		dev, err := load(ctx, store, tt.id)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("load(%s) succeeded, want an error", tt.id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("load(%s): %v", tt.id, err)
+			continue
+		}
+		if dev.ID != tt.id || !reflect.DeepEqual(dev.Driver, tt.want) {
+			t.Errorf("load(%s) = %+v, want driver %+v", tt.id, dev, tt.want)
+		}
+	}
+}
+
+func TestRegisterRejectsInvalidDriver(t *testing.T) {
+	ctx := context.Background()
+	store := newMemDriverStore()
+	for _, info := range []DriverInfo{{Type: "http"}, {Type: "serial", Address: "/dev/ttyS0"}} {
+		if err := register(ctx, store, "device-1", info); err == nil {
+			t.Errorf("register(%+v) succeeded, want an error", info)
+		}
+	}
+	if len(store.entries) != 0 {
+		t.Errorf("invalid drivers were stored: %v", store.entries)
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	ctx := context.Background()
+	store := newMemDriverStore()
+	if err := register(ctx, store, "device-1", DriverInfo{Type: "http", Address: "10.0.0.1:8080"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := unregister(ctx, store, "device-1"); err != nil {
+			t.Errorf("unregister #%d: %v", i+1, err)
+		}
+	}
+	if _, ok := store.entries["device-1"]; ok {
+		t.Error("driver still registered after unregister")
+	}
+}
@@ -0,0 +1,101 @@
+// Package events keeps the controller's most recent log lines in memory so they
+// can be inspected over the API without tailing the pod's logs.
+package events
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultSize = 500
+
+const (
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+}
+
+var (
+	mu     sync.Mutex
+	events = make([]Event, DefaultSize)
+	next   int // index the next event is written to
+	count  int
+)
+
+// SetSize resizes the buffer, discarding anything recorded so far. It should be called once at startup.
+func SetSize(n int) {
+	mu.Lock()
+	defer mu.Unlock()
+	events = make([]Event, n)
+	next = 0
+	count = 0
+}
+
+// Record adds an event, overwriting the oldest one once the buffer is full.
+func Record(level, message string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) == 0 {
+		return
+	}
+	events[next] = Event{Timestamp: time.Now(), Level: level, Message: message}
+	next = (next + 1) % len(events)
+	if count < len(events) {
+		count++
+	}
+}
+
+// Recent returns up to limit of the newest events, oldest first. A limit <= 0 returns everything buffered.
+func Recent(limit int) []Event {
+	mu.Lock()
+	defer mu.Unlock()
+	n := count
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	out := make([]Event, n)
+	start := next - n + len(events)
+	for i := range out {
+		out[i] = events[(start+i)%len(events)]
+	}
+	return out
+}
+
+// Writer feeds every line written through the standard logger into the buffer. Install it
+// alongside the normal output with log.SetOutput(io.MultiWriter(os.Stderr, events.Writer{})).
+type Writer struct{}
+
+// stdPrefix is the date and time log.LstdFlags puts in front of each line
+const stdPrefix = "2006/01/02 15:04:05 "
+
+func (Writer) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		if len(line) >= len(stdPrefix) {
+			if _, err := time.Parse(stdPrefix, line[:len(stdPrefix)]); err == nil {
+				line = line[len(stdPrefix):]
+			}
+		}
+		Record(levelOf(line), line)
+	}
+	return len(p), nil
+}
+
+// levelOf guesses a level from the message, since the controller's log lines don't carry one
+func levelOf(message string) string {
+	lower := strings.ToLower(message)
+	switch {
+	case strings.Contains(lower, "fail"), strings.Contains(lower, "error"), strings.Contains(lower, "unreachable"):
+		return LevelError
+	case strings.Contains(lower, "dropping"), strings.Contains(lower, "expired"), strings.Contains(lower, "lost"),
+		strings.Contains(lower, "retrying"), strings.Contains(lower, "not set"), strings.Contains(lower, "term_gap"):
+		return LevelWarn
+	}
+	return LevelInfo
+}
@@ -2,99 +2,596 @@ package leadership
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
 	"log"
 	"prototype/controller/device"
+	"prototype/controller/metrics"
+	"prototype/controller/optimeout"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/atomix/go-sdk/pkg/atomix"
 	"github.com/atomix/go-sdk/pkg/generic"
 	"github.com/atomix/go-sdk/pkg/primitive/election"
+	"github.com/atomix/runtime/sdk/pkg/errors"
 )
 
 type activeElection struct {
 	cancel   context.CancelFunc
 	election election.Election
+	term     *election.Term // latest term observed in runElection, guarded by ElectionManager.mu
+	onLost   LeadershipLostFunc
+	wins     map[string]int // terms won per candidate since the election started, guarded by ElectionManager.mu
+
+	rewatchAfter int
+}
+
+// LeadershipLostFunc is called when this host is deposed as a device's leader,
+// so it can stop or revert whatever it was applying to the device. It is not
+// called when the election is stopped.
+type LeadershipLostFunc func(ctx context.Context, dev *device.Device)
+
+// ElectionInfo is a snapshot of an active device election.
+type ElectionInfo struct {
+	DeviceID   string
+	Leader     string
+	Term       uint64
+	Candidates []string
+	Wins       map[string]int
+}
+
+type LeadershipEvent struct {
+	DeviceID string
+	Leader   string
+	Term     uint64
+	IsSelf   bool
 }
 
+const subscriberBufferSize = 16
+
+const (
+	streamRetryBackoff    = time.Second
+	maxStreamRetryBackoff = 30 * time.Second
+	// DefaultRewatchAfter is how many consecutive stream errors runElection tolerates before re-creating the Watch stream.
+	DefaultRewatchAfter = 5
+)
+
 type ElectionManager struct {
-	ctx      context.Context
-	hostname string
-	mu       sync.Mutex
-	active   map[string]activeElection
+	ctx         context.Context
+	candidateID string
+	priority    int
+	mu          sync.Mutex
+	active      map[string]*activeElection
+
+	subMu       sync.Mutex
+	subscribers map[<-chan LeadershipEvent]chan LeadershipEvent
+	subClosed   bool
+
+	onLost       LeadershipLostFunc // guarded by mu
+	rewatchAfter int                // guarded by mu
+	termGaps     int                // guarded by mu
+
+	newElection func(ctx context.Context, name, candidateID string) (election.Election, error) // getElection outside tests
 }
 
-func NewElectionManager(ctx context.Context, hostname string) *ElectionManager {
-	return &ElectionManager{
-		ctx:      ctx,
-		hostname: hostname,
-		active:   make(map[string]activeElection),
+// NewElectionManager creates a manager that enters every election as candidateID and
+// whose candidates are promoted up to priority places in each election queue on entry;
+// see promote. A priority of 0 keeps plain first-come ordering.
+func NewElectionManager(ctx context.Context, candidateID string, priority int) *ElectionManager {
+	m := &ElectionManager{
+		ctx:         ctx,
+		candidateID: candidateID,
+		priority:    priority,
+		active:      make(map[string]*activeElection),
+		subscribers: make(map[<-chan LeadershipEvent]chan LeadershipEvent),
+
+		rewatchAfter: DefaultRewatchAfter,
+		newElection:  getElection,
+	}
+	go func() {
+		<-ctx.Done()
+		m.closeSubscribers()
+	}()
+	return m
+}
+
+func getElection(ctx context.Context, name, candidateID string) (election.Election, error) {
+	return atomix.LeaderElection(name).
+		CandidateID(candidateID).
+		Get(ctx)
+}
+
+// Subscribe returns a channel of leadership changes. Events are dropped if the channel's buffer is full.
+func (m *ElectionManager) Subscribe() <-chan LeadershipEvent {
+	ch := make(chan LeadershipEvent, subscriberBufferSize)
+
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	if m.subClosed {
+		close(ch)
+		return ch
+	}
+	m.subscribers[ch] = ch
+	return ch
+}
+
+func (m *ElectionManager) Unsubscribe(sub <-chan LeadershipEvent) {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	if ch, ok := m.subscribers[sub]; ok {
+		delete(m.subscribers, sub)
+		close(ch)
 	}
 }
 
-func (m *ElectionManager) StartElection(deviceID string, dev *device.Device) {
+func (m *ElectionManager) publish(event LeadershipEvent) {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("[Leadership] Dropping leadership event for %s: subscriber buffer full", event.DeviceID)
+		}
+	}
+}
+
+func (m *ElectionManager) closeSubscribers() {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	m.subClosed = true
+	for sub, ch := range m.subscribers {
+		delete(m.subscribers, sub)
+		close(ch)
+	}
+}
+
+// SetLeadershipLostHandler sets the handler that elections started afterwards
+// call when this host loses leadership.
+func (m *ElectionManager) SetLeadershipLostHandler(fn LeadershipLostFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLost = fn
+}
+
+// SetRewatchAfter sets how many consecutive election stream errors cause
+// elections started afterwards to re-create their Watch stream.
+func (m *ElectionManager) SetRewatchAfter(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rewatchAfter = n
+}
+
+// StartElection joins the device's election in the background. The election runs
+// until ctx is canceled or StopElection is called.
+func (m *ElectionManager) StartElection(ctx context.Context, deviceID string, dev *device.Device) {
+	// Reserve the device under a single lock acquisition so concurrent callers
+	// cannot both create an election for it.
 	m.mu.Lock()
 	if _, exists := m.active[deviceID]; exists {
 		// Election already running
+		m.mu.Unlock()
 		return
 	}
+	ctx, cancel := context.WithCancel(ctx)
+	ae := &activeElection{cancel: cancel, onLost: m.onLost, wins: make(map[string]int), rewatchAfter: m.rewatchAfter}
+	m.active[deviceID] = ae
 	m.mu.Unlock()
 
-	ctx, cancel := context.WithCancel(m.ctx)
-	e, err := atomix.LeaderElection("election-" + dev.ID).
-		CandidateID(m.hostname).
-		Get(ctx)
+	getCtx, getCancel := optimeout.WithTimeout(ctx)
+	e, err := m.newElection(getCtx, "election-"+dev.ID, m.candidateID)
+	getCancel()
 	if err != nil {
 		log.Printf("[Election] (%s) Failed to create election: %v", dev.ID, err)
 		cancel()
+		m.mu.Lock()
+		if m.active[deviceID] == ae {
+			delete(m.active, deviceID)
+		}
+		m.mu.Unlock()
 		return
 	}
 
 	m.mu.Lock()
-	m.active[deviceID] = activeElection{
-		cancel:   cancel,
-		election: e,
+	if ctx.Err() != nil {
+		// Stopped while the election was being created
+		m.mu.Unlock()
+		closeCtx, closeCancel := optimeout.WithTimeout(m.ctx)
+		e.Close(closeCtx)
+		closeCancel()
+		return
 	}
+	ae.election = e
 	m.mu.Unlock()
 
-	go m.runElection(ctx, dev, e)
+	go m.runElection(ctx, dev, ae)
 }
 
-func (m *ElectionManager) StopElection(deviceID string) {
+const (
+	evictAttempts   = 3
+	evictRetryDelay = 500 * time.Millisecond
+	// evictVerifyAttempts is how many times evict re-reads the term to confirm the candidate is gone
+	evictVerifyAttempts = 5
+)
+
+var ErrCandidateNotEvicted = stderrors.New("candidate still present after evict")
+
+// StopElection cancels the device's election and evicts this host from it. The
+// evict runs on its own timeout derived from the manager's context, since the
+// election's context has just been canceled, and is retried on transient errors.
+// The election is forgotten once the evict is confirmed or the retries run out; the
+// last evict error is returned in the latter case.
+func (m *ElectionManager) StopElection(deviceID string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	ae, exists := m.active[deviceID]
+	if exists {
+		ae.cancel()
+	}
+	m.mu.Unlock()
+	if !exists {
+		return nil
+	}
+
+	var err error
+	if ae.election != nil {
+		err = m.evict(m.ctx, ae.election, m.candidateID, deviceID)
+	}
+
+	m.mu.Lock()
+	if m.active[deviceID] == ae {
+		delete(m.active, deviceID)
+	}
+	m.mu.Unlock()
+	return err
+}
+
+// evict removes candidateID from the election, retrying transient errors, and then
+// confirms via verifyEvicted that it is really gone.
+func (m *ElectionManager) evict(ctx context.Context, e election.Election, candidateID, deviceID string) error {
+	var err error
+	for attempt := 1; attempt <= evictAttempts; attempt++ {
+		opCtx, cancel := optimeout.WithTimeout(ctx)
+		_, err = e.Evict(opCtx, candidateID)
+		cancel()
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		if err == nil {
+			return m.verifyEvicted(ctx, e, candidateID, deviceID)
+		}
+		if !isTransient(err) {
+			break
+		}
+		log.Printf("[Leadership] Evicting %s from election %s failed (attempt %d/%d): %v", candidateID, deviceID, attempt, evictAttempts, err)
+		if attempt < evictAttempts {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("failed to evict %s from election %s: %w", candidateID, deviceID, err)
+			case <-time.After(evictRetryDelay):
+			}
+		}
+	}
+	return fmt.Errorf("failed to evict %s from election %s: %w", candidateID, deviceID, err)
+}
+
+// verifyEvicted re-reads the term until candidateID is no longer a candidate,
+// re-issuing the evict each time it is still listed. A ghost candidate left by
+// a silently failed evict could otherwise still win the election.
+func (m *ElectionManager) verifyEvicted(ctx context.Context, e election.Election, candidateID, deviceID string) error {
+	for attempt := 1; attempt <= evictVerifyAttempts; attempt++ {
+		opCtx, cancel := optimeout.WithTimeout(ctx)
+		term, err := e.GetTerm(opCtx)
+		cancel()
+		switch {
+		case errors.IsNotFound(err):
+			return nil
+		case err != nil && !isTransient(err):
+			return fmt.Errorf("failed to verify eviction of %s from election %s: %w", candidateID, deviceID, err)
+		case err == nil && !contains(term.Candidates, candidateID):
+			return nil
+		case err == nil:
+			log.Printf("[Leadership] %s still a candidate in election %s after evict (check %d/%d)", candidateID, deviceID, attempt, evictVerifyAttempts)
+			evictCtx, evictCancel := optimeout.WithTimeout(ctx)
+			e.Evict(evictCtx, candidateID)
+			evictCancel()
+		}
+		if attempt < evictVerifyAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(evictRetryDelay):
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s in election %s", ErrCandidateNotEvicted, candidateID, deviceID)
+}
+
+// MemberCandidate picks the candidate ID a controller pod entered an election with. A pod
+// on the host network has the node's hostname rather than its own name, so with a UID any
+// candidate carrying that UID matches.
+func MemberCandidate(candidates []string, name, uid string) string {
+	if uid != "" {
+		for _, id := range candidates {
+			if strings.HasSuffix(id, "-"+uid) {
+				return id
+			}
+		}
+	}
+	return DefaultCandidateID(name, uid)
+}
+
+func isTransient(err error) bool {
+	return errors.IsUnavailable(err) || errors.IsTimeout(err) || stderrors.Is(err, context.DeadlineExceeded)
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// stepDownTimeout bounds how long StepDown waits for another candidate to take over.
+const stepDownTimeout = 15 * time.Second
+
+var (
+	ErrNoElection      = stderrors.New("no active election for device")
+	ErrNotLeader       = stderrors.New("this host is not the device's leader")
+	ErrStepDownTimeout = stderrors.New("timed out waiting for a new leader")
+)
+
+// StepDown hands leadership of the device to another candidate: this host is
+// evicted from the election and immediately re-enters at the back of the queue.
+// It returns the leader observed after the handoff. If no new leader is seen
+// within stepDownTimeout, the current leader (possibly empty) is returned along
+// with ErrStepDownTimeout. A host that is the only candidate wins again, and is
+// returned as the new leader.
+func (m *ElectionManager) StepDown(deviceID string) (string, error) {
+	m.mu.Lock()
+	ae, exists := m.active[deviceID]
+	if !exists || ae.election == nil {
+		m.mu.Unlock()
+		return "", ErrNoElection
+	}
+	if ae.term == nil || ae.term.Leader != ae.election.CandidateID() {
+		m.mu.Unlock()
+		return "", ErrNotLeader
+	}
+	e := ae.election
+	m.mu.Unlock()
 
-	if ae, exists := m.active[deviceID]; exists {
+	// Subscribe before evicting so the handoff can't be missed
+	sub := m.Subscribe()
+	defer m.Unsubscribe(sub)
+
+	log.Printf("[Leadership] (%s) Stepping down as leader", e.Name())
+	if err := m.evict(m.ctx, e, m.candidateID, deviceID); err != nil {
+		return "", err
+	}
+	enterCtx, cancel := optimeout.WithTimeout(m.ctx)
+	_, err := e.Enter(enterCtx)
+	cancel()
+	if err != nil {
+		return "", fmt.Errorf("failed to re-enter election %s after stepping down: %w", deviceID, err)
+	}
+
+	timeout := time.NewTimer(stepDownTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case event, ok := <-sub:
+			if !ok {
+				return "", m.ctx.Err()
+			}
+			if event.DeviceID == deviceID && event.Leader != "" {
+				log.Printf("[Leadership] (%s) Stepped down, new leader: %s", e.Name(), event.Leader)
+				return event.Leader, nil
+			}
+		case <-timeout.C:
+			leader, _ := m.GetLeader(deviceID)
+			return leader, ErrStepDownTimeout
+		}
+	}
+}
+
+// DefaultCandidateID is the candidate ID a controller pod uses unless CANDIDATE_ID
+// overrides it: the hostname, suffixed with the pod UID when that is known so that
+// pods sharing a hostname (e.g. with hostNetwork) or a restarted pod don't collide.
+func DefaultCandidateID(hostname, podUID string) string {
+	if podUID == "" {
+		return hostname
+	}
+	return hostname + "-" + podUID
+}
+
+// StopAllElectionsForMember evicts a departed controller pod from every active
+// election and verifies each evict. The pod is assumed to have used its default
+// candidate ID; one started with CANDIDATE_ID is left for Atomix to drop once its
+// session expires. The elections are snapshotted first so the retries don't hold
+// the manager's lock.
+func (m *ElectionManager) StopAllElectionsForMember(name, uid string) {
+	type target struct {
+		election    election.Election
+		candidateID string
+	}
+	m.mu.Lock()
+	targets := make(map[string]target, len(m.active))
+	for deviceID, ae := range m.active {
+		if ae.election != nil {
+			var candidates []string
+			if ae.term != nil {
+				candidates = ae.term.Candidates
+			}
+			targets[deviceID] = target{ae.election, MemberCandidate(candidates, name, uid)}
+		}
+	}
+	m.mu.Unlock()
+
+	for deviceID, t := range targets {
+		e, candidateID := t.election, t.candidateID
+		log.Printf("[Leadership] Evicting %s from election %s", candidateID, deviceID)
+		if err := m.evict(m.ctx, e, candidateID, deviceID); err != nil {
+			log.Printf("[Leadership] Failed to evict %s from election %s: %v", candidateID, deviceID, err)
+		}
+	}
+}
+
+// EvictAll stops every active election and evicts this host from each of them
+// concurrently, so the other candidates take over without waiting for this
+// host's session to time out. It is meant for shutdown: ctx bounds the whole
+// drain, and the elections are forgotten even if their evict fails. The evict
+// errors are returned joined.
+func (m *ElectionManager) EvictAll(ctx context.Context) error {
+	m.mu.Lock()
+	active := m.active
+	m.active = make(map[string]*activeElection)
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	var errsMu sync.Mutex
+	var errs []error
+	for deviceID, ae := range active {
 		ae.cancel()
-		if _, err := ae.election.Evict(m.ctx, m.hostname); err != nil {
-			log.Printf("[Leadership] Failed to evict %s from election %s: %v", m.hostname, deviceID, err)
+		if ae.election == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(deviceID string, e election.Election) {
+			defer wg.Done()
+			if err := m.evict(ctx, e, m.candidateID, deviceID); err != nil {
+				errsMu.Lock()
+				errs = append(errs, err)
+				errsMu.Unlock()
+			}
+		}(deviceID, ae.election)
+	}
+	wg.Wait()
+	return stderrors.Join(errs...)
+}
+
+// IsLeader reports whether this host leads the election for the given device,
+// based on the most recent term observed by runElection.
+func (m *ElectionManager) IsLeader(deviceID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ae, exists := m.active[deviceID]
+	if !exists || ae.term == nil {
+		return false
+	}
+	return ae.term.Leader == ae.election.CandidateID()
+}
+
+// LeaderCount returns how many active elections this host currently leads.
+func (m *ElectionManager) LeaderCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := 0
+	for _, ae := range m.active {
+		if ae.term != nil && ae.term.Leader == ae.election.CandidateID() {
+			count++
 		}
-		delete(m.active, deviceID)
 	}
+	return count
 }
 
-func (m *ElectionManager) StopAllElectionsForHostname(hostname string) {
+// LeadershipDistribution returns how many active device elections each leader
+// currently leads, keyed by candidate ID. Devices
+// without an observed leader are left out.
+func (m *ElectionManager) LeadershipDistribution() map[string]int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	distribution := make(map[string]int)
+	for _, ae := range m.active {
+		if ae.term != nil && ae.term.Leader != "" {
+			distribution[ae.term.Leader]++
+		}
+	}
+	return distribution
+}
+
+// TermGaps returns how many times an election's term ID has skipped ahead by more than one or gone
+// backwards, across all elections since the controller started
+func (m *ElectionManager) TermGaps() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.termGaps
+}
+
+// ActiveCount returns how many device elections are running.
+func (m *ElectionManager) ActiveCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.active)
+}
+
+// GetLeader returns the current leader's candidate ID for the given device and
+// whether an election is active for it. The leader is empty until the first term
+// has been observed.
+func (m *ElectionManager) GetLeader(deviceID string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ae, exists := m.active[deviceID]
+	if !exists {
+		return "", false
+	}
+	if ae.term == nil {
+		return "", true
+	}
+	return ae.term.Leader, true
+}
+
+// Elections returns a snapshot of every active election, sorted by device ID.
+func (m *ElectionManager) Elections() []ElectionInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elections := make([]ElectionInfo, 0, len(m.active))
 	for deviceID, ae := range m.active {
-		log.Printf("[Leadership] Evicting %s from election %s", hostname, deviceID)
-		if _, err := ae.election.Evict(m.ctx, hostname); err != nil {
-			log.Printf("[Leadership] Failed to evict %s from election %s: %v", hostname, deviceID, err)
+		info := ElectionInfo{DeviceID: deviceID}
+		if ae.term != nil {
+			info.Leader = ae.term.Leader
+			info.Term = ae.term.ID
+			info.Candidates = append([]string(nil), ae.term.Candidates...)
+		}
+		if len(ae.wins) > 0 {
+			info.Wins = make(map[string]int, len(ae.wins))
+			for candidate, n := range ae.wins {
+				info.Wins[candidate] = n
+			}
 		}
+		elections = append(elections, info)
 	}
+	sort.Slice(elections, func(i, j int) bool {
+		return elections[i].DeviceID < elections[j].DeviceID
+	})
+	return elections
 }
 
-func (m *ElectionManager) runElection(ctx context.Context, dev *device.Device, e election.Election) {
+func (m *ElectionManager) runElection(ctx context.Context, dev *device.Device, ae *activeElection) {
+	e := ae.election
 	electionName := e.Name()
 
 	// Join election
-	if _, err := e.Enter(ctx); err != nil {
+	enterCtx, cancel := optimeout.WithTimeout(ctx)
+	term, err := e.Enter(enterCtx)
+	cancel()
+	if err != nil {
 		log.Printf("[Leadership] (%s) Failed to enter election: %v", electionName, err)
 		return
 	}
 	log.Printf("[Leadership] (%s) Entered election", electionName)
+	metrics.ElectionEntered()
+	m.promote(ctx, e, term)
 
 	// Watch election
 	stream, err := e.Watch(ctx)
@@ -104,16 +601,27 @@ func (m *ElectionManager) runElection(ctx context.Context, dev *device.Device, e
 	}
 
 	// Distributed config map
+	getCtx, cancel := optimeout.WithTimeout(ctx)
 	configMap, err := atomix.Map[string, string]("config").
 		Codec(generic.Scalar[string]()).
-		Get(ctx)
+		Get(getCtx)
+	cancel()
 	if err != nil {
 		log.Printf("[Leadership] (%s) Error accessing config map: %v", electionName, err)
 		return
 	}
-	defer configMap.Close(ctx)
+	defer func() {
+		closeCtx, cancel := optimeout.WithTimeout(context.Background())
+		defer cancel()
+		configMap.Close(closeCtx)
+	}()
 
 	var cache *election.Term
+	var lastTermID uint64 // latest term ID seen, including the one returned by Enter
+	if term != nil {
+		lastTermID = term.ID
+	}
+	failures := 0
 	for {
 		select {
 		case <-ctx.Done():
@@ -122,31 +630,119 @@ func (m *ElectionManager) runElection(ctx context.Context, dev *device.Device, e
 		default:
 		}
 
-		term, err := stream.Next()
+		term, err = stream.Next()
 		if err != nil {
-			log.Printf("[Leadership] (%s) Error in election stream: %v", electionName, err)
-			time.Sleep(time.Second)
+			if ctx.Err() != nil {
+				log.Printf("[Leadership] (%s) Stopping election", electionName)
+				return
+			}
+			failures++
+			log.Printf("[Leadership] (%s) Error in election stream (%d consecutive): %v", electionName, failures, err)
+
+			if failures >= ae.rewatchAfter {
+				log.Printf("[Leadership] (%s) Re-creating election watch after %d stream errors", electionName, failures)
+				newStream, err := e.Watch(ctx)
+				if err == nil {
+					stream = newStream
+					failures = 0
+					continue
+				}
+				log.Printf("[Leadership] (%s) Failed to re-watch election: %v", electionName, err)
+			}
+
+			backoff := streamBackoff(failures)
+			log.Printf("[Leadership] (%s) Retrying election stream in %v", electionName, backoff)
+			select {
+			case <-ctx.Done():
+				log.Printf("[Leadership] (%s) Stopping election", electionName)
+				return
+			case <-time.After(backoff):
+			}
 			continue
 		}
+		failures = 0
 
 		if cache == nil || cache.ID != term.ID {
 			log.Printf("[Leadership] (%s) New term: %d", electionName, term.ID)
 		}
+		// Terms should only ever advance one at a time; a skip means events were missed and a
+		// decrease means the election state went backwards
+		gap := lastTermID != 0 && (term.ID > lastTermID+1 || term.ID < lastTermID)
+		if gap {
+			log.Printf("[Leadership] (%s) ELECTION_TERM_GAP: term %d followed term %d", electionName, term.ID, lastTermID)
+		}
+		lastTermID = term.ID
 		if cache == nil || !reflect.DeepEqual(cache.Candidates, term.Candidates) {
 			log.Printf("[Leadership] (%s) Candidates: %v", electionName, term.Candidates)
 		}
 
 		if cache == nil || cache.Leader != term.Leader {
+			m.publish(LeadershipEvent{
+				DeviceID: dev.ID,
+				Leader:   term.Leader,
+				Term:     term.ID,
+				IsSelf:   term.Leader == e.CandidateID(),
+			})
 			if term.Leader == e.CandidateID() {
 				log.Printf("[Leadership] (%s) ✅ I am leader (term %d)", electionName, term.ID)
-				value := "leader " + m.hostname
-				_, _ = configMap.Put(ctx, electionName, value)
+				metrics.LeadershipWon()
+				value := "leader " + m.candidateID
+				putCtx, cancel := optimeout.WithTimeout(ctx)
+				_, _ = configMap.Put(putCtx, electionName, value)
+				cancel()
 				config := map[string]string{"flow": "allow all", "version": time.Now().Format(time.RFC3339)}
 				dev.ApplyConfig(ctx, config)
 			} else {
 				log.Printf("[Leadership] (%s) ℹ️ Current leader: %s", electionName, term.Leader)
+				if cache != nil && cache.Leader == e.CandidateID() {
+					log.Printf("[Leadership] (%s) Lost leadership to %s (term %d)", electionName, term.Leader, term.ID)
+					if ae.onLost != nil {
+						ae.onLost(ctx, dev)
+					}
+				}
 			}
 		}
+
+		m.mu.Lock()
+		if term.Leader != "" && (cache == nil || cache.Leader != term.Leader) {
+			ae.wins[term.Leader]++
+		}
+		if gap {
+			m.termGaps++
+		}
+		ae.term = term
+		m.mu.Unlock()
 		cache = term
 	}
 }
+
+// streamBackoff doubles streamRetryBackoff for each consecutive failure after the first, capped at maxStreamRetryBackoff.
+func streamBackoff(failures int) time.Duration {
+	backoff := streamRetryBackoff
+	for i := 1; i < failures && backoff < maxStreamRetryBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, maxStreamRetryBackoff)
+}
+
+// promote moves this host up to m.priority places towards the front of the
+// election's candidate queue. Atomix promotes one place per call, so a priority at
+// least as large as the host's queue position takes leadership from the current
+// leader. Promotion only happens on entry: a host that later loses leadership
+// stays in the queue at its new position and is not re-promoted until its
+// election is stopped and started again (e.g. the device is re-added).
+func (m *ElectionManager) promote(ctx context.Context, e election.Election, term *election.Term) {
+	for i := 0; i < m.priority && term != nil && term.Leader != e.CandidateID(); i++ {
+		promoteCtx, cancel := optimeout.WithTimeout(ctx)
+		next, err := e.Promote(promoteCtx, e.CandidateID())
+		cancel()
+		if err != nil {
+			log.Printf("[Leadership] (%s) Failed to promote candidate: %v", e.Name(), err)
+			return
+		}
+		term = next
+	}
+	if m.priority > 0 && term != nil {
+		log.Printf("[Leadership] (%s) Candidate queue after promotion: %v", e.Name(), term.Candidates)
+	}
+}
@@ -0,0 +1,326 @@
+package leadership
+
+import (
+	"context"
+	stderrors "errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"prototype/controller/device"
+	"prototype/controller/optimeout"
+
+	"github.com/atomix/go-sdk/pkg/primitive/election"
+	"github.com/atomix/runtime/sdk/pkg/errors"
+)
+
+// fakeElection is an in-memory election shared by every candidate that opens it. Watch always
+// fails, so runElection stops after entering instead of reaching for the Atomix config map.
+type fakeElection struct {
+	name        string
+	candidateID string
+	state       *electionState
+}
+
+type electionState struct {
+	mu         sync.Mutex
+	candidates []string
+	// hang, set before the election is used, makes Evict block until its context is done, like a
+	// call stuck behind a partition
+	hang bool
+	// evictLag is how many term reads still list a candidate after its evict, like an evict that is
+	// only eventually consistent; lagging counts down the reads left per candidate
+	evictLag int
+	lagging  map[string]int
+	// ghostEvicts makes Evict report success without removing the candidate
+	ghostEvicts bool
+	evicts      int
+}
+
+func (s *electionState) open(name, candidateID string) *fakeElection {
+	return &fakeElection{name: name, candidateID: candidateID, state: s}
+}
+
+// term must be called with mu held
+func (s *electionState) term() *election.Term {
+	term := &election.Term{ID: 1, Candidates: append([]string(nil), s.candidates...)}
+	if len(s.candidates) > 0 {
+		term.Leader = s.candidates[0]
+	}
+	return term
+}
+
+func (e *fakeElection) Name() string {
+	return e.name
+}
+
+func (e *fakeElection) Close(context.Context) error {
+	return nil
+}
+
+func (e *fakeElection) CandidateID() string {
+	return e.candidateID
+}
+
+func (e *fakeElection) GetTerm(context.Context) (*election.Term, error) {
+	e.state.mu.Lock()
+	defer e.state.mu.Unlock()
+	term := e.state.term()
+	for id, reads := range e.state.lagging {
+		if reads > 0 {
+			term.Candidates = append(term.Candidates, id)
+			e.state.lagging[id]--
+		}
+	}
+	return term, nil
+}
+
+func (e *fakeElection) Enter(context.Context) (*election.Term, error) {
+	e.state.mu.Lock()
+	defer e.state.mu.Unlock()
+	if !contains(e.state.candidates, e.candidateID) {
+		e.state.candidates = append(e.state.candidates, e.candidateID)
+	}
+	return e.state.term(), nil
+}
+
+func (e *fakeElection) Leave(ctx context.Context) (*election.Term, error) {
+	return e.Evict(ctx, e.candidateID)
+}
+
+func (e *fakeElection) Anoint(context.Context, string) (*election.Term, error) {
+	return nil, errors.NewNotSupported("anoint")
+}
+
+func (e *fakeElection) Promote(context.Context, string) (*election.Term, error) {
+	return nil, errors.NewNotSupported("promote")
+}
+
+func (e *fakeElection) Evict(ctx context.Context, id string) (*election.Term, error) {
+	if e.state.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	e.state.mu.Lock()
+	defer e.state.mu.Unlock()
+	e.state.evicts++
+	for i, candidate := range e.state.candidates {
+		if candidate == id {
+			if e.state.ghostEvicts {
+				return e.state.term(), nil
+			}
+			e.state.candidates = append(e.state.candidates[:i:i], e.state.candidates[i+1:]...)
+			if e.state.evictLag > 0 {
+				if e.state.lagging == nil {
+					e.state.lagging = make(map[string]int)
+				}
+				e.state.lagging[id] = e.state.evictLag
+			}
+			return e.state.term(), nil
+		}
+	}
+	// A lagging candidate is already gone, so evicting it again succeeds
+	if e.state.lagging[id] > 0 {
+		return e.state.term(), nil
+	}
+	return nil, errors.NewNotFound("candidate %s not found", id)
+}
+
+func (s *electionState) snapshot() (candidates []string, evicts int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.candidates...), s.evicts
+}
+
+func (e *fakeElection) Watch(context.Context) (election.TermStream, error) {
+	return nil, errors.NewNotSupported("watch")
+}
+
+// TestStartElectionConcurrent checks that concurrent StartElection calls for one device register a
+// single election and open a single Atomix election between them
+func TestStartElectionConcurrent(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	state := &electionState{}
+	var opened atomic.Int32
+	m := NewElectionManager(ctx, "controller-1", 0)
+	m.newElection = func(_ context.Context, name, candidateID string) (election.Election, error) {
+		opened.Add(1)
+		// Widen the window between the existence check and the registration
+		time.Sleep(10 * time.Millisecond)
+		return state.open(name, candidateID), nil
+	}
+
+	dev := device.NewDevice("device-1", &device.FakeDriver{ID: "device-1"})
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			m.StartElection(ctx, dev.ID, dev)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if n := opened.Load(); n != 1 {
+		t.Errorf("%d elections opened, want 1", n)
+	}
+	m.mu.Lock()
+	ae, ok := m.active[dev.ID]
+	n := len(m.active)
+	m.mu.Unlock()
+	if n != 1 || !ok || ae.election == nil {
+		t.Fatalf("%d active elections registered, want only %s with its election set", n, dev.ID)
+	}
+}
+
+// TestEvictHungOperation checks that an Evict that never returns is cut off by the operation
+// timeout, so stopping an election during a partition doesn't block forever
+func TestEvictHungOperation(t *testing.T) {
+	optimeout.Set(20 * time.Millisecond)
+	t.Cleanup(func() { optimeout.Set(optimeout.DefaultTimeout) })
+
+	state := &electionState{candidates: []string{"controller-1"}, hang: true}
+	m := NewElectionManager(context.Background(), "controller-1", 0)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.evict(context.Background(), state.open("election-device-1", "controller-1"), "controller-1", "device-1")
+	}()
+	select {
+	case err := <-done:
+		if !stderrors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("evict = %v, want a deadline error", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("evict blocked on a hung Evict")
+	}
+}
+
+// TestStopElectionEventualEvict checks that StopElection waits out an evict that takes a few reads
+// to show up in the term, rather than trusting the first successful Evict
+func TestStopElectionEventualEvict(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	state := &electionState{candidates: []string{"controller-2"}, evictLag: 2}
+	m := NewElectionManager(ctx, "controller-1", 0)
+	m.newElection = func(_ context.Context, name, candidateID string) (election.Election, error) {
+		return state.open(name, candidateID), nil
+	}
+	dev := device.NewDevice("device-1", &device.FakeDriver{ID: "device-1"})
+	m.StartElection(ctx, dev.ID, dev)
+
+	// runElection enters the election in the background
+	waitForCandidates(t, state, "controller-2", "controller-1")
+
+	if err := m.StopElection(dev.ID); err != nil {
+		t.Fatalf("StopElection: %v", err)
+	}
+	term, _ := state.open("election-device-1", "controller-2").GetTerm(ctx)
+	if contains(term.Candidates, "controller-1") {
+		t.Errorf("controller-1 still a candidate after StopElection: %v", term.Candidates)
+	}
+	if _, evicts := state.snapshot(); evicts < 2 {
+		t.Errorf("%d evicts, want the evict re-issued while the candidate was still listed", evicts)
+	}
+	if _, ok := m.GetLeader(dev.ID); ok {
+		t.Error("election still active after StopElection")
+	}
+}
+
+// TestEvictGhostCandidate checks that an evict which never takes effect is reported rather than
+// leaving a ghost candidate that could still win
+func TestEvictGhostCandidate(t *testing.T) {
+	state := &electionState{candidates: []string{"controller-1", "controller-2"}, ghostEvicts: true}
+	m := NewElectionManager(context.Background(), "controller-2", 0)
+
+	err := m.evict(context.Background(), state.open("election-device-1", "controller-2"), "controller-1", "device-1")
+	if !stderrors.Is(err, ErrCandidateNotEvicted) {
+		t.Errorf("evict = %v, want %v", err, ErrCandidateNotEvicted)
+	}
+	if _, evicts := state.snapshot(); evicts != evictVerifyAttempts+1 {
+		t.Errorf("%d evicts, want the first plus one per verification", evicts)
+	}
+}
+
+// waitForCandidates waits until the election's candidates are exactly want, in order
+func waitForCandidates(t *testing.T, state *electionState, want ...string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		candidates, _ := state.snapshot()
+		if reflect.DeepEqual(candidates, want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("candidates = %v, want %v", candidates, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestDistinctCandidateIDs runs two controllers sharing a hostname, as with hostNetwork, and checks
+// that they enter the election as separate candidates and each only ever evicts itself
+func TestDistinctCandidateIDs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	idA, idB := DefaultCandidateID("node-1", "uid-a"), DefaultCandidateID("node-1", "uid-b")
+	if idA == idB {
+		t.Fatalf("pods with different UIDs on one host share candidate ID %s", idA)
+	}
+
+	state := &electionState{}
+	open := func(_ context.Context, name, candidateID string) (election.Election, error) {
+		return state.open(name, candidateID), nil
+	}
+	a, b := NewElectionManager(ctx, idA, 0), NewElectionManager(ctx, idB, 0)
+	a.newElection, b.newElection = open, open
+	dev := device.NewDevice("device-1", &device.FakeDriver{ID: "device-1"})
+
+	a.StartElection(ctx, dev.ID, dev)
+	waitForCandidates(t, state, idA)
+	b.StartElection(ctx, dev.ID, dev)
+	waitForCandidates(t, state, idA, idB)
+
+	if err := b.StopElection(dev.ID); err != nil {
+		t.Fatalf("StopElection: %v", err)
+	}
+	waitForCandidates(t, state, idA)
+	if _, ok := a.GetLeader(dev.ID); !ok {
+		t.Error("stopping the other controller's election stopped this one's")
+	}
+
+	// A departed pod is evicted by its own candidate ID, not by the hostname both pods share
+	b.StartElection(ctx, dev.ID, dev)
+	waitForCandidates(t, state, idA, idB)
+	a.StopAllElectionsForMember("node-1", "uid-b")
+	waitForCandidates(t, state, idA)
+}
+
+func TestMemberCandidate(t *testing.T) {
+	candidates := []string{"node-1-uid-a", "controller-2-uid-c", "controller-3"}
+	tests := []struct {
+		name, uid string
+		want      string
+	}{
+		{name: "controller-2", uid: "uid-c", want: "controller-2-uid-c"},
+		// On the host network the candidate carries the node's hostname, not the pod name
+		{name: "prototype-0", uid: "uid-a", want: "node-1-uid-a"},
+		{name: "controller-3", want: "controller-3"},
+		{name: "controller-4", uid: "uid-d", want: "controller-4-uid-d"},
+	}
+
+	for _, tt := range tests {
+		if got := MemberCandidate(candidates, tt.name, tt.uid); got != tt.want {
+			t.Errorf("MemberCandidate(%s, %q) = %s, want %s", tt.name, tt.uid, got, tt.want)
+		}
+	}
+}
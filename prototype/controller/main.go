@@ -2,15 +2,20 @@ package main
 
 import (
 	"context"
+	"io"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"prototype/controller/api"
 	"prototype/controller/device"
+	"prototype/controller/events"
 	"prototype/controller/leadership"
 	"prototype/controller/membership"
+	"prototype/controller/optimeout"
 )
 
 type Controller struct {
@@ -25,22 +30,125 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if v := os.Getenv("EVENT_BUFFER_SIZE"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			log.Fatalf("Invalid EVENT_BUFFER_SIZE %q: must be a positive integer", v)
+		}
+		events.SetSize(n)
+	}
+	log.SetOutput(io.MultiWriter(os.Stderr, events.Writer{}))
+
+	if v := os.Getenv("ATOMIX_OP_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("Invalid ATOMIX_OP_TIMEOUT %q: must be a positive duration", v)
+		}
+		optimeout.Set(d)
+	}
+
 	hostname, _ := os.Hostname()
-	electionManager := leadership.NewElectionManager(ctx, hostname)
+	candidateID := os.Getenv("CANDIDATE_ID")
+	if candidateID == "" {
+		candidateID = leadership.DefaultCandidateID(hostname, os.Getenv("POD_UID"))
+	}
+	log.Printf("Election candidate ID: %s", candidateID)
+	priority := 0
+	if v := os.Getenv("ELECTION_PRIORITY"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 0 {
+			log.Fatalf("Invalid ELECTION_PRIORITY %q: must be a non-negative integer", v)
+		}
+		priority = p
+	}
+	electionManager := leadership.NewElectionManager(ctx, candidateID, priority)
+	if v := os.Getenv("ELECTION_REWATCH_AFTER"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			log.Fatalf("Invalid ELECTION_REWATCH_AFTER %q: must be a positive integer", v)
+		}
+		electionManager.SetRewatchAfter(n)
+	}
 	go device.Monitor(ctx, electionManager.StartElection, electionManager.StopElection)
 
-	membershipManager, err := membership.NewMembershipManager(ctx)
+	namespace := os.Getenv("NAMESPACE")
+	if namespace == "" {
+		namespace = "default"
+	}
+	resyncPeriod := 30 * time.Second
+	if v := os.Getenv("MEMBERSHIP_RESYNC_PERIOD"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid MEMBERSHIP_RESYNC_PERIOD %q: %v", v, err)
+		}
+		resyncPeriod = d
+	}
+
+	membershipManager, err := membership.NewMembershipManager(ctx, namespace, resyncPeriod)
 	if err != nil {
 		log.Fatalf("Failed to create membership manager: %v", err)
 	}
-	go membershipManager.WatchControllers("name=prototype", electionManager.StopAllElectionsForHostname)
+	go membershipManager.WatchControllers("name=prototype", electionManager.StopAllElectionsForMember)
+
+	drainTimeout := 10 * time.Second
+	if v := os.Getenv("DRAIN_TIMEOUT"); v != "" {
+		drainTimeout, err = time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid DRAIN_TIMEOUT %q: %v", v, err)
+		}
+	}
+
+	shutdownTimeout := 10 * time.Second
+	if v := os.Getenv("SHUTDOWN_TIMEOUT"); v != "" {
+		shutdownTimeout, err = time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("Invalid SHUTDOWN_TIMEOUT %q: %v", v, err)
+		}
+	}
+
+	serverOpts := api.ServerOptions{
+		TLSCertFile: os.Getenv("API_TLS_CERT_FILE"),
+		TLSKeyFile:  os.Getenv("API_TLS_KEY_FILE"),
+		AuthToken:   os.Getenv("API_AUTH_TOKEN"),
+	}
+	if (serverOpts.TLSCertFile == "") != (serverOpts.TLSKeyFile == "") {
+		log.Fatalf("API_TLS_CERT_FILE and API_TLS_KEY_FILE must be set together")
+	}
+	if serverOpts.AuthToken == "" {
+		log.Printf("API_AUTH_TOKEN not set, mutating API requests are unauthenticated")
+	}
 
 	// Start HTTP server
-	go api.StartServer(ctx, membershipManager, ":8080")
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- api.StartServer(ctx, membershipManager, electionManager, ":8080", shutdownTimeout, serverOpts)
+	}()
 
 	// Wait for SIGTERM
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
-	<-sig
-	log.Println("Shutting down...")
+	select {
+	case <-sig:
+		log.Println("Shutting down...")
+		drain(electionManager, candidateID, drainTimeout)
+		cancel()
+		if err := <-serverErr; err != nil {
+			log.Printf("HTTP server shutdown error: %v", err)
+		}
+	case err := <-serverErr:
+		log.Fatalf("Failed to start HTTP server: %v", err)
+	}
+}
+
+// drain evicts this controller from its elections before the context is canceled,
+// so other controllers take over its devices straight away. Membership isn't left
+// here: other controllers see the departure when their informers observe this
+// pod's deletion.
+func drain(electionManager *leadership.ElectionManager, candidateID string, timeout time.Duration) {
+	log.Printf("Draining: evicting %s from %d elections (timeout %v)", candidateID, electionManager.ActiveCount(), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := electionManager.EvictAll(ctx); err != nil {
+		log.Printf("Drain incomplete: %v", err)
+	}
 }
@@ -15,13 +15,37 @@ import (
 )
 
 type MembershipManager struct {
-	ctx    context.Context
-	client *kubernetes.Clientset
-	mu     sync.Mutex
-	Active map[string]struct{}
+	ctx          context.Context
+	client       kubernetes.Interface
+	namespace    string
+	resyncPeriod time.Duration
+	mu           sync.Mutex
+	Active       map[string]struct{}
+	uids         map[string]string // pod UID per member, passed to onDelete
+	lastUpdated  time.Time
+	synced       chan struct{} // closed once the informer cache has synced
+
+	subMu       sync.Mutex
+	subscribers map[<-chan MembershipEvent]chan MembershipEvent
+	subClosed   bool
 }
 
-func NewMembershipManager(ctx context.Context) (*MembershipManager, error) {
+type MembershipEventType string
+
+const (
+	MemberAdded   MembershipEventType = "added"
+	MemberRemoved MembershipEventType = "removed"
+)
+
+type MembershipEvent struct {
+	Type   MembershipEventType `json:"type"`
+	Member string              `json:"member"`
+	Time   time.Time           `json:"time"`
+}
+
+const subscriberBufferSize = 16
+
+func NewMembershipManager(ctx context.Context, namespace string, resyncPeriod time.Duration) (*MembershipManager, error) {
 	config, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, err
@@ -31,20 +55,111 @@ func NewMembershipManager(ctx context.Context) (*MembershipManager, error) {
 	if err != nil {
 		return nil, err
 	}
+	return newMembershipManager(ctx, client, namespace, resyncPeriod), nil
+}
+
+func newMembershipManager(ctx context.Context, client kubernetes.Interface, namespace string, resyncPeriod time.Duration) *MembershipManager {
+	m := &MembershipManager{
+		client:       client,
+		ctx:          ctx,
+		namespace:    namespace,
+		resyncPeriod: resyncPeriod,
+		Active:       make(map[string]struct{}),
+		uids:         make(map[string]string),
+		synced:       make(chan struct{}),
+		subscribers:  make(map[<-chan MembershipEvent]chan MembershipEvent),
+	}
+	go func() {
+		<-ctx.Done()
+		m.closeSubscribers()
+	}()
+	return m
+}
+
+// Subscribe returns a channel of member additions and removals. Events are dropped if the channel's buffer is full.
+func (m *MembershipManager) Subscribe() <-chan MembershipEvent {
+	ch := make(chan MembershipEvent, subscriberBufferSize)
 
-	return &MembershipManager{
-		client: client,
-		ctx:    ctx,
-		Active: make(map[string]struct{}),
-	}, nil
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	if m.subClosed {
+		close(ch)
+		return ch
+	}
+	m.subscribers[ch] = ch
+	return ch
+}
+
+func (m *MembershipManager) Unsubscribe(sub <-chan MembershipEvent) {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	if ch, ok := m.subscribers[sub]; ok {
+		delete(m.subscribers, sub)
+		close(ch)
+	}
+}
+
+func (m *MembershipManager) publish(eventType MembershipEventType, member string) {
+	event := MembershipEvent{Type: eventType, Member: member, Time: time.Now()}
+
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	for _, ch := range m.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Printf("[Membership] Dropping membership event for %s: subscriber buffer full", member)
+		}
+	}
+}
+
+func (m *MembershipManager) closeSubscribers() {
+	m.subMu.Lock()
+	defer m.subMu.Unlock()
+	m.subClosed = true
+	for sub, ch := range m.subscribers {
+		delete(m.subscribers, sub)
+		close(ch)
+	}
 }
 
-// WatchControllers now uses an informer instead of a direct watch
-func (m *MembershipManager) WatchControllers(labelSelector string, onDelete func(string)) error {
+// Ready is closed once WatchControllers' informer cache has synced, so Active reflects the cluster
+func (m *MembershipManager) Ready() <-chan struct{} {
+	return m.synced
+}
+
+// LastUpdatedAt returns when the informer last reported a pod add, update or delete
+func (m *MembershipManager) LastUpdatedAt() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastUpdated
+}
+
+// Members returns the active members' names mapped to their pod UIDs
+func (m *MembershipManager) Members() map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	members := make(map[string]string, len(m.Active))
+	for name := range m.Active {
+		members[name] = m.uids[name]
+	}
+	return members
+}
+
+// Count returns the number of active members
+func (m *MembershipManager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.Active)
+}
+
+// WatchControllers now uses an informer instead of a direct watch. onDelete is given the
+// departed pod's name and UID.
+func (m *MembershipManager) WatchControllers(labelSelector string, onDelete func(name, uid string)) error {
 	factory := informers.NewSharedInformerFactoryWithOptions(
 		m.client,
-		30*time.Second, // resync period
-		informers.WithNamespace("default"),
+		m.resyncPeriod,
+		informers.WithNamespace(m.namespace),
 		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
 			opts.LabelSelector = labelSelector
 		}),
@@ -59,40 +174,67 @@ func (m *MembershipManager) WatchControllers(labelSelector string, onDelete func
 			defer m.mu.Unlock()
 
 			m.Active[pod.Name] = struct{}{}
+			m.uids[pod.Name] = string(pod.UID)
+			m.lastUpdated = time.Now()
 			log.Printf("[Membership] Pod added: %s", pod.Name)
+			m.publish(MemberAdded, pod.Name)
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldPod := oldObj.(*v1.Pod)
 			newPod := newObj.(*v1.Pod)
 			m.mu.Lock()
-			defer m.mu.Unlock()
 
-			if newPod.Status.Phase == v1.PodFailed || newPod.Status.Phase == v1.PodSucceeded {
+			// Periodic resyncs redeliver unchanged pods; don't count those as updates
+			if oldPod.ResourceVersion != newPod.ResourceVersion {
+				m.lastUpdated = time.Now()
+			}
+
+			terminated := newPod.Status.Phase == v1.PodFailed || newPod.Status.Phase == v1.PodSucceeded
+			_, wasActive := m.Active[newPod.Name]
+			if terminated && wasActive {
 				delete(m.Active, newPod.Name)
+				delete(m.uids, newPod.Name)
 				log.Printf("[Membership] Pod updated to terminated state: %s", newPod.Name)
-				onDelete(newPod.Name)
+				m.publish(MemberRemoved, newPod.Name)
+			}
+			m.mu.Unlock()
+
+			// onDelete evicts the member from every election, which can take seconds, so it runs
+			// without the lock. Resyncs redeliver terminated pods, which are only handled once.
+			if terminated && wasActive {
+				onDelete(newPod.Name, string(newPod.UID))
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
 			pod := obj.(*v1.Pod)
 			m.mu.Lock()
-			defer m.mu.Unlock()
-
+			_, wasActive := m.Active[pod.Name]
 			delete(m.Active, pod.Name)
+			delete(m.uids, pod.Name)
+			m.lastUpdated = time.Now()
 			log.Printf("[Membership] Pod deleted: %s", pod.Name)
-			onDelete(pod.Name)
+			if wasActive {
+				m.publish(MemberRemoved, pod.Name)
+			}
+			m.mu.Unlock()
+
+			// A pod already handled as terminated was evicted then
+			if wasActive {
+				onDelete(pod.Name, string(pod.UID))
+			}
 		},
 	})
 
-	stopCh := make(chan struct{})
-	defer close(stopCh)
-
-	log.Printf("[Membership] Starting informer for Pods with selector: %s", labelSelector)
-	factory.Start(stopCh)
+	log.Printf("[Membership] Starting informer for Pods in namespace %s with selector: %s", m.namespace, labelSelector)
+	factory.Start(m.ctx.Done())
+	// Informers stop once the context is done; Shutdown waits for their goroutines to exit
+	defer factory.Shutdown()
 
 	// Wait for cache sync before processing events
 	if !cache.WaitForCacheSync(m.ctx.Done(), podInformer.HasSynced) {
 		return context.Canceled
 	}
+	close(m.synced)
 
 	// Block until context is canceled
 	<-m.ctx.Done()
@@ -0,0 +1,158 @@
+package membership
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testSelector = "name=prototype"
+
+func newPod(namespace, name string, labels map[string]string) *v1.Pod {
+	return &v1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: namespace,
+		Name:      name,
+		UID:       types.UID(name + "-uid"),
+		Labels:    labels,
+	}}
+}
+
+// watch runs WatchControllers until the test ends and waits for its cache to sync
+func watch(t *testing.T, m *MembershipManager, onDelete func(name, uid string)) {
+	t.Helper()
+	go m.WatchControllers(testSelector, onDelete)
+	select {
+	case <-m.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("membership informer didn't sync")
+	}
+}
+
+func TestWatchControllersNamespace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	prototype := map[string]string{"name": "prototype"}
+	client := fake.NewClientset(
+		newPod("controllers", "prototype-0", prototype),
+		newPod("controllers", "prototype-1", prototype),
+		newPod("controllers", "other-0", map[string]string{"name": "other"}),
+		newPod("default", "prototype-default", prototype),
+	)
+	m := newMembershipManager(ctx, client, "controllers", time.Minute)
+	watch(t, m, func(name, uid string) {})
+
+	want := map[string]string{"prototype-0": "prototype-0-uid", "prototype-1": "prototype-1-uid"}
+	if got := m.Members(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Members = %v, want %v", got, want)
+	}
+}
+
+// TestWatchControllersShutdown checks that WatchControllers returns promptly once its context is
+// canceled and leaves none of the informer's goroutines behind
+func TestWatchControllersShutdown(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := fake.NewClientset(newPod("default", "prototype-0", map[string]string{"name": "prototype"}))
+	m := newMembershipManager(ctx, client, "default", time.Minute)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.WatchControllers(testSelector, func(name, uid string) {})
+	}()
+	select {
+	case <-m.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("membership informer didn't sync")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("WatchControllers = %v, want nil after cancellation", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchControllers didn't return after its context was canceled")
+	}
+
+	// Goroutines can take a moment to finish exiting after Shutdown returns
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("%d goroutines running after shutdown, %d before the manager started", after, before)
+	}
+}
+
+// TestWatchControllersOnDelete checks that a departed pod is handed to onDelete once, however often
+// the informer redelivers it, and without the manager's lock held, since evicting it is slow
+func TestWatchControllersOnDelete(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewClientset(newPod("default", "prototype-0", map[string]string{"name": "prototype"}))
+	m := newMembershipManager(ctx, client, "default", time.Minute)
+	var deletes atomic.Int32
+	deleted := make(chan string, 4)
+	watch(t, m, func(name, uid string) {
+		// Would deadlock if onDelete ran under the lock
+		m.Count()
+		deletes.Add(1)
+		deleted <- uid
+	})
+
+	pods := client.CoreV1().Pods("default")
+	pod, err := pods.Get(ctx, "prototype-0", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod.Status.Phase = v1.PodFailed
+	if pod, err = pods.UpdateStatus(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case uid := <-deleted:
+		if uid != "prototype-0-uid" {
+			t.Errorf("onDelete got UID %s, want prototype-0-uid", uid)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("onDelete wasn't called for a failed pod")
+	}
+	if n := m.Count(); n != 0 {
+		t.Errorf("%d members after the pod failed, want 0", n)
+	}
+
+	// Further updates and the deletion of the already-failed pod mustn't evict it again
+	pod.Labels["revision"] = "2"
+	if _, err := pods.Update(ctx, pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pods.Delete(ctx, "prototype-0", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	// The informer delivers events in order, so once a later pod shows up the deletion has been handled
+	if _, err := pods.Create(ctx, newPod("default", "prototype-1", map[string]string{"name": "prototype"}), metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for m.Count() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("prototype-1 was never added")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := deletes.Load(); n != 1 {
+		t.Errorf("onDelete called %d times, want 1", n)
+	}
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	electionsEntered = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "controller_elections_entered_total",
+		Help: "Number of device elections this controller has entered.",
+	})
+	leadershipWins = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "controller_leadership_wins_total",
+		Help: "Number of times this controller became leader of a device election.",
+	})
+	configPushes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_device_config_pushes_total",
+		Help: "Number of device config pushes, by result.",
+	}, []string{"result"})
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_http_requests_total",
+		Help: "Number of HTTP requests handled, by route and status code.",
+	}, []string{"path", "status"})
+)
+
+func init() {
+	prometheus.MustRegister(electionsEntered, leadershipWins, configPushes, httpRequests)
+}
+
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+func ElectionEntered() {
+	electionsEntered.Inc()
+}
+
+func LeadershipWon() {
+	leadershipWins.Inc()
+}
+
+func ConfigPushed(err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	configPushes.WithLabelValues(result).Inc()
+}
+
+func HTTPRequest(path string, status int) {
+	httpRequests.WithLabelValues(path, strconv.Itoa(status)).Inc()
+}
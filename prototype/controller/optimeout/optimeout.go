@@ -0,0 +1,32 @@
+// Package optimeout bounds individual Atomix calls so that a request hung during a
+// partition fails with a deadline error instead of blocking its caller forever.
+package optimeout
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultTimeout = 5 * time.Second
+
+var timeout atomic.Int64
+
+func init() {
+	timeout.Store(int64(DefaultTimeout))
+}
+
+// Set changes the per-operation timeout. It should be called once at startup.
+func Set(d time.Duration) {
+	timeout.Store(int64(d))
+}
+
+func Get() time.Duration {
+	return time.Duration(timeout.Load())
+}
+
+// WithTimeout derives a context for a single Atomix operation. Streams that are
+// meant to stay open (Watch, Events) should keep using the parent context.
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, Get())
+}